@@ -11,6 +11,7 @@ require (
 	github.com/golang-jwt/jwt/v4 v4.5.0
 	github.com/google/uuid v1.3.1
 	github.com/mattn/go-sqlite3 v1.14.17
+	github.com/miekg/dns v1.1.55
 	github.com/natefinch/lumberjack v2.0.0+incompatible
 	github.com/rs/cors v1.10.1
 	github.com/scaleway/scaleway-sdk-go v1.0.0-beta.17
@@ -96,7 +97,6 @@ require (
 	github.com/liquidweb/liquidweb-cli v0.6.9 // indirect
 	github.com/liquidweb/liquidweb-go v1.6.3 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
-	github.com/miekg/dns v1.1.55 // indirect
 	github.com/mimuret/golang-iij-dpf v0.9.1 // indirect
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
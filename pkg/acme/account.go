@@ -30,9 +30,9 @@ type Account struct {
 	Contact   []string   `json:"contact"`
 	CreatedAt timeString `json:"createdAt,omitempty"` // non-standard field
 	Location  *string    `json:"-"`                   // omit because it is in the header
+	Orders    string     `json:"orders,omitempty"`
 	// -- also available but not in use
 	// JsonWebKey jsonWebKey `json:"key"`
-	// Orders     string     `json:"orders"`
 	// InitialIP  string     `json:"initialIp"`
 }
 
@@ -133,6 +133,12 @@ func (service *Service) UpdateAccount(payload UpdateAccountPayload, accountKey A
 	return response, nil
 }
 
+// GetAccount fetches the current state of the account by posting an empty
+// update to the kid of the account
+func (service *Service) GetAccount(accountKey AccountKey) (response Account, err error) {
+	return service.UpdateAccount(UpdateAccountPayload{}, accountKey)
+}
+
 // DeactivateAccount posts deactivated status to the ACME account
 // Once deactivated, accounts cannot be re-enabled. This action is DANGEROUS
 // and should only be done when there is a complete understanding of the repurcussions.
@@ -0,0 +1,69 @@
+package acme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrAccountOrdersUnsupported = errors.New("acme server did not provide an orders url for the account")
+
+// maxOrdersListPages is a safety limit to stop a misbehaving server from keeping
+// the client paging forever
+const maxOrdersListPages = 1000
+
+// ordersList is the ACME orders list object (see: rfc8555 s 7.1.2.1)
+type ordersList struct {
+	Orders []string `json:"orders"`
+}
+
+// GetAccountOrders fetches the account's orders list and returns the URLs of all of
+// the orders on it. If the server paginates the list, all pages are fetched. Note the
+// server is only required to list orders that are pending, ready, processing, or
+// were valid within a server defined period of time.
+func (service *Service) GetAccountOrders(accountKey AccountKey) (orderUrls []string, err error) {
+	// get the orders url from the account object
+	account, err := service.GetAccount(accountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Orders == "" {
+		return nil, ErrAccountOrdersUnsupported
+	}
+
+	orderUrls = []string{}
+	seenPages := make(map[string]struct{})
+
+	for pageUrl := account.Orders; pageUrl != ""; {
+		// avoid loops and unbounded paging
+		if _, seen := seenPages[pageUrl]; seen {
+			break
+		}
+		if len(seenPages) >= maxOrdersListPages {
+			return nil, fmt.Errorf("acme orders list exceeded %d pages", maxOrdersListPages)
+		}
+		seenPages[pageUrl] = struct{}{}
+
+		// POST-as-GET
+		bodyBytes, headers, err := service.postAsGet(pageUrl, accountKey)
+		if err != nil {
+			return nil, err
+		}
+
+		var page ordersList
+		err = json.Unmarshal(bodyBytes, &page)
+		if err != nil {
+			return nil, err
+		}
+		orderUrls = append(orderUrls, page.Orders...)
+
+		// next page, if any (see: rfc8555 s 7.1.2.1)
+		pageUrl = ""
+		if next := linkUrls(headers, "next"); len(next) > 0 {
+			pageUrl = next[0]
+		}
+	}
+
+	return orderUrls, nil
+}
@@ -2,8 +2,11 @@ package acme
 
 import (
 	"encoding/json"
+	"errors"
 )
 
+var errNewAuthzUnsupported = errors.New("acme server does not support pre-authorization (newAuthz)")
+
 // ACME authorization response
 type Authorization struct {
 	Identifier Identifier  `json:"identifier"` // see orders
@@ -11,6 +14,12 @@ type Authorization struct {
 	Expires    timeString  `json:"expires"`
 	Challenges []Challenge `json:"challenges"`
 	Wildcard   bool        `json:"wildcard,omitempty"`
+	Location   string      `json:"-"` // omit because it is in the header (newAuthz only)
+}
+
+// NewAuthzPayload is the payload to post to ACME newAuthz (see: rfc8555 s 7.4.1)
+type NewAuthzPayload struct {
+	Identifier Identifier `json:"identifier"`
 }
 
 // Account response decoder
@@ -23,6 +32,37 @@ func unmarshalAuthorization(bodyBytes []byte) (response Authorization, err error
 	return response, nil
 }
 
+// SupportsPreAuthorization returns true if the acme server supports pre-authorization
+// (i.e. its directory includes newAuthz)
+func (service *Service) SupportsPreAuthorization() bool {
+	return service.dir.NewAuthz != ""
+}
+
+// NewAuthz posts a secure message to the NewAuthz URL of the directory to create a
+// new authorization for the specified identifier
+func (service *Service) NewAuthz(payload NewAuthzPayload, accountKey AccountKey) (response Authorization, err error) {
+	if !service.SupportsPreAuthorization() {
+		return Authorization{}, errNewAuthzUnsupported
+	}
+
+	// post new-authz
+	bodyBytes, headers, err := service.postToUrlSigned(payload, service.dir.NewAuthz, accountKey)
+	if err != nil {
+		return Authorization{}, err
+	}
+
+	// unmarshal response
+	response, err = unmarshalAuthorization(bodyBytes)
+	if err != nil {
+		return Authorization{}, err
+	}
+
+	// authorization location (url) isn't part of the JSON response, add it from the header.
+	response.Location = headers.Get("Location")
+
+	return response, nil
+}
+
 // GetAuth does a POST-as-GET to feth an authorization object
 func (service *Service) GetAuth(authUrl string, accountKey AccountKey) (response Authorization, err error) {
 
@@ -0,0 +1,98 @@
+package acme
+
+import (
+	"time"
+)
+
+// AutoRenewal is the auto-renewal object of a Short-Term, Automatically Renewed (STAR)
+// order. It is included in the newOrder payload to request a STAR order and the acme
+// server returns it as part of the order (see: rfc8739 s 3.1.1).
+type AutoRenewal struct {
+	StartDate           *timeString `json:"start-date,omitempty"`
+	EndDate             timeString  `json:"end-date"`
+	Lifetime            int         `json:"lifetime"`                  // seconds
+	LifetimeAdjust      int         `json:"lifetime-adjust,omitempty"` // seconds
+	AllowCertificateGet bool        `json:"allow-certificate-get,omitempty"`
+}
+
+// autoRenewalMeta is the auto-renewal object of the directory meta, its presence
+// indicates the acme server supports STAR orders (see: rfc8739 s 3.1.3)
+type autoRenewalMeta struct {
+	MinLifetime         int  `json:"min-lifetime"` // seconds
+	MaxDuration         int  `json:"max-duration"` // seconds
+	AllowCertificateGet bool `json:"allow-certificate-get"`
+}
+
+// NewAutoRenewal returns an AutoRenewal to request a STAR order that issues certificates
+// valid for lifetime (pre-dated by lifetimeAdjust) starting at startDate, and stops
+// renewing at endDate. If startDate is zero, the order starts as soon as it is authorized.
+func NewAutoRenewal(startDate time.Time, endDate time.Time, lifetime time.Duration, lifetimeAdjust time.Duration) *AutoRenewal {
+	ar := &AutoRenewal{
+		EndDate:        timeString(endDate.UTC().Format(time.RFC3339)),
+		Lifetime:       int(lifetime.Seconds()),
+		LifetimeAdjust: int(lifetimeAdjust.Seconds()),
+	}
+
+	if !startDate.IsZero() {
+		start := timeString(startDate.UTC().Format(time.RFC3339))
+		ar.StartDate = &start
+	}
+
+	return ar
+}
+
+// StartUnixTime returns the unix time of the AutoRenewal's start-date, or 0 if
+// the start-date is not set
+func (ar *AutoRenewal) StartUnixTime() int {
+	if ar == nil {
+		return 0
+	}
+
+	return ar.StartDate.ToUnixTime()
+}
+
+// EndUnixTime returns the unix time of the AutoRenewal's end-date
+func (ar *AutoRenewal) EndUnixTime() int {
+	if ar == nil {
+		return 0
+	}
+
+	return ar.EndDate.ToUnixTime()
+}
+
+// SupportsAutoRenewal returns true if the acme server supports STAR orders
+func (service *Service) SupportsAutoRenewal() bool {
+	return service.dir.Meta.AutoRenewal != nil
+}
+
+// AutoRenewalValid returns true if the acme server supports STAR orders with the specified
+// certificate lifetime and lifetime adjustment (pre-dating). duration is the time from the
+// order being placed until the end date and startOffset is the time from the order being
+// placed until the start date. The start date must be at least one lifetime before the
+// end date.
+func (service *Service) AutoRenewalValid(lifetime time.Duration, duration time.Duration, startOffset time.Duration, lifetimeAdjust time.Duration) bool {
+	meta := service.dir.Meta.AutoRenewal
+	if meta == nil {
+		return false
+	}
+
+	if lifetime <= 0 || startOffset < 0 || lifetimeAdjust < 0 || lifetimeAdjust >= lifetime {
+		return false
+	}
+
+	// time certificates will be issued for (end-date - start-date)
+	duration -= startOffset
+	if duration < lifetime {
+		return false
+	}
+
+	// zero value means the server did not specify a limit
+	if meta.MinLifetime > 0 && lifetime < time.Duration(meta.MinLifetime)*time.Second {
+		return false
+	}
+	if meta.MaxDuration > 0 && duration > time.Duration(meta.MaxDuration)*time.Second {
+		return false
+	}
+
+	return true
+}
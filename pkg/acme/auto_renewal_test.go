@@ -0,0 +1,311 @@
+package acme
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"legocerthub-backend/pkg/acme/nonces"
+	"legocerthub-backend/pkg/httpclient"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// starStubServer is a minimal acme server that supports STAR orders. Every time
+// renew is called, it issues a new certificate at the star-certificate url.
+type starStubServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	newOrderBody  NewOrderPayload
+	certPem       string
+	certDownloads atomic.Int32
+}
+
+// makeTestCertPem returns a self signed pem certificate valid from notBefore to notAfter
+func makeTestCertPem(t *testing.T, notBefore time.Time, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "star.example.com"},
+		DNSNames:     []string{"star.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// renew issues a new certificate at the star-certificate url
+func (stub *starStubServer) renew(t *testing.T) string {
+	certPem := makeTestCertPem(t, time.Now(), time.Now().Add(24*time.Hour))
+
+	stub.mu.Lock()
+	stub.certPem = certPem
+	stub.mu.Unlock()
+
+	return certPem
+}
+
+// newStarStubServer starts the stub acme server
+func newStarStubServer(t *testing.T) *starStubServer {
+	stub := &starStubServer{}
+
+	mux := http.NewServeMux()
+	stub.Server = httptest.NewServer(mux)
+	t.Cleanup(stub.Close)
+
+	nonce := atomic.Int64{}
+	setNonce := func(w http.ResponseWriter) {
+		w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", nonce.Add(1)))
+	}
+
+	// decodePayload returns the payload of a signed message
+	decodePayload := func(r *http.Request) []byte {
+		var msg acmeSignedMessage
+		err := json.NewDecoder(r.Body).Decode(&msg)
+		if err != nil {
+			t.Errorf("stub acme: failed to decode signed message (%s)", err)
+			return nil
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(msg.Payload)
+		if err != nil {
+			t.Errorf("stub acme: failed to decode payload (%s)", err)
+			return nil
+		}
+		return payload
+	}
+
+	mux.HandleFunc("/directory", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"newNonce": "%[1]s/new-nonce",
+			"newAccount": "%[1]s/new-account",
+			"newOrder": "%[1]s/new-order",
+			"revokeCert": "%[1]s/revoke-cert",
+			"keyChange": "%[1]s/key-change",
+			"meta": {
+				"auto-renewal": {
+					"min-lifetime": 86400,
+					"max-duration": 31536000,
+					"allow-certificate-get": true
+				}
+			}
+		}`, stub.URL)
+	})
+
+	mux.HandleFunc("/new-nonce", func(w http.ResponseWriter, r *http.Request) {
+		setNonce(w)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/new-order", func(w http.ResponseWriter, r *http.Request) {
+		var payload NewOrderPayload
+		err := json.Unmarshal(decodePayload(r), &payload)
+		if err != nil {
+			t.Errorf("stub acme: failed to unmarshal new order payload (%s)", err)
+		}
+		stub.mu.Lock()
+		stub.newOrderBody = payload
+		stub.mu.Unlock()
+
+		// echo back the requested auto-renewal, with the start date set if it wasn't
+		autoRenewal := *payload.AutoRenewal
+		if autoRenewal.StartDate == nil {
+			start := timeString(time.Now().UTC().Format(time.RFC3339))
+			autoRenewal.StartDate = &start
+		}
+
+		starCertUrl := stub.URL + "/star-cert/1"
+
+		setNonce(w)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", stub.URL+"/order/1")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Order{
+			Status:          "valid",
+			Identifiers:     payload.Identifiers,
+			Authorizations:  []string{stub.URL + "/authz/1"},
+			Finalize:        stub.URL + "/order/1/finalize",
+			AutoRenewal:     &autoRenewal,
+			StarCertificate: &starCertUrl,
+		})
+	})
+
+	mux.HandleFunc("/star-cert/1", func(w http.ResponseWriter, r *http.Request) {
+		stub.certDownloads.Add(1)
+
+		stub.mu.Lock()
+		certPem := stub.certPem
+		stub.mu.Unlock()
+
+		setNonce(w)
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		_, _ = io.WriteString(w, certPem)
+	})
+
+	return stub
+}
+
+// newTestService makes an acme Service for the stub server
+func newTestService(t *testing.T, dirUri string) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	httpClient := httpclient.New("lego-test")
+	dir, err := FetchAcmeDirectory(httpClient, dirUri)
+	if err != nil {
+		t.Fatalf("failed to fetch stub directory (%s)", err)
+	}
+
+	service := &Service{
+		shutdownCtx: ctx,
+		logger:      zap.NewNop().Sugar(),
+		httpClient:  httpClient,
+		dirUri:      dirUri,
+		dir:         &dir,
+		rateLimits:  newRateLimits(),
+	}
+	service.nonceManager = nonces.NewManager(httpClient, &service.dir.NewNonce, ctx, wg)
+
+	return service
+}
+
+// autoRenewalValidTest is a STAR order's parameters and if the stub server (min-lifetime
+// 1 day, max-duration 1 year) should accept them
+type autoRenewalValidTest struct {
+	name           string
+	lifetime       time.Duration
+	duration       time.Duration
+	startOffset    time.Duration
+	lifetimeAdjust time.Duration
+	valid          bool
+}
+
+var autoRenewalValidTests = []autoRenewalValidTest{
+	{name: "within server limits", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, valid: true},
+	{name: "lifetime below min-lifetime", lifetime: time.Hour, duration: 30 * 24 * time.Hour, valid: false},
+	{name: "duration above max-duration", lifetime: 48 * time.Hour, duration: 2 * 365 * 24 * time.Hour, valid: false},
+	{name: "duration shorter than lifetime", lifetime: 48 * time.Hour, duration: 24 * time.Hour, valid: false},
+	{name: "start offset", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, startOffset: 7 * 24 * time.Hour, valid: true},
+	{name: "start offset shortens max-duration", lifetime: 48 * time.Hour, duration: 370 * 24 * time.Hour, startOffset: 10 * 24 * time.Hour, valid: true},
+	{name: "start after end", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, startOffset: 31 * 24 * time.Hour, valid: false},
+	{name: "start less than a lifetime before end", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, startOffset: 29 * 24 * time.Hour, valid: false},
+	{name: "negative start offset", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, startOffset: -time.Hour, valid: false},
+	{name: "lifetime adjust", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, lifetimeAdjust: time.Hour, valid: true},
+	{name: "lifetime adjust not less than lifetime", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, lifetimeAdjust: 48 * time.Hour, valid: false},
+	{name: "negative lifetime adjust", lifetime: 48 * time.Hour, duration: 30 * 24 * time.Hour, lifetimeAdjust: -time.Hour, valid: false},
+}
+
+func TestAcme_StarOrder(t *testing.T) {
+	stub := newStarStubServer(t)
+	service := newTestService(t, stub.URL+"/directory")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountKey := AccountKey{Key: key, Kid: stub.URL + "/account/1"}
+
+	// server support
+	if !service.SupportsAutoRenewal() {
+		t.Fatal("stub server with auto-renewal meta should support star orders")
+	}
+	for _, test := range autoRenewalValidTests {
+		valid := service.AutoRenewalValid(test.lifetime, test.duration, test.startOffset, test.lifetimeAdjust)
+		if valid != test.valid {
+			t.Errorf("auto-renewal valid test case '%s' returned %t, expected %t", test.name, valid, test.valid)
+		}
+	}
+
+	// create STAR order
+	startDate := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	endDate := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	order, err := service.NewOrder(NewOrderPayload{
+		Identifiers: IdentifierSlice{NewIdentifier("star.example.com")},
+		AutoRenewal: NewAutoRenewal(startDate, endDate, 48*time.Hour, time.Hour),
+	}, accountKey)
+	if err != nil {
+		t.Fatalf("failed to create star order (%s)", err)
+	}
+
+	stub.mu.Lock()
+	sent := stub.newOrderBody
+	stub.mu.Unlock()
+	if sent.AutoRenewal == nil {
+		t.Fatal("new order payload did not include auto-renewal")
+	}
+	if sent.AutoRenewal.Lifetime != 48*60*60 {
+		t.Errorf("new order auto-renewal lifetime is %d, expected %d", sent.AutoRenewal.Lifetime, 48*60*60)
+	}
+	if sent.AutoRenewal.LifetimeAdjust != 60*60 {
+		t.Errorf("new order auto-renewal lifetime-adjust is %d, expected %d", sent.AutoRenewal.LifetimeAdjust, 60*60)
+	}
+	if sent.AutoRenewal.StartUnixTime() != int(startDate.Unix()) {
+		t.Errorf("new order auto-renewal start-date is %d, expected %d", sent.AutoRenewal.StartUnixTime(), startDate.Unix())
+	}
+	if sent.AutoRenewal.EndUnixTime() != int(endDate.Unix()) {
+		t.Errorf("new order auto-renewal end-date is %d, expected %d", sent.AutoRenewal.EndUnixTime(), endDate.Unix())
+	}
+
+	if order.Location != stub.URL+"/order/1" {
+		t.Errorf("star order location is %s, expected %s", order.Location, stub.URL+"/order/1")
+	}
+	if order.StarCertificate == nil || *order.StarCertificate != stub.URL+"/star-cert/1" {
+		t.Fatalf("star order star-certificate url is %v, expected %s", order.StarCertificate, stub.URL+"/star-cert/1")
+	}
+	if order.AutoRenewal.StartUnixTime() != int(startDate.Unix()) || order.AutoRenewal.EndUnixTime() != int(endDate.Unix()) {
+		t.Errorf("star order auto-renewal dates not returned (start %d, end %d)", order.AutoRenewal.StartUnixTime(), order.AutoRenewal.EndUnixTime())
+	}
+
+	// poll star-certificate url, the cert only changes when the server renews it
+	firstPem := stub.renew(t)
+	for i := 0; i < 2; i++ {
+		pemChains, err := service.DownloadCertificateChains(*order.StarCertificate, accountKey)
+		if err != nil {
+			t.Fatalf("failed to download star certificate (%s)", err)
+		}
+		if pemChains[0] != firstPem {
+			t.Errorf("star certificate poll %d did not return the current certificate", i)
+		}
+	}
+
+	secondPem := stub.renew(t)
+	pemChains, err := service.DownloadCertificateChains(*order.StarCertificate, accountKey)
+	if err != nil {
+		t.Fatalf("failed to download renewed star certificate (%s)", err)
+	}
+	if pemChains[0] != secondPem {
+		t.Error("star certificate poll after renewal did not return the new certificate")
+	}
+
+	if downloads := stub.certDownloads.Load(); downloads != 3 {
+		t.Errorf("star certificate url polled %d times, expected 3", downloads)
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"encoding/json"
 )
 
-// Define challenge types (per RFC 8555)
+// Define challenge types (per RFC 8555 and RFC 8737)
 type ChallengeType string
 
 const (
 	UnknownChallengeType ChallengeType = ""
 
-	ChallengeTypeHttp01 ChallengeType = "http-01"
-	ChallengeTypeDns01  ChallengeType = "dns-01"
+	ChallengeTypeHttp01    ChallengeType = "http-01"
+	ChallengeTypeDns01     ChallengeType = "dns-01"
+	ChallengeTypeTlsAlpn01 ChallengeType = "tls-alpn-01"
 )
 
 // ACME challenge object
@@ -1,7 +1,21 @@
 package acme
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"time"
 )
 
 // ValidationResourceDns01 returns the dnsRecord name and value to provision
@@ -19,3 +33,127 @@ func ValidationResourceDns01(domain, keyAuth string) (dnsRecordName, dnsRecordVa
 
 	return dnsRecordName, dnsRecordValue
 }
+
+// TlsAlpn01Protocol is the ALPN protocol name used for TlsAlpn01 challenges (see: rfc8737 s 6.2)
+const TlsAlpn01Protocol = "acme-tls/1"
+
+// oidAcmeIdentifier is the id-pe-acmeIdentifier extension OID (see: rfc8737 s 6.1)
+var oidAcmeIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+// ValidationResourceTlsAlpn01 returns the self-signed certificate to serve in response
+// to a TlsAlpn01 challenge for a given domain and keyAuth. The certificate contains
+// only the domain as a SAN and the critical acmeIdentifier extension containing the
+// sha256 digest of the key authorization (see: rfc8737 s 3). If domain is an IP
+// address, the SAN is an IP address SAN instead (see: rfc8738 s 6).
+func ValidationResourceTlsAlpn01(domain, keyAuth string) (*tls.Certificate, error) {
+	// acmeIdentifier extension value is the ASN.1 OCTET STRING of the key auth digest
+	keyAuthDigest := sha256.Sum256([]byte(keyAuth))
+	extValue, err := asn1.Marshal(keyAuthDigest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	// key for the self-signed cert
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName: "LeGo CertHub tls-alpn-01 challenge",
+		},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(7 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		ExtraExtensions: []pkix.Extension{
+			{
+				Id:       oidAcmeIdentifier,
+				Critical: true,
+				Value:    extValue,
+			},
+		},
+	}
+
+	// SAN
+	ip, err := netip.ParseAddr(domain)
+	if err == nil {
+		template.IPAddresses = []net.IP{ip.AsSlice()}
+	} else {
+		template.DNSNames = []string{domain}
+	}
+
+	derCert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{derCert},
+		PrivateKey:  key,
+	}, nil
+}
+
+// CheckTlsAlpn01Certificate returns an error if cert is not a valid response to a
+// TlsAlpn01 challenge for the specified keyAuth (i.e. it does not contain the critical
+// acmeIdentifier extension with the key authorization digest, see: rfc8737 s 3)
+func CheckTlsAlpn01Certificate(cert *x509.Certificate, keyAuth string) error {
+	keyAuthDigest := sha256.Sum256([]byte(keyAuth))
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidAcmeIdentifier) {
+			continue
+		}
+
+		if !ext.Critical {
+			return errors.New("tls-alpn-01 certificate acmeIdentifier extension is not critical")
+		}
+
+		var digest []byte
+		rest, err := asn1.Unmarshal(ext.Value, &digest)
+		if err != nil || len(rest) != 0 {
+			return errors.New("tls-alpn-01 certificate acmeIdentifier extension is malformed")
+		}
+
+		if subtle.ConstantTimeCompare(digest, keyAuthDigest[:]) != 1 {
+			return errors.New("tls-alpn-01 certificate acmeIdentifier does not match key authorization")
+		}
+
+		return nil
+	}
+
+	return errors.New("tls-alpn-01 certificate is missing the acmeIdentifier extension")
+}
+
+// TlsAlpn01ServerName returns the TLS SNI value the ACME server will send when
+// validating a TlsAlpn01 challenge for the specified domain. For domains this is
+// the domain itself. For IP addresses this is the reverse DNS name of the address
+// (see: rfc8738 s 6).
+func TlsAlpn01ServerName(domain string) string {
+	ip, err := netip.ParseAddr(domain)
+	if err != nil {
+		return domain
+	}
+
+	// IPv4 (e.g. 4.3.2.1.in-addr.arpa)
+	if ip.Is4() {
+		b := ip.As4()
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", b[3], b[2], b[1], b[0])
+	}
+
+	// IPv6 (each nibble reversed, e.g. 1.0.0.0. ... .8.b.d.0.1.0.0.2.ip6.arpa)
+	b := ip.As16()
+	name := ""
+	for i := len(b) - 1; i >= 0; i-- {
+		name += fmt.Sprintf("%x.%x.", b[i]&0x0f, b[i]>>4)
+	}
+	return name + "ip6.arpa"
+}
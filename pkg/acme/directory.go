@@ -7,6 +7,7 @@ import (
 	"io"
 	"legocerthub-backend/pkg/httpclient"
 	"legocerthub-backend/pkg/randomness"
+	"maps"
 	"reflect"
 	"sync"
 	"time"
@@ -20,17 +21,22 @@ var (
 
 // acmeDirectory struct holds ACME directory object
 type directory struct {
-	NewNonce   string `json:"newNonce"`
-	NewAccount string `json:"newAccount"`
-	NewOrder   string `json:"newOrder"`
-	NewAuthz   string `json:"newAuthz"`
-	RevokeCert string `json:"revokeCert"`
-	KeyChange  string `json:"keyChange"`
-	Meta       struct {
+	NewNonce    string `json:"newNonce"`
+	NewAccount  string `json:"newAccount"`
+	NewOrder    string `json:"newOrder"`
+	NewAuthz    string `json:"newAuthz"`
+	RevokeCert  string `json:"revokeCert"`
+	KeyChange   string `json:"keyChange"`
+	RenewalInfo string `json:"renewalInfo"` // optional, see: rfc9773
+	Meta        struct {
 		TermsOfService          string   `json:"termsOfService"`
 		Website                 string   `json:"website"`
 		CaaIdentities           []string `json:"caaIdentities"`
 		ExternalAccountRequired bool     `json:"externalAccountRequired"`
+		// optional, see: draft-ietf-acme-profiles
+		Profiles map[string]string `json:"profiles"`
+		// optional, see: rfc8739
+		AutoRenewal *autoRenewalMeta `json:"auto-renewal"`
 	} `json:"meta"`
 }
 
@@ -184,3 +190,21 @@ func (service *Service) TosUrl() string {
 func (service *Service) RequiresEAB() bool {
 	return service.dir.Meta.ExternalAccountRequired
 }
+
+// Profiles returns the certificate profiles advertised by the acme server, mapped
+// from profile name to the server's description of the profile
+func (service *Service) Profiles() map[string]string {
+	profiles := maps.Clone(service.dir.Meta.Profiles)
+	if profiles == nil {
+		profiles = make(map[string]string)
+	}
+
+	return profiles
+}
+
+// SupportsProfile returns true if the acme server advertises the specified
+// certificate profile
+func (service *Service) SupportsProfile(profile string) bool {
+	_, exists := service.dir.Meta.Profiles[profile]
+	return exists
+}
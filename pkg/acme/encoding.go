@@ -64,11 +64,8 @@ func (ats *timeString) ToUnixTime() (unixTime int) {
 		return 0
 	}
 
-	// RFC3339
-	layout := "2006-01-02T15:04:05Z"
-
-	// Parse
-	time, err := time.Parse(layout, string(*ats))
+	// Parse (RFC3339, fractional seconds are accepted when parsing)
+	time, err := time.Parse(time.RFC3339, string(*ats))
 	if err != nil {
 		return 0
 	}
@@ -3,18 +3,58 @@ package acme
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+)
+
+// ACME error types that receive special handling (see: rfc8555 s 6.7)
+const (
+	ErrTypeBadNonce    = "urn:ietf:params:acme:error:badNonce"
+	ErrTypeRateLimited = "urn:ietf:params:acme:error:rateLimited"
 )
 
 // ACME error
 type Error struct {
-	Status int    `json:"status"`
-	Type   string `json:"type"`
-	Detail string `json:"detail"`
+	Status      int          `json:"status"`
+	Type        string       `json:"type"`
+	Detail      string       `json:"detail"`
+	Subproblems []Subproblem `json:"subproblems,omitempty"`
+
+	// RetryAfter is populated from the Retry-After header of a rateLimited
+	// response; it is not part of the ACME error object
+	RetryAfter time.Time `json:"-"`
+}
+
+// Subproblem is an ACME error that relates to a specific identifier (see:
+// rfc8555 s 6.7.1)
+type Subproblem struct {
+	Type       string      `json:"type"`
+	Detail     string      `json:"detail"`
+	Identifier *Identifier `json:"identifier,omitempty"`
 }
 
 // Error() implements the error interface
 func (e *Error) Error() string {
-	return fmt.Sprintf("status: %d; type: %s; detail: %s", e.Status, e.Type, e.Detail)
+	msg := fmt.Sprintf("status: %d; type: %s; detail: %s", e.Status, e.Type, e.Detail)
+
+	if len(e.Subproblems) > 0 {
+		subproblems := []string{}
+		for _, sp := range e.Subproblems {
+			identifier := "none"
+			if sp.Identifier != nil {
+				identifier = sp.Identifier.Value
+			}
+			subproblems = append(subproblems, fmt.Sprintf("%s: %s (%s)", identifier, sp.Type, sp.Detail))
+		}
+		msg += fmt.Sprintf("; subproblems: [%s]", strings.Join(subproblems, ", "))
+	}
+
+	return msg
+}
+
+// IsType returns true if the Error is of the specified ACME error type
+func (e *Error) IsType(errType string) bool {
+	return e != nil && e.Type == errType
 }
 
 // unmarshalErrorResponse attempts to unmarshal into the error response object. If
@@ -27,6 +67,12 @@ func unmarshalErrorResponse(bodyBytes []byte) (errResponse *Error) {
 		return nil
 	}
 
+	// problem documents always have a type (other objects, e.g. an account's
+	// orders list, may decode without error but are not an error)
+	if errResponse.Type == "" {
+		return nil
+	}
+
 	// if we did get an error response from ACME
 	return errResponse
 }
@@ -55,6 +101,7 @@ func NewAcmeError(acmeErrorJson *string) (e *Error) {
 		return nil
 	}
 
+	e = new(Error)
 	err := json.Unmarshal([]byte(*acmeErrorJson), e)
 	if err != nil {
 		// if unmarshal fails, return nil
@@ -1,20 +1,33 @@
 package acme
 
+import "net/netip"
+
 // Identifier is the ACME Identifier object
 type Identifier struct {
 	Type  IdentifierType `json:"type"`
 	Value string         `json:"value"`
 }
 
-// Define ACME identifier types (per RFC 8555 9.7.7)
+// Define ACME identifier types (per RFC 8555 9.7.7 and RFC 8738)
 type IdentifierType string
 
 const (
 	UnknownIdentifierType IdentifierType = ""
 
 	IdentifierTypeDns = "dns"
+	IdentifierTypeIp  = "ip"
 )
 
+// NewIdentifier returns an Identifier for the specified value. If the value
+// is an IP address, the Identifier is of type ip, otherwise it is of type dns.
+func NewIdentifier(value string) Identifier {
+	if _, err := netip.ParseAddr(value); err == nil {
+		return Identifier{Type: IdentifierTypeIp, Value: value}
+	}
+
+	return Identifier{Type: IdentifierTypeDns, Value: value}
+}
+
 // IdentifierSlice is a slice of Identifier
 type IdentifierSlice []Identifier
 
@@ -30,3 +43,16 @@ func (ids *IdentifierSlice) DnsIdentifiers() []string {
 
 	return s
 }
+
+// IpIdentifiers returns a slice of the value strings of the ip type Identifiers
+func (ids *IdentifierSlice) IpIdentifiers() []string {
+	var s []string
+
+	for _, id := range *ids {
+		if id.Type == IdentifierTypeIp {
+			s = append(s, id.Value)
+		}
+	}
+
+	return s
+}
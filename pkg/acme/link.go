@@ -0,0 +1,44 @@
+package acme
+
+import (
+	"net/http"
+	"strings"
+)
+
+// linkUrls returns the target URLs of all Link headers with the specified relation
+// type (see: rfc8288 s 3). A single Link header may contain multiple comma separated
+// links and a link may have multiple space separated relation types.
+func linkUrls(headers http.Header, rel string) []string {
+	urls := []string{}
+
+	for _, headerVal := range headers.Values("Link") {
+		for _, link := range strings.Split(headerVal, ",") {
+			// link format: <url>; param1=val1; param2="val2"
+			parts := strings.Split(link, ";")
+
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+
+			// check params for matching rel
+			for _, param := range parts[1:] {
+				key, val, found := strings.Cut(strings.TrimSpace(param), "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+
+				val = strings.Trim(strings.TrimSpace(val), "\"")
+				for _, oneRel := range strings.Fields(val) {
+					if strings.EqualFold(oneRel, rel) {
+						urls = append(urls, target)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return urls
+}
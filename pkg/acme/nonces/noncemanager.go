@@ -1,29 +1,64 @@
 package nonces
 
 import (
+	"context"
 	"errors"
 	"io"
 	"legocerthub-backend/pkg/datatypes/ringbuffer"
 	"legocerthub-backend/pkg/httpclient"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Manager is the NonceManager
+// Manager is the NonceManager. It maintains a bounded pool of nonces that is
+// filled from ACME responses and, when it runs low, by prefetching in the
+// background.
 type Manager struct {
 	httpClient  *httpclient.Client
 	newNonceUrl *string
-	nonces      *ringbuffer.RingBuffer[string]
+	nonces      *ringbuffer.RingBuffer[savedNonce]
+	prefetch    chan struct{}
+
+	// metrics
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	fetches       atomic.Uint64
+	badNonces     atomic.Uint64
+	staleEvicted  atomic.Uint64
+	fetchFailures atomic.Uint64
 }
 
-// Manager buffer size
-const bufferSize = 32
+// savedNonce is a nonce along with the time it was received
+type savedNonce struct {
+	value      string
+	receivedAt time.Time
+}
+
+const (
+	// Manager buffer size
+	bufferSize = 32
+
+	// prefetch refills the buffer to prefetchTarget when it drops below
+	// prefetchLowWater
+	prefetchLowWater = 2
+	prefetchTarget   = 4
 
-// NewManager creates a new nonce manager
-func NewManager(client *httpclient.Client, nonceUrl *string) *Manager {
+	// nonces older than maxNonceAge are discarded instead of used as the
+	// acme server has likely already expired them
+	maxNonceAge = 5 * time.Minute
+)
+
+// NewManager creates a new nonce manager and starts its background prefetcher
+func NewManager(client *httpclient.Client, nonceUrl *string, shutdownCtx context.Context, shutdownWg *sync.WaitGroup) *Manager {
 	manager := new(Manager)
 
 	manager.httpClient = client
 	manager.newNonceUrl = nonceUrl
-	manager.nonces = ringbuffer.NewRingBuffer[string](bufferSize)
+	manager.nonces = ringbuffer.NewRingBuffer[savedNonce](bufferSize)
+	manager.prefetch = make(chan struct{}, 1)
+
+	manager.backgroundPrefetcher(shutdownCtx, shutdownWg)
 
 	return manager
 }
@@ -31,9 +66,12 @@ func NewManager(client *httpclient.Client, nonceUrl *string) *Manager {
 // fetchNonce gets a nonce from the manager's newNonceUrl
 // if fetching fails or the header does not contain a nonce,
 // an error is returned
-func (manager *Manager) fetchNonce() (nonce string, err error) {
-	response, err := manager.httpClient.Head(*manager.newNonceUrl)
+func (manager *Manager) fetchNonce(ctx context.Context) (nonce string, err error) {
+	manager.fetches.Add(1)
+
+	response, err := manager.httpClient.HeadWithContext(ctx, *manager.newNonceUrl)
 	if err != nil {
+		manager.fetchFailures.Add(1)
 		return "", err
 	}
 	defer response.Body.Close()
@@ -46,25 +84,84 @@ func (manager *Manager) fetchNonce() (nonce string, err error) {
 	// make sure the nonce isn't blank
 	nonce = response.Header.Get("Replay-Nonce")
 	if nonce == "" {
+		manager.fetchFailures.Add(1)
 		return "", errors.New("failed to fetch nonce, no value in header")
 	}
 
 	return nonce, nil
 }
 
-// Nonce returns the oldest nonce from the nonce buffer.
-// If the buffer cannot be read, a new nonce will be acquired by
-// fetching from the newNonceUrl
-func (manager *Manager) Nonce() (nonce string, err error) {
-	// try to read, if error fetch new
-	nonce, err = manager.nonces.Read()
+// backgroundPrefetcher starts a goroutine that refills the nonce buffer whenever
+// it is signaled that the buffer is running low
+func (manager *Manager) backgroundPrefetcher(shutdownCtx context.Context, shutdownWg *sync.WaitGroup) {
+	shutdownWg.Add(1)
+	go func() {
+		defer shutdownWg.Done()
+
+		for {
+			select {
+			case <-shutdownCtx.Done():
+				return
+
+			case <-manager.prefetch:
+				// can't fetch until the directory is loaded
+				if *manager.newNonceUrl == "" {
+					continue
+				}
+
+				for manager.nonces.Len() < prefetchTarget {
+					nonce, err := manager.fetchNonce(shutdownCtx)
+					if err != nil {
+						// give up until next signal
+						break
+					}
+
+					_ = manager.SaveNonce(nonce)
+				}
+			}
+		}
+	}()
+}
 
-	// if read failed, fetch from url
-	if err != nil {
-		return manager.fetchNonce()
+// signalPrefetch signals the prefetcher if the buffer is low. It never blocks.
+func (manager *Manager) signalPrefetch() {
+	if manager.nonces.Len() >= prefetchLowWater {
+		return
 	}
 
-	return nonce, nil
+	select {
+	case manager.prefetch <- struct{}{}:
+	default:
+		// prefetch already pending
+	}
+}
+
+// Nonce returns the oldest usable nonce from the nonce buffer. Stale nonces
+// are discarded. If the buffer is empty, a new nonce will be acquired by
+// fetching from the newNonceUrl, waiting no longer than ctx allows.
+func (manager *Manager) Nonce(ctx context.Context) (nonce string, err error) {
+	defer manager.signalPrefetch()
+
+	for {
+		// try to read, if error fetch new
+		saved, err := manager.nonces.Read()
+		if err != nil {
+			break
+		}
+
+		// discard stale
+		if time.Since(saved.receivedAt) > maxNonceAge {
+			manager.staleEvicted.Add(1)
+			continue
+		}
+
+		manager.hits.Add(1)
+		return saved.value, nil
+	}
+
+	// buffer empty, fetch from url
+	manager.misses.Add(1)
+	return manager.fetchNonce(ctx)
 }
 
 // SaveNonce saves the nonce string to the nonces buffer. If the
@@ -77,10 +174,40 @@ func (manager *Manager) SaveNonce(nonce string) (err error) {
 	}
 
 	// write new nonce and evict oldest if buffer is full
-	err = manager.nonces.Write(nonce, true)
+	err = manager.nonces.Write(savedNonce{value: nonce, receivedAt: time.Now()}, true)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// RecordBadNonce increments the count of badNonce errors received from the
+// acme server
+func (manager *Manager) RecordBadNonce() {
+	manager.badNonces.Add(1)
+}
+
+// Stats contains nonce manager metrics
+type Stats struct {
+	Pooled        int    `json:"pooled"`
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Fetches       uint64 `json:"fetches"`
+	FetchFailures uint64 `json:"fetch_failures"`
+	StaleEvicted  uint64 `json:"stale_evicted"`
+	BadNonces     uint64 `json:"bad_nonce_retries"`
+}
+
+// Stats returns the manager's current metrics
+func (manager *Manager) Stats() Stats {
+	return Stats{
+		Pooled:        manager.nonces.Len(),
+		Hits:          manager.hits.Load(),
+		Misses:        manager.misses.Load(),
+		Fetches:       manager.fetches.Load(),
+		FetchFailures: manager.fetchFailures.Load(),
+		StaleEvicted:  manager.staleEvicted.Load(),
+		BadNonces:     manager.badNonces.Load(),
+	}
+}
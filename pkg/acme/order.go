@@ -16,6 +16,12 @@ var errBadOrderPem = errors.New("pem returned from ACME server failed safety che
 type NewOrderPayload struct {
 	// notBefore and notAfter are optional and not implemented
 	Identifiers IdentifierSlice `json:"identifiers"`
+	// Replaces is the ARI CertID of the certificate this order replaces (see: rfc9773 s 5)
+	Replaces string `json:"replaces,omitempty"`
+	// Profile is the name of the certificate profile to use (see: draft-ietf-acme-profiles)
+	Profile string `json:"profile,omitempty"`
+	// AutoRenewal requests a STAR order (see: rfc8739 s 3.1.1)
+	AutoRenewal *AutoRenewal `json:"auto-renewal,omitempty"`
 }
 
 // LE response with order information
@@ -29,7 +35,10 @@ type Order struct {
 	Certificate    *string         `json:"certificate,omitempty"`
 	NotBefore      *timeString     `json:"notBefore,omitempty"`
 	NotAfter       *timeString     `json:"notAfter,omitempty"`
-	Location       string          `json:"-"` // omit because it is in the header
+	// STAR orders only (see: rfc8739 s 3.1.1)
+	AutoRenewal     *AutoRenewal `json:"auto-renewal,omitempty"`
+	StarCertificate *string      `json:"star-certificate,omitempty"`
+	Location        string       `json:"-"` // omit because it is in the header
 }
 
 // Account response decoder
@@ -85,8 +94,8 @@ func (service *Service) FinalizeOrder(finalizeUrl string, derCsr []byte, account
 	if service.logger.Level() == zapcore.DebugLevel {
 		csr, prettyErr := x509.ParseCertificateRequest(derCsr)
 		if prettyErr == nil {
-			// log CN and DNS names
-			service.logger.Debugf("attempting finalize using csr with common name: %s ; and dns name(s): %s", csr.Subject.CommonName, csr.DNSNames)
+			// log CN, DNS names, and IP addresses
+			service.logger.Debugf("attempting finalize using csr with common name: %s ; dns name(s): %s ; and ip address(es): %s", csr.Subject.CommonName, csr.DNSNames, csr.IPAddresses)
 
 			// Log full CSR
 			// prettyBytes, prettyErr := json.MarshalIndent(csr, "", "\t")
@@ -119,18 +128,51 @@ func (service *Service) FinalizeOrder(finalizeUrl string, derCsr []byte, account
 }
 
 // DownloadCertificate uses POST-as-GET to download a valid certificate from the specified
-// url.
+// url. Only the default chain is returned.
 func (service *Service) DownloadCertificate(certificateUrl string, accountKey AccountKey) (pemChain string, err error) {
+	pemChain, _, err = service.downloadCertificate(certificateUrl, accountKey)
+	return pemChain, err
+}
+
+// DownloadCertificateChains uses POST-as-GET to download a valid certificate from the
+// specified url along with all of the alternate chains the ACME server offers (see: RFC8555
+// s 7.4.2). The default chain is always the first element of the returned slice. If an
+// alternate chain fails to download, it is logged and omitted from the result.
+func (service *Service) DownloadCertificateChains(certificateUrl string, accountKey AccountKey) (pemChains []string, err error) {
+	defaultChain, altUrls, err := service.downloadCertificate(certificateUrl, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	pemChains = []string{defaultChain}
+
+	service.logger.Debugf("alternate download links: %s", altUrls)
+
+	for _, altUrl := range altUrls {
+		altChain, _, err := service.downloadCertificate(altUrl, accountKey)
+		if err != nil {
+			service.logger.Errorf("failed to download alternate certificate chain %s (%s)", altUrl, err)
+			continue
+		}
+
+		pemChains = append(pemChains, altChain)
+	}
+
+	return pemChains, nil
+}
+
+// downloadCertificate uses POST-as-GET to download a certificate chain from the specified
+// url and returns it along with the urls of any alternate chains
+func (service *Service) downloadCertificate(certificateUrl string, accountKey AccountKey) (pemChain string, alternateUrls []string, err error) {
 	// POST-as-GET
 	bodyBytes, headers, err := service.postAsGet(certificateUrl, accountKey)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	// this server only supports pem (application/pem-certificate-chain)
 	contentType := headers.Get("Content-type")
 	if contentType != "application/pem-certificate-chain" {
-		return "", errBadOrderPem
+		return "", nil, errBadOrderPem
 	}
 
 	// validate ACME server didn't return malicious pem (see: RFC8555 s 11.4)
@@ -141,7 +183,7 @@ func (service *Service) DownloadCertificate(certificateUrl string, accountKey Ac
 	// if there is never a begin, invalid pem
 	i := strings.Index(pemCheck, beginString)
 	if i == -1 {
-		return "", errBadOrderPem
+		return "", nil, errBadOrderPem
 	}
 
 	// check every begin to ensure it is followed by CERTIFICATE
@@ -149,20 +191,10 @@ func (service *Service) DownloadCertificate(certificateUrl string, accountKey Ac
 		pemCheck = pemCheck[(i + len(beginString)):]
 
 		if !strings.HasPrefix(pemCheck, mustBeFollowedBy) {
-			return "", errBadOrderPem
+			return "", nil, errBadOrderPem
 		}
 	}
 	// end - validate (RFC8555 s 11.4)
 
-	// log alternate links in debug
-	// TODO: maybe care about these at some point
-	// altLinks := []string{}
-	// for _, altLink := range headers.Values("Link") {
-	// 	if strings.HasSuffix(altLink, "rel=\"alternate\"") {
-	// 		altLinks = append(altLinks, altLink)
-	// 	}
-	// }
-	// service.logger.Debugf("alternate download links: %s", altLinks)
-
-	return string(bodyBytes), nil
+	return string(bodyBytes), linkUrls(headers, "alternate"), nil
 }
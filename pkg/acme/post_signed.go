@@ -2,11 +2,13 @@ package acme
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -33,6 +35,18 @@ type protectedHeader struct {
 	Url        string      `json:"url"`
 }
 
+// nonceTimeout is the maximum time to wait to get a nonce
+const nonceTimeout = 30 * time.Second
+
+// nonce gets a nonce from the nonce manager, waiting no longer than nonceTimeout
+// (or until shutdown)
+func (service *Service) nonce() (string, error) {
+	ctx, cancel := context.WithTimeout(service.shutdownCtx, nonceTimeout)
+	defer cancel()
+
+	return service.nonceManager.Nonce(ctx)
+}
+
 // postToUrlSigned posts the payload to the specified url, using the specified AccountKeyInfo
 // and returns the response body (data / bytes) and headers from ACME
 func (service *Service) postToUrlSigned(payload any, url string, accountKey AccountKey) (body []byte, headers http.Header, err error) {
@@ -61,8 +75,14 @@ func (service *Service) postToUrlSigned(payload any, url string, accountKey Acco
 		header.KeyId = ""
 	}
 
+	// don't send anything if the server previously said to back off
+	blockedUntil := service.BlockedUntil(accountKey)
+	if !blockedUntil.IsZero() {
+		return nil, nil, blockedError(blockedUntil)
+	}
+
 	// nonce
-	header.Nonce, err = service.nonceManager.Nonce()
+	header.Nonce, err = service.nonce()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -156,13 +176,28 @@ func (service *Service) postToUrlSigned(payload any, url string, accountKey Acco
 			err = acmeError
 
 			// if acme error and it is specifically bad nonce, set header nonce and continue
-			// to next loop iteration
-			if acmeError.Type == "urn:ietf:params:acme:error:badNonce" {
+			// to next loop iteration (which re-signs the message with the new nonce)
+			if acmeError.IsType(ErrTypeBadNonce) {
+				service.nonceManager.RecordBadNonce()
 				header.Nonce = response.Header.Get("Replay-Nonce")
 
+				// server should always provide a new nonce, but get one if it didn't
+				if header.Nonce == "" {
+					header.Nonce, err = service.nonce()
+					if err != nil {
+						return nil, nil, err
+					}
+				}
+
 				// no need to sleep, remote server is working ok
 				continue
 			}
+
+			// if rate limited, record when requests may resume
+			if acmeError.IsType(ErrTypeRateLimited) {
+				acmeError.RetryAfter = parseRetryAfter(response.Header)
+				service.recordRateLimit(accountKey.Kid, acmeError.RetryAfter)
+			}
 		}
 
 		// not bad nonce error, loop is done
@@ -184,7 +219,16 @@ func (service *Service) postToUrlSigned(payload any, url string, accountKey Acco
 	}
 
 	// verify status code is success (catch all in case acmeError didn't decode somehow)
-	if response.StatusCode < 200 || response.StatusCode >= 300 {
+	if response.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(response.Header)
+		service.recordRateLimit(accountKey.Kid, retryAfter)
+		return nil, nil, &Error{
+			Status:     response.StatusCode,
+			Type:       ErrTypeRateLimited,
+			Detail:     "acme error: status code 429",
+			RetryAfter: retryAfter,
+		}
+	} else if response.StatusCode < 200 || response.StatusCode >= 300 {
 		return nil, nil, fmt.Errorf("acme error: status code %d", response.StatusCode)
 	}
 
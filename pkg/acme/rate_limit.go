@@ -0,0 +1,122 @@
+package acme
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRateLimitRetryAfter is used if the acme server responds rateLimited but
+// does not include a usable Retry-After header
+const defaultRateLimitRetryAfter = 30 * time.Minute
+
+// rateLimits tracks when the acme server has asked the client to stop sending
+// requests (see: rfc8555 s 6.6). Requests made with an account kid are tracked
+// per account, any other requests (e.g. newAccount) are tracked for the server
+// as a whole.
+type rateLimits struct {
+	serverBlockedUntil  time.Time
+	accountBlockedUntil map[string]time.Time // [kid]blockedUntil
+	mu                  sync.RWMutex
+}
+
+// newRateLimits creates an empty rateLimits
+func newRateLimits() *rateLimits {
+	return &rateLimits{
+		accountBlockedUntil: make(map[string]time.Time),
+	}
+}
+
+// parseRetryAfter returns the time specified by the Retry-After header. The header
+// may be either a number of seconds or an HTTP-date (see: rfc9110 s 10.2.3). If the
+// header is missing or invalid, the default is used.
+func parseRetryAfter(headers http.Header) time.Time {
+	retryAfter := headers.Get("Retry-After")
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	if retryTime, err := http.ParseTime(retryAfter); err == nil {
+		return retryTime
+	}
+
+	return time.Now().Add(defaultRateLimitRetryAfter)
+}
+
+// recordRateLimit records that requests using the specified kid should not be sent
+// until the specified time. If kid is blank, the whole server is blocked.
+func (service *Service) recordRateLimit(kid string, until time.Time) {
+	service.rateLimits.mu.Lock()
+	defer service.rateLimits.mu.Unlock()
+
+	if kid == "" {
+		if until.After(service.rateLimits.serverBlockedUntil) {
+			service.rateLimits.serverBlockedUntil = until
+		}
+		service.logger.Warnf("acme server %s rate limited, blocked until %s", service.dirUri, until)
+		return
+	}
+
+	if until.After(service.rateLimits.accountBlockedUntil[kid]) {
+		service.rateLimits.accountBlockedUntil[kid] = until
+	}
+	service.logger.Warnf("acme account %s rate limited, blocked until %s", kid, until)
+}
+
+// ServerBlockedUntil returns the time until which the acme server has blocked all
+// requests. If the server is not blocked, the zero time is returned.
+func (service *Service) ServerBlockedUntil() time.Time {
+	service.rateLimits.mu.RLock()
+	defer service.rateLimits.mu.RUnlock()
+
+	if time.Now().After(service.rateLimits.serverBlockedUntil) {
+		return time.Time{}
+	}
+
+	return service.rateLimits.serverBlockedUntil
+}
+
+// BlockedUntil returns the time until which requests for the specified account key
+// are blocked, considering both the account and the server as a whole. If the account
+// is not blocked, the zero time is returned.
+func (service *Service) BlockedUntil(accountKey AccountKey) time.Time {
+	blockedUntil := service.ServerBlockedUntil()
+
+	if accountKey.Kid != "" {
+		service.rateLimits.mu.RLock()
+		accountBlockedUntil := service.rateLimits.accountBlockedUntil[accountKey.Kid]
+		service.rateLimits.mu.RUnlock()
+
+		if accountBlockedUntil.After(blockedUntil) && time.Now().Before(accountBlockedUntil) {
+			blockedUntil = accountBlockedUntil
+		}
+	}
+
+	return blockedUntil
+}
+
+// blockedError returns a rateLimited Error for a request that was not sent because
+// of a previously recorded rate limit
+func blockedError(blockedUntil time.Time) *Error {
+	return &Error{
+		Status:     http.StatusTooManyRequests,
+		Type:       ErrTypeRateLimited,
+		Detail:     fmt.Sprintf("request not sent, previously rate limited until %s", blockedUntil),
+		RetryAfter: blockedUntil,
+	}
+}
+
+// RateLimitedUntil returns the Retry-After time and true if err is (or wraps) an ACME
+// rateLimited Error. Otherwise it returns the zero time and false.
+func RateLimitedUntil(err error) (time.Time, bool) {
+	acmeErr := new(Error)
+	if errors.As(err, &acmeErr) && acmeErr.IsType(ErrTypeRateLimited) {
+		return acmeErr.RetryAfter, true
+	}
+
+	return time.Time{}, false
+}
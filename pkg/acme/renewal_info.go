@@ -0,0 +1,123 @@
+package acme
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	errAriNotSupported = errors.New("acme server does not support renewal information (ari)")
+	errAriBadPem       = errors.New("ari: failed to decode certificate pem")
+	errAriNoAki        = errors.New("ari: certificate is missing authority key identifier")
+	errAriBadWindow    = errors.New("ari: suggested window is invalid")
+)
+
+// RenewalInfo is the ACME Renewal Information (ARI) object (see: rfc9773 s 4.2)
+type RenewalInfo struct {
+	SuggestedWindow struct {
+		Start timeString `json:"start"`
+		End   timeString `json:"end"`
+	} `json:"suggestedWindow"`
+	ExplanationUrl string `json:"explanationURL,omitempty"`
+}
+
+// SupportsRenewalInfo returns if the acme server's directory includes the
+// renewalInfo endpoint
+func (service *Service) SupportsRenewalInfo() bool {
+	return service.dir.RenewalInfo != ""
+}
+
+// AriCertId returns the ARI unique identifier for the first certificate in the
+// specified pem (or pem chain). The identifier is the base64url encoded AKI
+// keyIdentifier and the base64url encoded DER serial number, joined by a period.
+// (see: rfc9773 s 4.1)
+func AriCertId(pemCert string) (string, error) {
+	// decode pem (if a chain, take the first cert and discard the rest)
+	pemBlock, _ := pem.Decode([]byte(pemCert))
+	if pemBlock == nil {
+		return "", errAriBadPem
+	}
+
+	cert, err := x509.ParseCertificate(pemBlock.Bytes)
+	if err != nil {
+		return "", err
+	}
+
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", errAriNoAki
+	}
+
+	// serial must be the DER encoded value (i.e. two's complement, so add a leading
+	// zero byte if the high bit is set)
+	serialBytes := cert.SerialNumber.Bytes()
+	if len(serialBytes) == 0 || serialBytes[0]&0x80 != 0 {
+		serialBytes = append([]byte{0}, serialBytes...)
+	}
+
+	return encodeString(cert.AuthorityKeyId) + "." + encodeString(serialBytes), nil
+}
+
+// GetRenewalInfo fetches the renewal information for the first certificate in the
+// specified pem (or pem chain). Per rfc9773 this is an unauthenticated GET request.
+func (service *Service) GetRenewalInfo(pemCert string) (renewalInfo RenewalInfo, err error) {
+	if !service.SupportsRenewalInfo() {
+		return RenewalInfo{}, errAriNotSupported
+	}
+
+	certId, err := AriCertId(pemCert)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+
+	// GET renewal info
+	response, err := service.httpClient.Get(strings.TrimSuffix(service.dir.RenewalInfo, "/") + "/" + certId)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		// try to decode AcmeError
+		acmeError := unmarshalErrorResponse(bodyBytes)
+		if acmeError != nil {
+			return RenewalInfo{}, acmeError
+		}
+
+		return RenewalInfo{}, fmt.Errorf("ari: status code %d", response.StatusCode)
+	}
+
+	err = json.Unmarshal(bodyBytes, &renewalInfo)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+
+	// window must be valid (see: rfc9773 s 4.2)
+	start := renewalInfo.SuggestedWindow.Start.ToUnixTime()
+	end := renewalInfo.SuggestedWindow.End.ToUnixTime()
+	if start == 0 || end == 0 || end < start {
+		return RenewalInfo{}, errAriBadWindow
+	}
+
+	return renewalInfo, nil
+}
+
+// WindowStart returns the unix time of the start of the suggested window
+func (ri *RenewalInfo) WindowStart() int {
+	return ri.SuggestedWindow.Start.ToUnixTime()
+}
+
+// WindowEnd returns the unix time of the end of the suggested window
+func (ri *RenewalInfo) WindowEnd() int {
+	return ri.SuggestedWindow.End.ToUnixTime()
+}
@@ -20,11 +20,13 @@ type App interface {
 
 // Acme service struct
 type Service struct {
+	shutdownCtx  context.Context
 	logger       *zap.SugaredLogger
 	httpClient   *httpclient.Client
 	dirUri       string
 	dir          *directory
 	nonceManager *nonces.Manager
+	rateLimits   *rateLimits
 }
 
 // NewService creates a new service
@@ -40,6 +42,9 @@ func NewService(app App, dirUri string) (*Service, error) {
 	// http client
 	service.httpClient = app.GetHttpClient()
 
+	// shutdown context
+	service.shutdownCtx = app.GetShutdownContext()
+
 	// acme directory
 	service.dirUri = dirUri
 	service.dir = new(directory)
@@ -48,7 +53,15 @@ func NewService(app App, dirUri string) (*Service, error) {
 	service.backgroundDirManager(app.GetShutdownContext(), app.GetShutdownWaitGroup())
 
 	// nonce manager
-	service.nonceManager = nonces.NewManager(service.httpClient, &service.dir.NewNonce)
+	service.nonceManager = nonces.NewManager(service.httpClient, &service.dir.NewNonce, app.GetShutdownContext(), app.GetShutdownWaitGroup())
+
+	// rate limit tracking
+	service.rateLimits = newRateLimits()
 
 	return service, nil
 }
+
+// NonceStats returns the metrics of the service's nonce manager
+func (service *Service) NonceStats() nonces.Stats {
+	return service.nonceManager.Stats()
+}
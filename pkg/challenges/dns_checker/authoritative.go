@@ -0,0 +1,268 @@
+package dns_checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// authoritativeTimeoutSeconds bounds one complete authoritative check (zone discovery
+// and querying every nameserver)
+const authoritativeTimeoutSeconds = 30
+
+// authServer is one address of an authoritative nameserver
+type authServer struct {
+	name    string
+	address string
+}
+
+// String returns the nameserver's name and address
+func (s authServer) String() string {
+	return fmt.Sprintf("%s (%s)", strings.TrimSuffix(s.name, "."), s.address)
+}
+
+// authoritativeCheck is the result of checking for a record on every authoritative
+// nameserver of its zone
+type authoritativeCheck struct {
+	recordCheck
+	// lagging contains the nameservers that do not yet serve the record and why
+	lagging []string
+}
+
+// recursiveQuery sends a query using the configured resolver pairs, trying each pair
+// in order until one responds
+func (service *Service) recursiveQuery(ctx context.Context, name string, qtype uint16) (resp *dns.Msg, err error) {
+	for i := range service.dnsResolvers {
+		resp, err = service.dnsResolvers[i].query(ctx, name, qtype)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	if err == nil {
+		err = errors.New("no dns resolvers configured")
+	}
+
+	return nil, err
+}
+
+// findZone returns the zone that contains name by walking up the labels of name until
+// one is found that has an SOA record (i.e. is a zone apex)
+func (service *Service) findZone(ctx context.Context, name string) (string, error) {
+	candidate := dns.Fqdn(name)
+
+	for {
+		resp, err := service.recursiveQuery(ctx, candidate, dns.TypeSOA)
+		if err != nil {
+			return "", err
+		}
+
+		for _, rr := range resp.Answer {
+			soa, ok := rr.(*dns.SOA)
+			if ok && sameFqdn(soa.Hdr.Name, candidate) {
+				return candidate, nil
+			}
+		}
+
+		// move up one label
+		next, end := dns.NextLabel(candidate, 0)
+		if end {
+			return "", fmt.Errorf("failed to find zone of %s", name)
+		}
+		candidate = candidate[next:]
+	}
+}
+
+// authoritativeServers returns the addresses of the zone's authoritative nameservers. For
+// each nameserver, all of its ipv4 addresses are used, or its ipv6 addresses if it has no
+// ipv4 addresses.
+func (service *Service) authoritativeServers(ctx context.Context, zone string) ([]authServer, error) {
+	resp, err := service.recursiveQuery(ctx, zone, dns.TypeNS)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []authServer{}
+	for _, rr := range resp.Answer {
+		ns, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+
+		addresses := []string{}
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+			addrResp, err := service.recursiveQuery(ctx, ns.Ns, qtype)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, addrRR := range addrResp.Answer {
+				switch addr := addrRR.(type) {
+				case *dns.A:
+					addresses = append(addresses, addr.A.String())
+				case *dns.AAAA:
+					addresses = append(addresses, addr.AAAA.String())
+				}
+			}
+
+			// only fall back to ipv6
+			if len(addresses) > 0 {
+				break
+			}
+		}
+
+		if len(addresses) == 0 {
+			return nil, fmt.Errorf("failed to find address of nameserver %s", ns.Ns)
+		}
+
+		for _, address := range addresses {
+			servers = append(servers, authServer{
+				name:    ns.Ns,
+				address: address,
+			})
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("failed to find nameservers of zone %s", zone)
+	}
+
+	return servers, nil
+}
+
+// authoritativeQuery sends a non-recursive query to the authoritative server. If the udp
+// response is truncated, the query is retried over tcp.
+func authoritativeQuery(ctx context.Context, server authServer, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = false
+	msg.SetEdns0(4096, false)
+
+	address := net.JoinHostPort(server.address, "53")
+
+	client := &dns.Client{Net: "udp", Timeout: timeoutSeconds * time.Second}
+	resp, _, err := client.ExchangeContext(ctx, msg, address)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkDnsRecordAuthoritative checks that every authoritative nameserver of fqdn's zone
+// serves the record with the specified value. If fqdn is a CNAME, the CNAME chain is
+// followed and the authoritative nameservers of the target's zone are checked instead.
+func (service *Service) checkDnsRecordAuthoritative(fqdn string, recordValue string, recordType dnsRecordType) (result authoritativeCheck, err error) {
+	// supported record types
+	var qtype uint16
+	switch recordType {
+	// TXT records
+	case txtRecord:
+		qtype = dns.TypeTXT
+
+	// any other (unsupported)
+	default:
+		return authoritativeCheck{}, errors.New("unsupported dns record type")
+	}
+
+	// timeout context
+	ctx, cancel := context.WithTimeout(service.shutdownContext, authoritativeTimeoutSeconds*time.Second)
+	defer cancel()
+
+	name := dns.Fqdn(fqdn)
+	result.path = []string{name}
+
+	for {
+		zone, err := service.findZone(ctx, name)
+		if err != nil {
+			return authoritativeCheck{}, err
+		}
+
+		servers, err := service.authoritativeServers(ctx, zone)
+		if err != nil {
+			return authoritativeCheck{}, err
+		}
+
+		// query every server (concurrently)
+		responses := make([]*dns.Msg, len(servers))
+		errs := make([]error, len(servers))
+
+		var wg sync.WaitGroup
+		wg.Add(len(servers))
+		for i := range servers {
+			go func(i int) {
+				defer wg.Done()
+				responses[i], errs[i] = authoritativeQuery(ctx, servers[i], name, qtype)
+			}(i)
+		}
+		wg.Wait()
+
+		cnameTarget := ""
+		for i := range servers {
+			if errs[i] != nil {
+				continue
+			}
+
+			if chain := followCnames(responses[i].Answer, name); len(chain) > 0 && cnameTarget == "" {
+				cnameTarget = chain[0]
+			}
+		}
+
+		// name is a CNAME, check its target instead
+		if cnameTarget != "" {
+			result.path = append(result.path, cnameTarget)
+			if len(result.path) > maxCnameChain {
+				return authoritativeCheck{}, fmt.Errorf("cname chain of %s is too long (%s)", fqdn, pathString(result.path))
+			}
+
+			name = cnameTarget
+			continue
+		}
+
+		// check each server's answer
+		result.lagging = []string{}
+		for i := range servers {
+			reason := ""
+			switch {
+			case errs[i] != nil:
+				reason = errs[i].Error()
+			case !responses[i].Authoritative:
+				reason = "not authoritative for " + zone
+			case responses[i].Rcode != dns.RcodeSuccess && responses[i].Rcode != dns.RcodeNameError:
+				reason = dns.RcodeToString[responses[i].Rcode]
+			case !answerHasTxtValue(responses[i].Answer, name, recordValue):
+				reason = "record not found"
+			}
+
+			if reason != "" {
+				result.lagging = append(result.lagging, fmt.Sprintf("%s: %s", servers[i], reason))
+			}
+		}
+
+		result.exists = len(result.lagging) == 0
+		return result, nil
+	}
+}
+
+// answerHasTxtValue returns true if answer contains a TXT record for name with the
+// specified value
+func answerHasTxtValue(answer []dns.RR, name string, value string) bool {
+	for _, rr := range answer {
+		txt, ok := rr.(*dns.TXT)
+		if ok && sameFqdn(txt.Hdr.Name, name) && strings.Join(txt.Txt, "") == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,9 +3,12 @@ package dns_checker
 import (
 	"context"
 	"errors"
-	"net"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/miekg/dns"
 )
 
 // thresholds to decide if checking succeeded for not.
@@ -18,62 +21,89 @@ const (
 	functioningRequirement = 0.5
 )
 
-// checkDnsRecord checks if the fqdn has a record of the specified type, set to the specified
-// value, on the specified dns resolver. If the record does not exist or exists but the value is
-// different, false is returned. If there is an error querying for the record, an error is returned.
-func checkDnsRecord(fqdn string, recordValue string, recordType dnsRecordType, r *net.Resolver) (exists bool, err error) {
-	var values []string
+// recordCheck is the result of checking for a record on one dns resolver
+type recordCheck struct {
+	// exists is true if the record exists and is set to the desired value
+	exists bool
+	// path is the CNAME chain that was followed, starting with the queried fqdn and
+	// ending with the name that holds (or should hold) the record
+	path []string
+}
 
+// checkDnsRecord checks if the fqdn has a record of the specified type, set to the specified
+// value, on the specified dns resolver. If fqdn is a CNAME, the CNAME chain is followed and the
+// record is checked at the end of the chain. If the record does not exist or exists but the value
+// is different, exists is false. If there is an error querying for the record, an error is returned.
+func checkDnsRecord(fqdn string, recordValue string, recordType dnsRecordType, r *resolver) (result recordCheck, err error) {
 	// nil check
 	if r == nil {
-		return false, errors.New("can't check record, resolver is nil")
+		return recordCheck{}, errors.New("can't check record, resolver is nil")
 	}
 
-	// timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutSeconds*time.Second)
-	defer cancel()
-
-	// run appropriate query function
+	// supported record types
+	var qtype uint16
 	switch recordType {
 	// TXT records
 	case txtRecord:
-		values, err = r.LookupTXT(ctx, fqdn)
+		qtype = dns.TypeTXT
 
 	// any other (unsupported)
 	default:
-		return false, errors.New("unsupported dns record type")
+		return recordCheck{}, errors.New("unsupported dns record type")
 	}
 
-	// error check
-	if err != nil {
-		// if host wasn't found, this isn't a real error, it actually just means
-		// the record does not exist
-		dnsErr := new(net.DNSError)
-		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
-			return false, nil
+	// each query is bounded by the resolver's timeout
+	ctx := context.Background()
+
+	name := dns.Fqdn(fqdn)
+	result.path = []string{name}
+
+	for {
+		resp, err := r.query(ctx, name, qtype)
+		if err != nil {
+			return recordCheck{}, err
 		}
 
-		// any other error, server failed
-		return false, err
-	}
+		// follow any CNAME chain included in the answer
+		chain := followCnames(resp.Answer, name)
+		if len(chain) > 0 {
+			result.path = append(result.path, chain...)
+			name = chain[len(chain)-1]
+		}
+		if len(result.path) > maxCnameChain {
+			return recordCheck{}, fmt.Errorf("cname chain of %s is too long (%s)", fqdn, pathString(result.path))
+		}
+
+		// check for desired value at the end of the chain
+		found := false
+		for _, rr := range resp.Answer {
+			txt, ok := rr.(*dns.TXT)
+			if !ok || !sameFqdn(txt.Hdr.Name, name) {
+				continue
+			}
+			found = true
 
-	// check for desired value
-	for i := range values {
-		// if value found
-		if values[i] == recordValue {
-			return true, nil
+			// long values may be split into multiple strings
+			if strings.Join(txt.Txt, "") == recordValue {
+				result.exists = true
+				return result, nil
+			}
 		}
-	}
 
-	// records exist but desired value wasn't found
-	return false, nil
+		// if the chain was not followed to its end by the resolver (no records of the type
+		// at the last name), query the last name directly; otherwise done
+		if found || len(chain) == 0 || resp.Rcode != dns.RcodeSuccess {
+			return result, nil
+		}
+	}
 }
 
 // checkDnsRecordAllServices sends concurrent dns requests using all configured
 // resolvers to check for the existence of the specified record. If the propagation
-// requirement is met, TRUE is returned. An Error is returned if the functioning
-// requirement is not met.
-func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue string, recordType dnsRecordType) (exists bool) {
+// requirement is met, TRUE is returned. FALSE is returned if the functioning
+// requirement is not met. The CNAME chain of fqdn (as resolved by the first functioning
+// resolver) is also returned.
+func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue string, recordType dnsRecordType) (exists bool, path []string) {
 	// if no resolvers (i.e. configured to skip)
 	if service.dnsResolvers == nil {
 		// sleep the skip wait and then return true (assume propagated)
@@ -90,13 +120,13 @@ func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue strin
 			}
 
 			// cancel/error if shutting down
-			return false
+			return false, nil
 
 		case <-delayTimer.C:
 			// sleep and retry
 		}
 
-		return true
+		return true, nil
 	}
 
 	// use waitgroup for concurrent checking
@@ -104,7 +134,7 @@ func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue strin
 	resolverTotal := len(service.dnsResolvers)
 
 	wg.Add(resolverTotal)
-	wgResults := make(chan bool, resolverTotal)
+	wgResults := make(chan recordCheck, resolverTotal)
 	wgErrors := make(chan error, resolverTotal)
 
 	// for each resolver pair, start a Go Routine
@@ -139,13 +169,18 @@ func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue strin
 
 	// if error rate is greater than tolerable, return not propagated
 	if errRate > (1 - functioningRequirement) {
-		return false
+		return false, nil
 	}
 
 	// error rate was acceptable, check results
 	successCount := 0
-	for existed := range wgResults {
-		if existed {
+	for result := range wgResults {
+		// errored resolvers return an empty result
+		if path == nil && result.path != nil {
+			path = result.path
+		}
+
+		if result.exists {
 			successCount++
 		}
 	}
@@ -155,5 +190,27 @@ func (service *Service) checkDnsRecordAllServices(fqdn string, recordValue strin
 	service.logger.Debugf("dns check (%s): propagation success count: %d, resolver count: %d, propagation rate: %.2f, propagation requirement: %.2f", fqdn, successCount, resolverTotal, propagationRate, propagationRequirement)
 
 	// return true if rate >= requirement
-	return propagationRate >= propagationRequirement
+	return propagationRate >= propagationRequirement, path
+}
+
+// checkDnsRecordPropagated checks for the specified record using the configured mode. It
+// returns TRUE if the record is propagated and the CNAME chain of fqdn.
+func (service *Service) checkDnsRecordPropagated(fqdn string, recordValue string, recordType dnsRecordType) (exists bool, path []string) {
+	// recursive mode (or skipping check)
+	if !service.authoritative || service.dnsResolvers == nil {
+		return service.checkDnsRecordAllServices(fqdn, recordValue, recordType)
+	}
+
+	// authoritative mode
+	result, err := service.checkDnsRecordAuthoritative(fqdn, recordValue, recordType)
+	if err != nil {
+		service.logger.Errorf("dns check (%s): authoritative check failed (%s)", fqdn, err)
+		return false, nil
+	}
+
+	if len(result.lagging) > 0 {
+		service.logger.Infof("dns check (%s): authoritative nameserver(s) lagging: %s", fqdn, strings.Join(result.lagging, "; "))
+	}
+
+	return result.exists, result.path
 }
@@ -20,11 +20,45 @@ const (
 
 // CheckTXTWithRetry checks for the specified record. If the check fails, use exponential
 // backoff until that times out and then return false if propagation still hasn't occurred.
-func (service *Service) CheckTXTWithRetry(fqdn string, recordValue string) (propagated bool) {
+// If fqdn is a CNAME, the record is checked at the end of the CNAME chain. If the provider
+// writes the record to a delegated fqdn, delegatedFqdn should be set to it so a CNAME
+// that doesn't lead there can be reported, otherwise it should be blank.
+func (service *Service) CheckTXTWithRetry(fqdn string, recordValue string, delegatedFqdn string) (propagated bool) {
+	propagated, _ = service.checkTXTWithBackoff(fqdn, recordValue, delegatedFqdn, 30*time.Minute)
+	return propagated
+}
+
+// CheckTXTWithTimeout is the same as CheckTXTWithRetry except that it gives up once timeout
+// has elapsed. The last path followed to find the record (the CNAME chain) is also returned.
+func (service *Service) CheckTXTWithTimeout(fqdn string, recordValue string, delegatedFqdn string, timeout time.Duration) (propagated bool, path []string) {
+	return service.checkTXTWithBackoff(fqdn, recordValue, delegatedFqdn, timeout)
+}
+
+// checkTXTWithBackoff checks for the specified record using exponential backoff until
+// maxElapsed and returns if it propagated along with the last path followed
+func (service *Service) checkTXTWithBackoff(fqdn string, recordValue string, delegatedFqdn string, maxElapsed time.Duration) (propagated bool, path []string) {
+	// the last reported path (to only log when it changes)
+	lastPath := ""
+	warnedDelegation := false
+
 	// func to try with exponential backoff
 	checkAllServicesFunc := func() (bool, error) {
 		// check for propagation
-		propagated := service.checkDnsRecordAllServices(fqdn, recordValue, txtRecord)
+		var propagated bool
+		propagated, path = service.checkDnsRecordPropagated(fqdn, recordValue, txtRecord)
+
+		// report the resolved path
+		if len(path) > 1 && pathString(path) != lastPath {
+			lastPath = pathString(path)
+			service.logger.Infof("dns check (%s): following cname chain %s", fqdn, lastPath)
+		}
+
+		// warn if the provider writes somewhere the chain doesn't lead
+		if !warnedDelegation && delegatedFqdn != "" && len(path) > 0 && !sameFqdn(path[len(path)-1], delegatedFqdn) {
+			warnedDelegation = true
+			service.logger.Warnf("dns check (%s): provider writes the record to %s but %s resolves to %s (check the _acme-challenge CNAME)",
+				fqdn, delegatedFqdn, fqdn, path[len(path)-1])
+		}
 
 		// if propagated, done & success
 		if propagated {
@@ -41,7 +75,7 @@ func (service *Service) CheckTXTWithRetry(fqdn string, recordValue string) (prop
 	bo.RandomizationFactor = 0.2
 	bo.Multiplier = 1.2
 	bo.MaxInterval = 2 * time.Minute
-	bo.MaxElapsedTime = 30 * time.Minute
+	bo.MaxElapsedTime = maxElapsed
 
 	boWithContext := backoff.WithContext(bo, service.shutdownContext)
 
@@ -53,8 +87,8 @@ func (service *Service) CheckTXTWithRetry(fqdn string, recordValue string) (prop
 	// (re)try with backoff
 	propagated, err := backoff.RetryNotifyWithData(checkAllServicesFunc, boWithContext, notifyFunc)
 	if err != nil || !propagated {
-		return false
+		return false, path
 	}
 
-	return true
+	return true, path
 }
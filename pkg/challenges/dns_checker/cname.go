@@ -0,0 +1,47 @@
+package dns_checker
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// maxCnameChain is the maximum number of names (including the original fqdn) that
+// will be followed when resolving a CNAME chain
+const maxCnameChain = 10
+
+// followCnames follows the CNAME chain starting at name using the records in answer. It
+// returns the names name is aliased to (in order), which is empty if name is not a CNAME.
+func followCnames(answer []dns.RR, name string) (chain []string) {
+	for len(chain) < maxCnameChain {
+		next := ""
+		for _, rr := range answer {
+			cname, ok := rr.(*dns.CNAME)
+			if ok && strings.EqualFold(cname.Hdr.Name, name) {
+				next = cname.Target
+				break
+			}
+		}
+
+		// end of chain
+		if next == "" {
+			return chain
+		}
+
+		chain = append(chain, next)
+		name = next
+	}
+
+	return chain
+}
+
+// sameFqdn returns true if a and b are the same fqdn (case insensitive, trailing
+// dot optional)
+func sameFqdn(a, b string) bool {
+	return strings.EqualFold(dns.Fqdn(a), dns.Fqdn(b))
+}
+
+// pathString returns a printable version of a CNAME chain
+func pathString(path []string) string {
+	return strings.Join(path, " -> ")
+}
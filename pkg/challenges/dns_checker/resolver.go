@@ -3,31 +3,54 @@ package dns_checker
 import (
 	"context"
 	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/httpclient"
 	"net"
 	"time"
+
+	"github.com/miekg/dns"
 )
 
-// timeoutSeconds is the DNS dialer timeout (in seconds)
+// timeoutSeconds is the default DNS query timeout (in seconds)
 const timeoutSeconds = 5
 
 var (
-	errBlankIP = errors.New("can't create resolver, ip address is blank")
+	errBlankIP          = errors.New("can't create resolver, ip address is blank")
+	errMultipleResolver = errors.New("can't create resolver, only one of ip, doh url, or dot address may be specified")
 )
 
+// exchangeFunc sends a dns message to a dns server using a specific transport and
+// returns the server's response
+type exchangeFunc func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+
+// resolver sends queries to a specific recursive DNS server
+type resolver struct {
+	// address of the server (ip:port, doh url, or dot host:port)
+	address  string
+	timeout  time.Duration
+	exchange exchangeFunc
+}
+
 // makeResolvers generates all of the resolver pairs for a slice
 // of DNS Service IP Pairs
-func makeResolvers(dnsServices []DnsServiceIPPair) ([]dnsResolverPair, error) {
+func makeResolvers(dnsServices []DnsServiceIPPair, httpClient *httpclient.Client) ([]dnsResolverPair, error) {
 	// add each service pair to the resolver pairs
 	dnsResolverPairs := []dnsResolverPair{}
 	for i := range dnsServices {
+		// timeout
+		timeout := timeoutSeconds * time.Second
+		if dnsServices[i].TimeoutSeconds > 0 {
+			timeout = time.Duration(dnsServices[i].TimeoutSeconds) * time.Second
+		}
+
 		// make primary
-		primaryR, err := makeResolver(dnsServices[i].Primary)
+		primaryR, err := makeResolver(dnsServices[i].Primary, dnsServices[i].PrimaryDohUrl, dnsServices[i].PrimaryDot, timeout, httpClient)
 		if err != nil {
 			return nil, err
 		}
 
 		// make secondary (blank is okay, just exclude it)
-		secondaryR, err := makeResolver(dnsServices[i].Secondary)
+		secondaryR, err := makeResolver(dnsServices[i].Secondary, dnsServices[i].SecondaryDohUrl, dnsServices[i].SecondaryDot, timeout, httpClient)
 		if err != nil && !errors.Is(err, errBlankIP) {
 			return nil, err
 		}
@@ -45,29 +68,90 @@ func makeResolvers(dnsServices []DnsServiceIPPair) ([]dnsResolverPair, error) {
 	return dnsResolverPairs, nil
 }
 
-// makeResolver creates a net.Resolver to resolve DNS queries using
-// the specified DNS server IP.
-func makeResolver(ipAddress string) (*net.Resolver, error) {
-	if ipAddress == "" {
-		return nil, errBlankIP
+// makeResolver creates a resolver to resolve DNS queries using the specified DNS
+// server. Exactly one of ipAddress (plain dns), dohUrl (DNS-over-HTTPS), or dotAddress
+// (DNS-over-TLS) must be specified.
+func makeResolver(ipAddress string, dohUrl string, dotAddress string, timeout time.Duration, httpClient *httpclient.Client) (*resolver, error) {
+	r := &resolver{
+		timeout: timeout,
+	}
+
+	specified := 0
+	for _, s := range []string{ipAddress, dohUrl, dotAddress} {
+		if s != "" {
+			specified++
+		}
 	}
 
-	r := &net.Resolver{
-		PreferGo: true,
-		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-			d := net.Dialer{}
-			return d.DialContext(ctx, network, ipAddress+":53")
-		},
+	switch {
+	case specified == 0:
+		return nil, errBlankIP
+
+	case specified > 1:
+		return nil, errMultipleResolver
+
+	case ipAddress != "":
+		r.address = net.JoinHostPort(ipAddress, "53")
+		r.exchange = plainExchange(r.address, timeout)
+
+	case dohUrl != "":
+		var err error
+		r.address = dohUrl
+		r.exchange, err = dohExchange(dohUrl, httpClient)
+		if err != nil {
+			return nil, err
+		}
+
+	case dotAddress != "":
+		r.address = dotAddress
+		r.exchange = dotExchange(dotAddress, timeout)
 	}
 
 	// make sure the dns resolver actually works
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutSeconds*time.Second)
+	_, err := r.query(context.Background(), "google.com", dns.TypeA)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// query sends a recursive query for the specified name and type. An error is returned
+// if the query fails or the server responds with an rcode other than success or name
+// error (nxdomain).
+func (r *resolver) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+	msg.SetEdns0(4096, false)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	_, err := r.LookupIP(ctx, "ip", "google.com")
+	resp, err := r.exchange(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
 
-	return r, nil
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("dns server %s responded %s to query for %s", r.address, dns.RcodeToString[resp.Rcode], name)
+	}
+
+	return resp, nil
+}
+
+// plainExchange returns an exchangeFunc that uses plain dns. Queries are sent over udp
+// and if the response is truncated, the query is retried over tcp.
+func plainExchange(address string, timeout time.Duration) exchangeFunc {
+	udpClient := &dns.Client{Net: "udp", Timeout: timeout}
+	tcpClient := &dns.Client{Net: "tcp", Timeout: timeout}
+
+	return func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+		resp, _, err := udpClient.ExchangeContext(ctx, msg, address)
+		if err == nil && resp.Truncated {
+			resp, _, err = tcpClient.ExchangeContext(ctx, msg, address)
+		}
+
+		return resp, err
+	}
 }
@@ -0,0 +1,102 @@
+package dns_checker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"legocerthub-backend/pkg/httpclient"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNS-over-TLS (see: rfc7858) and DNS-over-HTTPS (see: rfc8484) transports
+
+// defaultDotPort is used if a DNS-over-TLS address doesn't specify a port
+const defaultDotPort = "853"
+
+// dohContentType is the media type of DNS-over-HTTPS requests and responses
+const dohContentType = "application/dns-message"
+
+// dohMaxResponse is the largest DNS-over-HTTPS response that will be read
+const dohMaxResponse = 65535
+
+// dotExchange returns an exchangeFunc that uses DNS-over-TLS. The server's certificate
+// is verified against the host portion of address.
+func dotExchange(address string, timeout time.Duration) exchangeFunc {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+		address = net.JoinHostPort(address, defaultDotPort)
+	}
+
+	client := &dns.Client{
+		Net:     "tcp-tls",
+		Timeout: timeout,
+		TLSConfig: &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	return func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+		resp, _, err := client.ExchangeContext(ctx, msg, address)
+		return resp, err
+	}
+}
+
+// dohExchange returns an exchangeFunc that uses DNS-over-HTTPS (POST method)
+func dohExchange(dohUrl string, httpClient *httpclient.Client) (exchangeFunc, error) {
+	if httpClient == nil {
+		return nil, errors.New("can't create doh resolver, http client is missing")
+	}
+
+	u, err := url.Parse(dohUrl)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("can't create doh resolver, url %s is not a valid https url", dohUrl)
+	}
+
+	return func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+		// id should be 0 to maximize http cache friendliness (rfc8484 s 4.1)
+		query := msg.Copy()
+		query.Id = 0
+
+		packed, err := query.Pack()
+		if err != nil {
+			return nil, err
+		}
+
+		header := make(http.Header)
+		header.Set("Accept", dohContentType)
+
+		resp, err := httpClient.PostWithHeaderWithContext(ctx, dohUrl, dohContentType, bytes.NewReader(packed), header)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, dohMaxResponse))
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("doh server %s responded with status %d", dohUrl, resp.StatusCode)
+		}
+
+		answer := new(dns.Msg)
+		err = answer.Unpack(body)
+		if err != nil {
+			return nil, fmt.Errorf("doh server %s response invalid (%w)", dohUrl, err)
+		}
+		answer.Id = msg.Id
+
+		return answer, nil
+	}, nil
+}
@@ -0,0 +1,136 @@
+package dns_checker
+
+import (
+	"context"
+	"io"
+	"legocerthub-backend/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// newDohTestServer starts a tls server with handler and returns an http client that
+// trusts it
+func newDohTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *httpclient.Client) {
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	// httpclient uses the default transport, trust the test server's certificate
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	oldTlsConfig := defaultTransport.TLSClientConfig
+	defaultTransport.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig
+	t.Cleanup(func() { defaultTransport.TLSClientConfig = oldTlsConfig })
+
+	return srv, httpclient.New("lego-test")
+}
+
+// dohAnswer returns a packed response to query with a TXT answer
+func dohAnswer(t *testing.T, query *dns.Msg, txt string) []byte {
+	resp := new(dns.Msg)
+	resp.SetReply(query)
+	resp.Answer = []dns.RR{&dns.TXT{
+		Hdr: dns.RR_Header{Name: query.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+		Txt: []string{txt},
+	}}
+
+	packed, err := resp.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return packed
+}
+
+func TestDnsChecker_DohExchange(t *testing.T) {
+	srv, client := newDohTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("doh request method is %s, expected POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != dohContentType {
+			t.Errorf("doh request content type is %s, expected %s", ct, dohContentType)
+		}
+		if accept := r.Header.Get("Accept"); accept != dohContentType {
+			t.Errorf("doh request accept is %s, expected %s", accept, dohContentType)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := new(dns.Msg)
+		err = query.Unpack(body)
+		if err != nil {
+			t.Errorf("doh request body is not a dns message (%s)", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if query.Id != 0 {
+			t.Errorf("doh request id is %d, expected 0", query.Id)
+		}
+
+		switch query.Question[0].Name {
+		case "status.example.com.":
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+		case "garbage.example.com.":
+			w.Header().Set("Content-Type", dohContentType)
+			_, _ = w.Write([]byte("not a dns message"))
+
+		default:
+			w.Header().Set("Content-Type", dohContentType)
+			_, _ = w.Write(dohAnswer(t, query, "doh-value"))
+		}
+	})
+
+	// invalid urls
+	for _, badUrl := range []string{"http://dns.example.com/dns-query", "dns.example.com", "https:///dns-query"} {
+		_, err := dohExchange(badUrl, client)
+		if err == nil {
+			t.Errorf("doh url '%s' should be invalid", badUrl)
+		}
+	}
+	_, err := dohExchange(srv.URL, nil)
+	if err == nil {
+		t.Error("doh exchange without http client should be invalid")
+	}
+
+	exchange, err := dohExchange(srv.URL+"/dns-query", client)
+	if err != nil {
+		t.Fatalf("failed to make doh exchange (%s)", err)
+	}
+
+	// answer is unpacked and the original id restored
+	msg := new(dns.Msg)
+	msg.SetQuestion("_acme-challenge.example.com.", dns.TypeTXT)
+	msg.Id = 1234
+	resp, err := exchange(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("doh exchange failed (%s)", err)
+	}
+	if resp.Id != 1234 {
+		t.Errorf("doh response id is %d, expected 1234", resp.Id)
+	}
+	if len(resp.Answer) != 1 {
+		t.Fatalf("doh response has %d answers, expected 1", len(resp.Answer))
+	}
+	if txt, ok := resp.Answer[0].(*dns.TXT); !ok || txt.Txt[0] != "doh-value" {
+		t.Errorf("doh response answer is %s, expected txt doh-value", resp.Answer[0])
+	}
+
+	// non-200 status
+	msg.SetQuestion("status.example.com.", dns.TypeTXT)
+	_, err = exchange(context.Background(), msg)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("doh exchange with status 503 returned error '%v', expected status error", err)
+	}
+
+	// response that isn't a dns message
+	msg.SetQuestion("garbage.example.com.", dns.TypeTXT)
+	_, err = exchange(context.Background(), msg)
+	if err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("doh exchange with garbage response returned error '%v', expected invalid response error", err)
+	}
+}
@@ -1,40 +1,70 @@
 package dns_checker
 
-import "net"
+import (
+	"context"
+
+	"github.com/miekg/dns"
+)
 
 // DnsServiceIPPair contains a primary and secondary DNS server for
-// a given DNS service
+// a given DNS service. Each server is specified by one of ip (plain dns),
+// doh url (DNS-over-HTTPS), or dot address (DNS-over-TLS host[:port]).
 type DnsServiceIPPair struct {
-	Primary   string `yaml:"primary_ip"`
-	Secondary string `yaml:"secondary_ip"`
+	Primary         string `yaml:"primary_ip,omitempty"`
+	PrimaryDohUrl   string `yaml:"primary_doh_url,omitempty"`
+	PrimaryDot      string `yaml:"primary_dot,omitempty"`
+	Secondary       string `yaml:"secondary_ip,omitempty"`
+	SecondaryDohUrl string `yaml:"secondary_doh_url,omitempty"`
+	SecondaryDot    string `yaml:"secondary_dot,omitempty"`
+	TimeoutSeconds  int    `yaml:"timeout_seconds,omitempty"`
 }
 
-// dnsResolverPair contains the net.Resolver pair for a specific DNS service
+// dnsResolverPair contains the resolver pair for a specific DNS service
 type dnsResolverPair struct {
-	primary   *net.Resolver
-	secondary *net.Resolver
+	primary   *resolver
+	secondary *resolver
 }
 
 // checkDnsRecord attempts to find the specified record using the dnsResolverPair. It
 // first tries the primary dns server and if an error is returned it attempts to use
 // the secondary server.
-func (rPair dnsResolverPair) checkDnsRecord(fqdn string, recordValue string, recordType dnsRecordType) (exists bool, err error) {
+func (rPair dnsResolverPair) checkDnsRecord(fqdn string, recordValue string, recordType dnsRecordType) (result recordCheck, err error) {
 	// try primary
-	exists, err = checkDnsRecord(fqdn, recordValue, recordType, rPair.primary)
-	// if NO error, return exists
+	result, err = checkDnsRecord(fqdn, recordValue, recordType, rPair.primary)
+	// if NO error, return result
 	if err == nil {
-		return exists, nil
+		return result, nil
 	}
 
 	// if primary errored, try secondary (if there is one)
 	if rPair.secondary != nil {
-		exists, err = checkDnsRecord(fqdn, recordValue, recordType, rPair.secondary)
-		// if NO error, return exists
+		result, err = checkDnsRecord(fqdn, recordValue, recordType, rPair.secondary)
+		// if NO error, return result
 		if err == nil {
-			return exists, nil
+			return result, nil
 		}
 	}
 
 	// return false/error (neither attempt found the record)
-	return false, err
+	return recordCheck{}, err
+}
+
+// query sends a query using the dnsResolverPair. It first tries the primary dns server
+// and if an error is returned it attempts to use the secondary server.
+func (rPair dnsResolverPair) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	// try primary
+	resp, err := rPair.primary.query(ctx, name, qtype)
+	if err == nil {
+		return resp, nil
+	}
+
+	// if primary errored, try secondary (if there is one)
+	if rPair.secondary != nil {
+		resp, err = rPair.secondary.query(ctx, name, qtype)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	return nil, err
 }
@@ -0,0 +1,135 @@
+package dns_checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// testResolver is a resolver that answers every query with a TXT record of value, or
+// fails with err (or the rcode) if set. It counts the queries it receives.
+type testResolver struct {
+	value   string
+	rcode   int
+	err     error
+	queries int
+}
+
+// resolver returns a resolver that uses the testResolver as its exchange
+func (tr *testResolver) resolver(address string) *resolver {
+	return &resolver{
+		address: address,
+		timeout: time.Second,
+		exchange: func(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+			tr.queries++
+			if tr.err != nil {
+				return nil, tr.err
+			}
+
+			resp := new(dns.Msg)
+			resp.SetRcode(msg, tr.rcode)
+			if tr.rcode == dns.RcodeSuccess {
+				resp.Answer = []dns.RR{&dns.TXT{
+					Hdr: dns.RR_Header{Name: msg.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{tr.value},
+				}}
+			}
+
+			return resp, nil
+		},
+	}
+}
+
+// resolverPairTest is the behavior of the primary and secondary resolver and the
+// expected outcome of checking a record on the pair
+type resolverPairTest struct {
+	name               string
+	primary            *testResolver
+	secondary          *testResolver
+	expectErr          bool
+	expectExists       bool
+	expectSecondaryUse bool
+}
+
+var errTestTimeout = errors.New("i/o timeout")
+
+var resolverPairTests = []resolverPairTest{
+	{
+		name:         "primary works",
+		primary:      &testResolver{value: "expected"},
+		secondary:    &testResolver{value: "expected"},
+		expectExists: true,
+	},
+	{
+		name:         "primary works, record not propagated (no failover)",
+		primary:      &testResolver{value: "old"},
+		secondary:    &testResolver{value: "expected"},
+		expectExists: false,
+	},
+	{
+		name:               "primary errors",
+		primary:            &testResolver{err: errTestTimeout},
+		secondary:          &testResolver{value: "expected"},
+		expectExists:       true,
+		expectSecondaryUse: true,
+	},
+	{
+		name:               "primary servfail",
+		primary:            &testResolver{rcode: dns.RcodeServerFailure},
+		secondary:          &testResolver{value: "expected"},
+		expectExists:       true,
+		expectSecondaryUse: true,
+	},
+	{
+		name:               "both error",
+		primary:            &testResolver{err: errTestTimeout},
+		secondary:          &testResolver{rcode: dns.RcodeRefused},
+		expectErr:          true,
+		expectSecondaryUse: true,
+	},
+	{
+		name:      "primary errors, no secondary",
+		primary:   &testResolver{err: errTestTimeout},
+		expectErr: true,
+	},
+}
+
+func TestDnsChecker_ResolverPairFailover(t *testing.T) {
+	for _, test := range resolverPairTests {
+		pair := dnsResolverPair{primary: test.primary.resolver("primary")}
+		if test.secondary != nil {
+			pair.secondary = test.secondary.resolver("secondary")
+		}
+
+		// checkDnsRecord
+		result, err := pair.checkDnsRecord("_acme-challenge.example.com", "expected", txtRecord)
+		if test.expectErr && err == nil {
+			t.Errorf("resolver pair test case '%s' check did not return an error", test.name)
+		} else if !test.expectErr && err != nil {
+			t.Errorf("resolver pair test case '%s' check returned error (%s)", test.name, err)
+		}
+		if result.exists != test.expectExists {
+			t.Errorf("resolver pair test case '%s' check returned exists %t, expected %t", test.name, result.exists, test.expectExists)
+		}
+
+		// query
+		_, err = pair.query(context.Background(), "_acme-challenge.example.com", dns.TypeTXT)
+		if test.expectErr != (err != nil) {
+			t.Errorf("resolver pair test case '%s' query returned error '%v', expected error %t", test.name, err, test.expectErr)
+		}
+
+		// each operation queries the primary once, and the secondary only on failure
+		if test.primary.queries != 2 {
+			t.Errorf("resolver pair test case '%s' primary queried %d times, expected 2", test.name, test.primary.queries)
+		}
+		if test.secondary != nil {
+			secondaryUsed := test.secondary.queries > 0
+			if secondaryUsed != test.expectSecondaryUse {
+				t.Errorf("resolver pair test case '%s' secondary used %t, expected %t", test.name, secondaryUsed, test.expectSecondaryUse)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package dns_checker
 import (
 	"context"
 	"errors"
+	"legocerthub-backend/pkg/httpclient"
 	"time"
 
 	"go.uber.org/zap"
@@ -14,11 +15,22 @@ var errServiceComponent = errors.New("necessary challenges service component is
 type App interface {
 	GetShutdownContext() context.Context
 	GetLogger() *zap.SugaredLogger
+	GetHttpClient() *httpclient.Client
 }
 
+// checker modes
+const (
+	// check that the configured recursive dns services return the record
+	modeRecursive = "recursive"
+	// check that every authoritative nameserver of the record's zone serves the
+	// record (dns services are only used to find the nameservers)
+	modeAuthoritative = "authoritative"
+)
+
 // Config is used to configure the service
 type Config struct {
 	SkipCheckWaitSeconds *int               `yaml:"skip_check_wait_seconds"`
+	Mode                 string             `yaml:"mode"`
 	DnsServices          []DnsServiceIPPair `yaml:"dns_services"`
 }
 
@@ -27,6 +39,7 @@ type Service struct {
 	shutdownContext context.Context
 	logger          *zap.SugaredLogger
 	skipWait        time.Duration
+	authoritative   bool
 	dnsResolvers    []dnsResolverPair
 }
 
@@ -49,14 +62,42 @@ func NewService(app App, cfg Config) (service *Service, err error) {
 		service.logger.Warnf("dns record validation disabled, will manually sleep %d seconds instead", *cfg.SkipCheckWaitSeconds)
 		service.skipWait = time.Duration(*cfg.SkipCheckWaitSeconds) * time.Second
 	} else {
-		service.dnsResolvers, err = makeResolvers(cfg.DnsServices)
+		service.dnsResolvers, err = makeResolvers(cfg.DnsServices, app.GetHttpClient())
 		if err != nil {
 			// if failed to make resolvers, fallback to sleeping
 			fallbackSleepSeconds := 120
 			service.logger.Errorf("failed to configure dns checker resolvers (%s), will sleep %d seconds instead of validating dns records", err, fallbackSleepSeconds)
 			service.skipWait = time.Duration(fallbackSleepSeconds) * time.Second
 		}
+
+		// check mode
+		switch cfg.Mode {
+		case "", modeRecursive:
+			// default
+		case modeAuthoritative:
+			// authoritative nameservers are always queried using plain dns (port 53), which
+			// isn't possible if only encrypted resolvers are configured (e.g. port 53 blocked)
+			if !anyPlainDns(cfg.DnsServices) {
+				service.logger.Errorf("dns checker mode %s requires plain dns (port 53) but only doh/dot dns services are configured, using %s", cfg.Mode, modeRecursive)
+				break
+			}
+			service.logger.Info("dns checker will check authoritative nameservers")
+			service.authoritative = true
+		default:
+			service.logger.Errorf("dns checker mode %s is invalid, using %s", cfg.Mode, modeRecursive)
+		}
 	}
 
 	return service, nil
 }
+
+// anyPlainDns returns true if any of the dns services has a plain dns (ip) server
+func anyPlainDns(dnsServices []DnsServiceIPPair) bool {
+	for i := range dnsServices {
+		if dnsServices[i].Primary != "" || dnsServices[i].Secondary != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"sync"
+	"time"
+)
+
+// demotionTime is how long a provider whose challenge failed validation for an identifier
+// is tried after the identifier's other providers
+const demotionTime = 24 * time.Hour
+
+// failover tracks, for each identifier, the providers whose submitted challenge failed
+// validation. An invalid challenge invalidates the authorization and the order (see:
+// rfc8555 s 7.1.6), so the next provider can only be tried on a new order. When a demotion
+// leaves the identifier with another provider to try, the identifier is flagged so the
+// order fulfiller knows a new order is worth placing.
+type failover struct {
+	demoted map[string]map[string]time.Time // map[identifier]map[providerKey]demotedUntil
+	retry   map[string]time.Time            // map[identifier]flaggedUntil
+	mu      sync.Mutex
+}
+
+// newFailover creates an empty failover tracker
+func newFailover() *failover {
+	return &failover{
+		demoted: make(map[string]map[string]time.Time),
+		retry:   make(map[string]time.Time),
+	}
+}
+
+// failoverIdentifier returns the identifier value as it appears in an order (wildcard
+// authorizations only contain the base domain)
+func failoverIdentifier(identifier acme.Identifier, wildcard bool) string {
+	if wildcard {
+		return "*." + identifier.Value
+	}
+	return identifier.Value
+}
+
+// demotedKeys returns the provider keys currently demoted for the identifier
+func (f *failover) demotedKeys(identifier string) map[string]bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make(map[string]bool)
+	for key, until := range f.demoted[identifier] {
+		if time.Now().After(until) {
+			delete(f.demoted[identifier], key)
+			continue
+		}
+		keys[key] = true
+	}
+
+	return keys
+}
+
+// demote demotes the provider for the identifier. If any of the candidate providers is not
+// demoted, the identifier is flagged for a new order and true is returned.
+func (f *failover) demote(identifier string, providerKey string, candidateKeys []string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.demoted[identifier] == nil {
+		f.demoted[identifier] = make(map[string]time.Time)
+	}
+	f.demoted[identifier][providerKey] = time.Now().Add(demotionTime)
+
+	for _, key := range candidateKeys {
+		until, isDemoted := f.demoted[identifier][key]
+		if !isDemoted || time.Now().After(until) {
+			f.retry[identifier] = time.Now().Add(demotionTime)
+			return true
+		}
+	}
+
+	return false
+}
+
+// restore removes the provider's demotion for the identifier (e.g. once it has validated)
+func (f *failover) restore(identifier string, providerKey string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.demoted[identifier], providerKey)
+	if len(f.demoted[identifier]) == 0 {
+		delete(f.demoted, identifier)
+	}
+}
+
+// TakeFailover returns true if a challenge for any of the (order) identifiers failed
+// validation and that identifier has another provider to try, meaning a new order may
+// succeed where the last one failed. The flags are cleared so each failed validation
+// results in at most one new order.
+func (service *Service) TakeFailover(identifiers acme.IdentifierSlice) bool {
+	f := service.failover
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	found := false
+	for _, identifier := range identifiers {
+		until, flagged := f.retry[identifier.Value]
+		if !flagged {
+			continue
+		}
+
+		delete(f.retry, identifier.Value)
+		if time.Now().Before(until) {
+			found = true
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,55 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"testing"
+)
+
+func TestChallenges_Failover(t *testing.T) {
+	service := &Service{failover: newFailover()}
+	identifier := acme.Identifier{Type: acme.IdentifierTypeDns, Value: "example.com"}
+	wildcardId := failoverIdentifier(identifier, true)
+	plainId := failoverIdentifier(identifier, false)
+	candidates := []string{"first", "second"}
+
+	if wildcardId != "*.example.com" || plainId != "example.com" {
+		t.Fatalf("failover identifiers are '%s' and '%s', expected '*.example.com' and 'example.com'", wildcardId, plainId)
+	}
+
+	// first provider fails, second is left to try
+	if !service.failover.demote(wildcardId, "first", candidates) {
+		t.Error("demote with another provider left returned false")
+	}
+	if demoted := service.failover.demotedKeys(wildcardId); !demoted["first"] || demoted["second"] {
+		t.Errorf("demoted keys are %v, expected only 'first'", demoted)
+	}
+	if len(service.failover.demotedKeys(plainId)) != 0 {
+		t.Error("demotion for wildcard identifier also applied to the plain identifier")
+	}
+
+	// flag is only for the matching order identifier and is only taken once
+	if service.TakeFailover(acme.IdentifierSlice{{Type: acme.IdentifierTypeDns, Value: "example.com"}}) {
+		t.Error("take failover for the plain identifier returned true")
+	}
+	orderIds := acme.IdentifierSlice{{Type: acme.IdentifierTypeDns, Value: "*.example.com"}}
+	if !service.TakeFailover(orderIds) {
+		t.Error("take failover for the wildcard identifier returned false")
+	}
+	if service.TakeFailover(orderIds) {
+		t.Error("take failover returned true a second time")
+	}
+
+	// second provider also fails, nothing left to try
+	if service.failover.demote(wildcardId, "second", candidates) {
+		t.Error("demote with every provider demoted returned true")
+	}
+	if service.TakeFailover(orderIds) {
+		t.Error("take failover with every provider demoted returned true")
+	}
+
+	// a provider that validates is no longer demoted
+	service.failover.restore(wildcardId, "second")
+	if demoted := service.failover.demotedKeys(wildcardId); !demoted["first"] || demoted["second"] {
+		t.Errorf("demoted keys after restore are %v, expected only 'first'", demoted)
+	}
+}
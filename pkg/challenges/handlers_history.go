@@ -0,0 +1,133 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// provider stats window (days)
+const (
+	statsDefaultDays = 30
+	statsMaxDays     = 3650
+)
+
+type challengeHistoryResponse struct {
+	output.JsonResponse
+	TotalEvents int     `json:"total_records"`
+	Events      []Event `json:"events"`
+}
+
+// GetChallengeHistory returns the recorded challenge solving steps, newest first. The
+// results can be filtered with the provider_id, provider_key, identifier and step
+// query params.
+func (service *Service) GetChallengeHistory(w http.ResponseWriter, r *http.Request) *output.Error {
+	// parse pagination and sorting
+	query := pagination_sort.ParseRequestToQuery(r)
+
+	// filter params
+	v := r.URL.Query()
+	filter := EventFilter{
+		ProviderKey:     v.Get("provider_key"),
+		IdentifierValue: v.Get("identifier"),
+		Step:            v.Get("step"),
+	}
+
+	// validation
+	// provider
+	if providerParam := v.Get("provider_id"); providerParam != "" {
+		providerId, err := strconv.Atoi(providerParam)
+		if err != nil || providerId < 0 {
+			service.logger.Debugf("challenge history provider_id %s is not valid", providerParam)
+			return output.ErrValidationFailed
+		}
+		filter.ProviderID = &providerId
+	}
+	// step
+	switch filter.Step {
+	case "", StepProvision, StepPropagation, StepValidation, StepDeprovision:
+	default:
+		service.logger.Debugf("challenge history step %s is not valid", filter.Step)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// get from storage
+	events, totalRows, err := service.storage.GetChallengeEvents(filter, query)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// write response
+	response := &challengeHistoryResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.TotalEvents = totalRows
+	response.Events = events
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
+
+type providerStatsResponse struct {
+	output.JsonResponse
+	Days      int             `json:"days"`
+	Providers []ProviderStats `json:"providers"`
+}
+
+// GetProviderStats returns each provider's challenge success rate, average dns
+// propagation time and failure counts over the last days (query param, default 30).
+func (service *Service) GetProviderStats(w http.ResponseWriter, r *http.Request) *output.Error {
+	// validation
+	// days
+	days := statsDefaultDays
+	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
+		var err error
+		days, err = strconv.Atoi(daysParam)
+		if err != nil || days < 1 || days > statsMaxDays {
+			service.logger.Debugf("provider stats days must be between 1 and %d", statsMaxDays)
+			return output.ErrValidationFailed
+		}
+	}
+	// end validation
+
+	// get from storage
+	since := int(time.Now().Add(-time.Duration(days) * 24 * time.Hour).Unix())
+	stats, err := service.storage.GetChallengeProviderStats(since)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// add the current id of each provider that still exists
+	for i := range stats {
+		p, err := service.Providers.ProviderByKey(stats[i].ProviderKey)
+		if err == nil {
+			providerId := p.ID
+			stats[i].ProviderID = &providerId
+		}
+	}
+
+	// write response
+	response := &providerStatsResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.Days = days
+	response.Providers = stats
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package challenges
+
+import (
+	"encoding/json"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// self test check timeout (seconds)
+const (
+	selfTestDefaultTimeout = 120
+	selfTestMaxTimeout     = 600
+)
+
+// selfTestPayload is used to self test a provider
+type selfTestPayload struct {
+	Domain         string `json:"domain"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+type selfTestResponse struct {
+	output.JsonResponse
+	Result *selfTestResult `json:"result"`
+}
+
+// TestProvider provisions, checks and then deprovisions a random challenge resource for
+// the specified domain using the provider with the ID param. The response reports each
+// step's timing and any error. A failed self test is not an error response.
+func (service *Service) TestProvider(w http.ResponseWriter, r *http.Request) *output.Error {
+	// params
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// decode body into payload
+	var payload selfTestPayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// domain
+	if !validation.DomainValid(payload.Domain, false) && !validation.IPAddressValid(payload.Domain) {
+		service.logger.Debugf("self test domain %s is not a valid domain or ip address", payload.Domain)
+		return output.ErrValidationFailed
+	}
+	// timeout
+	if payload.TimeoutSeconds == 0 {
+		payload.TimeoutSeconds = selfTestDefaultTimeout
+	} else if payload.TimeoutSeconds < 0 || payload.TimeoutSeconds > selfTestMaxTimeout {
+		service.logger.Debugf("self test timeout_seconds must be between 1 and %d", selfTestMaxTimeout)
+		return output.ErrValidationFailed
+	}
+	// provider
+	p, err := service.Providers.ProviderByID(id)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+	// dns-01 cannot validate ip identifiers (see: rfc8738 s 7)
+	identifier := acme.NewIdentifier(payload.Domain)
+	if identifier.Type == acme.IdentifierTypeIp && p.AcmeChallengeType() == acme.ChallengeTypeDns01 {
+		service.logger.Debugf("self test of ip %s cannot use dns-01 provider %d", payload.Domain, id)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// the test runs longer than the server's usual write timeout
+	timeout := time.Duration(payload.TimeoutSeconds) * time.Second
+	err = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(2*timeout + time.Minute))
+	if err != nil {
+		service.logger.Errorf("failed to extend write deadline for provider self test (%s)", err)
+	}
+
+	service.logger.Infof("self testing challenge provider %d (%s) using %s", p.ID, p.Type, identifier.Value)
+	result := service.selfTestProvider(p.ID, p.Key, p.Type, p.Service, identifier.Value, timeout)
+	service.logger.Infof("self test of challenge provider %d (%s) finished (success: %t)", p.ID, p.Type, result.Success)
+
+	// write response
+	response := &selfTestResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.Result = result
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/randomness"
+	"time"
+)
+
+// challenge solving steps recorded in history
+const (
+	StepProvision   = "provision"
+	StepPropagation = "propagation"
+	StepValidation  = "validation"
+	StepDeprovision = "deprovision"
+)
+
+// Event is the record of one step of an attempt to solve a challenge with a
+// provider. All of the steps of one attempt share the same AttemptID.
+type Event struct {
+	ID              int    `json:"id"`
+	AttemptID       string `json:"attempt_id"`
+	ProviderID      int    `json:"provider_id"`
+	ProviderKey     string `json:"provider_key"`
+	ProviderType    string `json:"provider_type"`
+	ChallengeType   string `json:"challenge_type"`
+	IdentifierType  string `json:"identifier_type"`
+	IdentifierValue string `json:"identifier_value"`
+	Step            string `json:"step"`
+	Success         bool   `json:"success"`
+	Error           string `json:"error"`
+	StartedAt       int    `json:"started_at"`
+	DurationMs      int    `json:"duration_ms"`
+}
+
+// EventFilter limits which events are returned from storage. Zero values do not
+// filter.
+type EventFilter struct {
+	ProviderID      *int
+	ProviderKey     string
+	IdentifierValue string
+	Step            string
+}
+
+// ProviderStats is a summary of a provider's challenge solving history. An
+// attempt is successful if its challenge was validated by the ACME server.
+// History is grouped by provider key since provider IDs are reassigned when the
+// app starts. ProviderID is the current ID of the provider with the key, or nil
+// if it no longer exists.
+type ProviderStats struct {
+	ProviderKey         string   `json:"provider_key"`
+	ProviderID          *int     `json:"provider_id"`
+	ProviderType        string   `json:"provider_type"`
+	Attempts            int      `json:"attempts"`
+	SuccessfulAttempts  int      `json:"successful_attempts"`
+	SuccessRate         float64  `json:"success_rate"`
+	ProvisionFailures   int      `json:"provision_failures"`
+	PropagationFailures int      `json:"propagation_failures"`
+	ValidationFailures  int      `json:"validation_failures"`
+	DeprovisionFailures int      `json:"deprovision_failures"`
+	AvgPropagationMs    *float64 `json:"average_propagation_ms"`
+	LastAttemptAt       int      `json:"last_attempt_at"`
+}
+
+// attemptRecorder saves the steps of one attempt to solve a challenge with a
+// provider to storage
+type attemptRecorder struct {
+	service *Service
+	base    Event
+}
+
+// newAttemptRecorder creates a recorder for a new attempt
+func (service *Service) newAttemptRecorder(identifier acme.Identifier, providerID int, providerKey string, providerType string, challengeType acme.ChallengeType) *attemptRecorder {
+	return &attemptRecorder{
+		service: service,
+		base: Event{
+			AttemptID:       randomness.GenerateInsecureString(16),
+			ProviderID:      providerID,
+			ProviderKey:     providerKey,
+			ProviderType:    providerType,
+			ChallengeType:   string(challengeType),
+			IdentifierType:  string(identifier.Type),
+			IdentifierValue: identifier.Value,
+		},
+	}
+}
+
+// record saves the outcome of a step that began at start. A failure to save is
+// logged but otherwise ignored so history never interferes with solving.
+func (rec *attemptRecorder) record(step string, start time.Time, err error) {
+	event := rec.base
+	event.Step = step
+	event.Success = err == nil
+	if err != nil {
+		event.Error = err.Error()
+	}
+	event.StartedAt = int(start.Unix())
+	event.DurationMs = int(time.Since(start).Milliseconds())
+
+	saveErr := rec.service.storage.PostChallengeEvent(event)
+	if saveErr != nil {
+		rec.service.logger.Errorf("failed to save challenge %s history for %s (%s)", step, event.IdentifierValue, saveErr)
+	}
+}
+
+// historyPruneInterval is how often old challenge history is removed
+const historyPruneInterval = 24 * time.Hour
+
+// startHistoryPruner starts a go routine that removes challenge history older than the
+// longest provider stats window, once at start and then every historyPruneInterval
+// until shutdown
+func (service *Service) startHistoryPruner() {
+	service.shutdownWaitgroup.Add(1)
+	go func() {
+		defer service.shutdownWaitgroup.Done()
+
+		for {
+			service.pruneHistory()
+
+			// sleep or wait for shutdown context to be done
+			delayTimer := time.NewTimer(historyPruneInterval)
+
+			select {
+			case <-service.shutdownContext.Done():
+				// ensure timer releases resources
+				if !delayTimer.Stop() {
+					<-delayTimer.C
+				}
+				return
+
+			case <-delayTimer.C:
+				// proceed to next run
+			}
+		}
+	}()
+}
+
+// pruneHistory removes the challenge history events that started more than
+// statsMaxDays ago
+func (service *Service) pruneHistory() {
+	before := int(time.Now().Add(-statsMaxDays * 24 * time.Hour).Unix())
+
+	count, err := service.storage.DeleteChallengeEventsBefore(before)
+	if err != nil {
+		service.logger.Errorf("failed to remove old challenge history (%s)", err)
+		return
+	}
+
+	if count > 0 {
+		service.logger.Infof("removed %d challenge history event(s) older than %d days", count, statsMaxDays)
+	}
+}
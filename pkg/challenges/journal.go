@@ -0,0 +1,140 @@
+package challenges
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/challenges/providers"
+	"time"
+)
+
+// ProvisionedResource is a challenge resource that has been (or is about to be)
+// provisioned. Resources are journaled to storage until they are successfully
+// deprovisioned so anything left behind by the app stopping unexpectedly can be
+// cleaned up at the next start.
+type ProvisionedResource struct {
+	ID           int
+	ProviderID   int
+	ProviderKey  string
+	ProviderType string
+	Domain       string
+	Token        string
+	KeyAuth      string
+	CreatedAt    int
+}
+
+// journalResource saves res to storage and sets its ID. If the provider only keeps
+// resources in memory or saving fails, res is not journaled and its ID is -1.
+func (service *Service) journalResource(res *ProvisionedResource, provider providers.Service) {
+	res.ID = -1
+
+	// nothing will be left behind
+	if inMem, ok := provider.(providers.InMemoryService); ok && inMem.InMemoryResources() {
+		return
+	}
+
+	res.CreatedAt = int(time.Now().Unix())
+
+	id, err := service.storage.PostChallengeResource(*res)
+	if err != nil {
+		service.logger.Errorf("failed to journal challenge resource for %s, it will not be cleaned up if the app stops before deprovisioning (%s)", res.Domain, err)
+		return
+	}
+
+	res.ID = id
+}
+
+// clearJournaledResource removes res from the journal (if it was journaled)
+func (service *Service) clearJournaledResource(res ProvisionedResource) {
+	if res.ID < 0 {
+		return
+	}
+
+	err := service.storage.DeleteChallengeResource(res.ID)
+	if err != nil {
+		service.logger.Errorf("failed to remove challenge resource for %s from journal (%s)", res.Domain, err)
+	}
+}
+
+// cleanupOrphanedResources deprovisions any resources left in the journal (which means
+// the app stopped between provisioning and deprovisioning them). Resources that fail
+// to deprovision stay in the journal and are retried at the next start.
+func (service *Service) cleanupOrphanedResources() {
+	orphans, err := service.storage.GetAllChallengeResources()
+	if err != nil {
+		service.logger.Errorf("failed to get journaled challenge resources, orphaned resources will not be cleaned up (%s)", err)
+		return
+	}
+
+	if len(orphans) == 0 {
+		return
+	}
+
+	service.logger.Infof("cleaning up %d orphaned challenge resource(s)", len(orphans))
+
+	for _, res := range orphans {
+		// stop if shutting down (remaining resources are retried at the next start)
+		if service.shutdownContext.Err() != nil {
+			return
+		}
+
+		provider := service.orphanOwner(res)
+		if provider == nil {
+			service.logger.Warnf("no %s provider for orphaned challenge resource for %s exists anymore, it must be removed manually", res.ProviderType, res.Domain)
+			service.clearJournaledResource(res)
+			continue
+		}
+
+		// don't interfere with a real challenge that may have started for the name
+		err = service.reserveResourceName(res.Domain, true)
+		if err != nil {
+			service.logger.Errorf("failed to clean up orphaned challenge resource for %s (%s)", res.Domain, err)
+			continue
+		}
+
+		err = service.deprovision(res, provider)
+		if err != nil {
+			service.logger.Errorf("failed to clean up orphaned challenge resource for %s, will retry at next start (%s)", res.Domain, err)
+			continue
+		}
+
+		service.logger.Infof("cleaned up orphaned challenge resource for %s", res.Domain)
+	}
+}
+
+// orphanOwner returns the provider service that should deprovision res. That is the
+// provider with the same key, since provider IDs are reassigned when the app starts and
+// another provider of the same type may have different credentials or a different zone.
+// If there is no such provider, nil is returned.
+func (service *Service) orphanOwner(res ProvisionedResource) providers.Service {
+	if res.ProviderKey != "" {
+		p, err := service.Providers.ProviderByKey(res.ProviderKey)
+		if err != nil || p.Type != res.ProviderType {
+			return nil
+		}
+		return p.Service
+	}
+
+	// resources journaled before providers had keys: if the provider with the original
+	// ID is no longer the same type, use a provider of the same type that is used for
+	// the domain instead
+	p, err := service.Providers.ProviderByID(res.ProviderID)
+	if err == nil && p.Type == res.ProviderType {
+		return p.Service
+	}
+
+	// resource name doesn't indicate wildcard, so check both
+	identifier := acme.NewIdentifier(res.Domain)
+	for _, wildcard := range []bool{false, true} {
+		candidates, err := service.Providers.ProvidersFor(identifier, wildcard)
+		if err != nil {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Type == res.ProviderType {
+				return candidate.Service
+			}
+		}
+	}
+
+	return nil
+}
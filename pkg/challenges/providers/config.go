@@ -6,64 +6,125 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
 	"legocerthub-backend/pkg/challenges/providers/dns01goacme"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dns01rfc2136"
+	"legocerthub-backend/pkg/challenges/providers/dns01webhook"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/challenges/providers/http01remote"
+	"legocerthub-backend/pkg/challenges/providers/http01webroot"
+	"legocerthub-backend/pkg/challenges/providers/tlsalpn01internal"
 )
 
 // provider manager configs
 type ConfigManagerHttp01Internal struct {
+	Key                    string   `yaml:"key,omitempty"`
 	Domains                []string `yaml:"domains"`
+	Priority               int      `yaml:"priority,omitempty"`
 	*http01internal.Config `yaml:",inline"`
 }
 
+type ConfigManagerTlsAlpn01Internal struct {
+	Key                       string   `yaml:"key,omitempty"`
+	Domains                   []string `yaml:"domains"`
+	Priority                  int      `yaml:"priority,omitempty"`
+	*tlsalpn01internal.Config `yaml:",inline"`
+}
+
 type ConfigManagerDns01Manual struct {
+	Key                 string   `yaml:"key,omitempty"`
 	Domains             []string `yaml:"domains"`
+	Priority            int      `yaml:"priority,omitempty"`
 	*dns01manual.Config `yaml:",inline"`
 }
 
 type ConfigManagerDns01AcmeDns struct {
+	Key                  string   `yaml:"key,omitempty"`
 	Domains              []string `yaml:"domains"`
+	Priority             int      `yaml:"priority,omitempty"`
 	*dns01acmedns.Config `yaml:",inline"`
 }
 
 type ConfigManagerDns01AcmeSh struct {
+	Key                 string   `yaml:"key,omitempty"`
 	Domains             []string `yaml:"domains"`
+	Priority            int      `yaml:"priority,omitempty"`
 	*dns01acmesh.Config `yaml:",inline"`
 }
 
 type ConfigManagerDns01Cloudflare struct {
+	Key                     string   `yaml:"key,omitempty"`
 	Domains                 []string `yaml:"domains"`
+	Priority                int      `yaml:"priority,omitempty"`
 	*dns01cloudflare.Config `yaml:",inline"`
 }
 
 type ConfigManagerDns01GoAcme struct {
+	Key                 string   `yaml:"key,omitempty"`
 	Domains             []string `yaml:"domains"`
+	Priority            int      `yaml:"priority,omitempty"`
 	*dns01goacme.Config `yaml:",inline"`
 }
 
+type ConfigManagerHttp01Remote struct {
+	Key                  string   `yaml:"key,omitempty"`
+	Domains              []string `yaml:"domains"`
+	Priority             int      `yaml:"priority,omitempty"`
+	*http01remote.Config `yaml:",inline"`
+}
+
+type ConfigManagerHttp01Webroot struct {
+	Key                   string   `yaml:"key,omitempty"`
+	Domains               []string `yaml:"domains"`
+	Priority              int      `yaml:"priority,omitempty"`
+	*http01webroot.Config `yaml:",inline"`
+}
+
+type ConfigManagerDns01Webhook struct {
+	Key                  string   `yaml:"key,omitempty"`
+	Domains              []string `yaml:"domains"`
+	Priority             int      `yaml:"priority,omitempty"`
+	*dns01webhook.Config `yaml:",inline"`
+}
+
+type ConfigManagerDns01Rfc2136 struct {
+	Key                  string   `yaml:"key,omitempty"`
+	Domains              []string `yaml:"domains"`
+	Priority             int      `yaml:"priority,omitempty"`
+	*dns01rfc2136.Config `yaml:",inline"`
+}
+
 // Config contains configurations for all provider types with domains
 type Config struct {
-	Http01InternalConfigs  []ConfigManagerHttp01Internal  `yaml:"http_01_internal,omitempty"`
-	Dns01ManualConfigs     []ConfigManagerDns01Manual     `yaml:"dns_01_manual,omitempty"`
-	Dns01AcmeDnsConfigs    []ConfigManagerDns01AcmeDns    `yaml:"dns_01_acme_dns,omitempty"`
-	Dns01AcmeShConfigs     []ConfigManagerDns01AcmeSh     `yaml:"dns_01_acme_sh,omitempty"`
-	Dns01CloudflareConfigs []ConfigManagerDns01Cloudflare `yaml:"dns_01_cloudflare,omitempty"`
-	Dns01GoAcmeConfigs     []ConfigManagerDns01GoAcme     `yaml:"dns_01_go_acme,omitempty"`
+	Http01InternalConfigs    []ConfigManagerHttp01Internal    `yaml:"http_01_internal,omitempty"`
+	TlsAlpn01InternalConfigs []ConfigManagerTlsAlpn01Internal `yaml:"tls_alpn_01_internal,omitempty"`
+	Dns01ManualConfigs       []ConfigManagerDns01Manual       `yaml:"dns_01_manual,omitempty"`
+	Dns01AcmeDnsConfigs      []ConfigManagerDns01AcmeDns      `yaml:"dns_01_acme_dns,omitempty"`
+	Dns01AcmeShConfigs       []ConfigManagerDns01AcmeSh       `yaml:"dns_01_acme_sh,omitempty"`
+	Dns01CloudflareConfigs   []ConfigManagerDns01Cloudflare   `yaml:"dns_01_cloudflare,omitempty"`
+	Dns01GoAcmeConfigs       []ConfigManagerDns01GoAcme       `yaml:"dns_01_go_acme,omitempty"`
+	Http01RemoteConfigs      []ConfigManagerHttp01Remote      `yaml:"http_01_remote,omitempty"`
+	Http01WebrootConfigs     []ConfigManagerHttp01Webroot     `yaml:"http_01_webroot,omitempty"`
+	Dns01WebhookConfigs      []ConfigManagerDns01Webhook      `yaml:"dns_01_webhook,omitempty"`
+	Dns01Rfc2136Configs      []ConfigManagerDns01Rfc2136      `yaml:"dns_01_rfc2136,omitempty"`
 }
 
 // Len returns the total number of Provider Configs, regardless of type.
 func (cfg Config) Len() int {
 	return len(cfg.Http01InternalConfigs) +
+		len(cfg.TlsAlpn01InternalConfigs) +
 		len(cfg.Dns01ManualConfigs) +
 		len(cfg.Dns01AcmeDnsConfigs) +
 		len(cfg.Dns01AcmeShConfigs) +
 		len(cfg.Dns01CloudflareConfigs) +
-		len(cfg.Dns01GoAcmeConfigs)
+		len(cfg.Dns01GoAcmeConfigs) +
+		len(cfg.Dns01Rfc2136Configs)
 }
 
 // managerProviderConfig is a provider config and additional config for
 // the manager
 type managerProviderConfig struct {
+	key         string
 	domains     []string
+	priority    int
 	providerCfg providerConfig
 }
 
@@ -72,37 +133,89 @@ func (cfg Config) All() []managerProviderConfig {
 	all := []managerProviderConfig{}
 	for _, mgrCfg := range cfg.Dns01AcmeDnsConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
 	for _, mgrCfg := range cfg.Dns01AcmeShConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
 	for _, mgrCfg := range cfg.Dns01CloudflareConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
 	for _, mgrCfg := range cfg.Dns01ManualConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
 	for _, mgrCfg := range cfg.Http01InternalConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
+			providerCfg: mgrCfg.Config,
+		})
+	}
+	for _, mgrCfg := range cfg.TlsAlpn01InternalConfigs {
+		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
+			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
 	for _, mgrCfg := range cfg.Dns01GoAcmeConfigs {
 		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
+			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
+			providerCfg: mgrCfg.Config,
+		})
+	}
+	for _, mgrCfg := range cfg.Dns01Rfc2136Configs {
+		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
+			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
+			providerCfg: mgrCfg.Config,
+		})
+	}
+	for _, mgrCfg := range cfg.Dns01WebhookConfigs {
+		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
+			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
+			providerCfg: mgrCfg.Config,
+		})
+	}
+	for _, mgrCfg := range cfg.Http01WebrootConfigs {
+		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
+			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
+			providerCfg: mgrCfg.Config,
+		})
+	}
+	for _, mgrCfg := range cfg.Http01RemoteConfigs {
+		all = append(all, managerProviderConfig{
+			key:         mgrCfg.Key,
 			domains:     mgrCfg.Domains,
+			priority:    mgrCfg.Priority,
 			providerCfg: mgrCfg.Config,
 		})
 	}
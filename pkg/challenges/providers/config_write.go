@@ -8,7 +8,12 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
 	"legocerthub-backend/pkg/challenges/providers/dns01goacme"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dns01rfc2136"
+	"legocerthub-backend/pkg/challenges/providers/dns01webhook"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/challenges/providers/http01remote"
+	"legocerthub-backend/pkg/challenges/providers/http01webroot"
+	"legocerthub-backend/pkg/challenges/providers/tlsalpn01internal"
 	"os"
 
 	"gopkg.in/yaml.v3"
@@ -27,48 +32,110 @@ func (mgr *Manager) unsafeWriteProvidersConfig() error {
 		case *http01internal.Config:
 			mgrCfg.Http01InternalConfigs = append(mgrCfg.Http01InternalConfigs,
 				ConfigManagerHttp01Internal{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
+				},
+			)
+
+		case *tlsalpn01internal.Config:
+			mgrCfg.TlsAlpn01InternalConfigs = append(mgrCfg.TlsAlpn01InternalConfigs,
+				ConfigManagerTlsAlpn01Internal{
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
 		case *dns01manual.Config:
 			mgrCfg.Dns01ManualConfigs = append(mgrCfg.Dns01ManualConfigs,
 				ConfigManagerDns01Manual{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
 		case *dns01acmedns.Config:
 			mgrCfg.Dns01AcmeDnsConfigs = append(mgrCfg.Dns01AcmeDnsConfigs,
 				ConfigManagerDns01AcmeDns{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
 		case *dns01acmesh.Config:
 			mgrCfg.Dns01AcmeShConfigs = append(mgrCfg.Dns01AcmeShConfigs,
 				ConfigManagerDns01AcmeSh{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
 		case *dns01cloudflare.Config:
 			mgrCfg.Dns01CloudflareConfigs = append(mgrCfg.Dns01CloudflareConfigs,
 				ConfigManagerDns01Cloudflare{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
 		case *dns01goacme.Config:
 			mgrCfg.Dns01GoAcmeConfigs = append(mgrCfg.Dns01GoAcmeConfigs,
 				ConfigManagerDns01GoAcme{
-					Domains: p.Domains,
-					Config:  realCfg,
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
+				},
+			)
+
+		case *dns01rfc2136.Config:
+			mgrCfg.Dns01Rfc2136Configs = append(mgrCfg.Dns01Rfc2136Configs,
+				ConfigManagerDns01Rfc2136{
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
+				},
+			)
+
+		case *dns01webhook.Config:
+			mgrCfg.Dns01WebhookConfigs = append(mgrCfg.Dns01WebhookConfigs,
+				ConfigManagerDns01Webhook{
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
+				},
+			)
+
+		case *http01webroot.Config:
+			mgrCfg.Http01WebrootConfigs = append(mgrCfg.Http01WebrootConfigs,
+				ConfigManagerHttp01Webroot{
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
+				},
+			)
+
+		case *http01remote.Config:
+			mgrCfg.Http01RemoteConfigs = append(mgrCfg.Http01RemoteConfigs,
+				ConfigManagerHttp01Remote{
+					Key:      p.Key,
+					Domains:  p.Domains,
+					Priority: p.Priority,
+					Config:   realCfg,
 				},
 			)
 
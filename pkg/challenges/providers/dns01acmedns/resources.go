@@ -79,6 +79,17 @@ func (service *Service) getAcmeDnsResource(domain string) (*acmeDnsResource, err
 	return nil, fmt.Errorf("acme-dns resource not found for %s", domain)
 }
 
+// DelegatedFqdn returns the acme-dns full domain that the record for domain is
+// written to
+func (service *Service) DelegatedFqdn(domain string) (string, bool) {
+	adr, err := service.getAcmeDnsResource(domain)
+	if err != nil {
+		return "", false
+	}
+
+	return adr.FullDomain, true
+}
+
 // Provision updates the acme-dns resource corresponding to domain with
 // the new value calculated from keyAuth
 func (service *Service) Provision(domain, _, keyAuth string) error {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/secrets"
 
 	"go.uber.org/zap"
 )
@@ -41,6 +42,15 @@ type Config struct {
 	Resources   []acmeDnsResource `yaml:"resources" json:"resources"`
 }
 
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	fields := []secrets.Field{}
+	for i := range cfg.Resources {
+		fields = append(fields, secrets.Field{Value: &cfg.Resources[i].Password})
+	}
+	return fields
+}
+
 // NewService creates a new service
 func NewService(app App, cfg *Config) (*Service, error) {
 	// if no config, error
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/datatypes/environment"
+	"legocerthub-backend/pkg/secrets"
 	"os"
 	"os/exec"
 	"runtime"
@@ -54,6 +55,11 @@ type Config struct {
 	DnsHook     string   `yaml:"dns_hook" json:"dns_hook"`
 }
 
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	return secrets.KeyValueFields(cfg.Environment)
+}
+
 // NewService creates a new service
 func NewService(app App, cfg *Config) (*Service, error) {
 	// error and fail if trying to run on windows
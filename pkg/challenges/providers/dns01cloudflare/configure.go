@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/secrets"
 	"time"
 
 	"github.com/cloudflare/cloudflare-go"
@@ -29,6 +30,15 @@ type Config struct {
 	ApiToken *string `yaml:"api_token,omitempty" json:"api_token,omitempty"`
 }
 
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	fields := []secrets.Field{{Value: cfg.ApiToken}}
+	if cfg.Account != nil {
+		fields = append(fields, secrets.Field{Value: cfg.Account.GlobalApiKey})
+	}
+	return fields
+}
+
 // redactedIdentifier selects the correct identifier field and then returns the identifier
 // in its redacted form
 func (cfg *Config) redactedIdentifier() string {
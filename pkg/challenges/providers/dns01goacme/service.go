@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/datatypes/environment"
+	"legocerthub-backend/pkg/secrets"
 	"os"
 
 	goacme_challenge "github.com/go-acme/lego/v4/challenge"
@@ -34,6 +35,11 @@ type Config struct {
 	Environment []string `yaml:"environment" json:"environment"`
 }
 
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	return secrets.KeyValueFields(cfg.Environment)
+}
+
 // provider Service struct
 type Service struct {
 	logger         *zap.SugaredLogger
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/datatypes/environment"
+	"legocerthub-backend/pkg/secrets"
 	"os/exec"
 
 	"go.uber.org/zap"
@@ -43,6 +44,11 @@ type Config struct {
 	DeleteScript string   `yaml:"delete_script" json:"delete_script"`
 }
 
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	return secrets.KeyValueFields(cfg.Environment)
+}
+
 // NewService creates a new service
 func NewService(app App, cfg *Config) (*Service, error) {
 	// if no config, error
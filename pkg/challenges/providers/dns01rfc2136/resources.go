@@ -0,0 +1,111 @@
+package dns01rfc2136
+
+import (
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// tsigFudge is the permitted clock skew (seconds) between LeGo and the nameserver
+const tsigFudge = 300
+
+// Provision adds the corresponding TXT record using a TSIG signed dynamic update
+func (service *Service) Provision(domain, _, keyAuth string) error {
+	// get dns record
+	dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	err := service.update(dnsRecordName, dnsRecordValue, true)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Deprovision removes the corresponding TXT record using a TSIG signed dynamic update
+func (service *Service) Deprovision(domain, _, keyAuth string) error {
+	// get dns record
+	dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	err := service.update(dnsRecordName, dnsRecordValue, false)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// update sends an UPDATE message (see: rfc2136) to the nameserver that either adds
+// or removes the specified TXT record. Only the record with the specified value is
+// removed, so other (e.g. concurrent) challenge records for the same name are left
+// in place.
+func (service *Service) update(dnsRecordName, dnsRecordValue string, add bool) error {
+	fqdn := dns.Fqdn(dnsRecordName)
+
+	zone, err := service.findZone(fqdn)
+	if err != nil {
+		return err
+	}
+
+	rr := &dns.TXT{
+		Hdr: dns.RR_Header{
+			Name:   fqdn,
+			Rrtype: dns.TypeTXT,
+			Class:  dns.ClassINET,
+			Ttl:    service.ttl,
+		},
+		Txt: []string{dnsRecordValue},
+	}
+
+	msg := new(dns.Msg)
+	msg.SetUpdate(zone)
+	if add {
+		msg.Insert([]dns.RR{rr})
+	} else {
+		msg.Remove([]dns.RR{rr})
+	}
+	msg.SetTsig(service.tsigKeyName, service.tsigAlgorithm, tsigFudge, time.Now().Unix())
+
+	resp, _, err := service.dnsClient.Exchange(msg, service.nameserver)
+	if err != nil {
+		return fmt.Errorf("dns-01 rfc2136 update of %s failed (%w)", fqdn, err)
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("dns-01 rfc2136 update of %s failed (%s)", fqdn, dns.RcodeToString[resp.Rcode])
+	}
+
+	return nil
+}
+
+// findZone returns the configured zone, or if none was configured, the zone containing
+// fqdn according to the SOA record the nameserver returns for fqdn
+func (service *Service) findZone(fqdn string) (string, error) {
+	if service.zone != "" {
+		return service.zone, nil
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(fqdn, dns.TypeSOA)
+
+	// unsigned, the tsig key may only be permitted to send updates
+	client := &dns.Client{
+		Net:     service.dnsClient.Net,
+		Timeout: service.dnsClient.Timeout,
+	}
+
+	resp, _, err := client.Exchange(msg, service.nameserver)
+	if err != nil {
+		return "", fmt.Errorf("dns-01 rfc2136 failed to find zone of %s (%w)", fqdn, err)
+	}
+
+	// SOA is in the answer if fqdn is the zone apex, otherwise in the authority section
+	for _, rr := range append(resp.Answer, resp.Ns...) {
+		if soa, ok := rr.(*dns.SOA); ok {
+			return soa.Hdr.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("dns-01 rfc2136 failed to find zone of %s (nameserver returned no soa)", fqdn)
+}
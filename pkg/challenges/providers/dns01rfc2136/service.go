@@ -0,0 +1,181 @@
+package dns01rfc2136
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/secrets"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent  = errors.New("necessary dns-01 rfc2136 component is missing")
+	errNameserverMissing = errors.New("dns-01 rfc2136 nameserver must be specified")
+	errTsigMissing       = errors.New("dns-01 rfc2136 tsig key name and secret must be specified")
+	errTsigSecretBad     = errors.New("dns-01 rfc2136 tsig secret must be base64 encoded")
+)
+
+// defaults
+const (
+	defaultPort           = "53"
+	defaultTsigAlgorithm  = "hmac-sha256"
+	defaultTtl            = 60
+	defaultTimeoutSeconds = 10
+)
+
+// supported tsig algorithms
+var tsigAlgorithms = map[string]string{
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+}
+
+// Configuration options
+type Config struct {
+	// nameserver to send updates to (host or host:port, port defaults to 53)
+	Nameserver string `yaml:"nameserver" json:"nameserver"`
+	// zone to update; if blank, the zone is determined by querying the nameserver
+	// for the record's SOA
+	Zone string `yaml:"zone" json:"zone"`
+	// tsig key
+	TsigKeyName   string `yaml:"tsig_key_name" json:"tsig_key_name"`
+	TsigAlgorithm string `yaml:"tsig_algorithm" json:"tsig_algorithm"`
+	TsigSecret    string `yaml:"tsig_secret" json:"tsig_secret"`
+	// optional
+	Ttl            int  `yaml:"ttl" json:"ttl"`
+	TimeoutSeconds int  `yaml:"timeout_seconds" json:"timeout_seconds"`
+	UseTcp         bool `yaml:"use_tcp" json:"use_tcp"`
+}
+
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	return []secrets.Field{{Value: &cfg.TsigSecret}}
+}
+
+// provider Service struct
+type Service struct {
+	logger        *zap.SugaredLogger
+	nameserver    string
+	zone          string
+	tsigKeyName   string
+	tsigAlgorithm string
+	tsigSecret    string
+	ttl           uint32
+	dnsClient     *dns.Client
+}
+
+// ChallengeType returns the ACME Challenge Type this provider uses, which is dns-01
+func (service *Service) AcmeChallengeType() acme.ChallengeType {
+	return acme.ChallengeTypeDns01
+}
+
+// Stop is used for any actions needed prior to deleting this provider. If no actions
+// are needed, it is just a no-op.
+func (service *Service) Stop() error { return nil }
+
+// NewService creates a new service
+func NewService(app App, cfg *Config) (*Service, error) {
+	// if no config, error
+	if cfg == nil {
+		return nil, errServiceComponent
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// nameserver (add default port if none)
+	if cfg.Nameserver == "" {
+		return nil, errNameserverMissing
+	}
+	service.nameserver = cfg.Nameserver
+	if _, _, err := net.SplitHostPort(service.nameserver); err != nil {
+		service.nameserver = net.JoinHostPort(strings.Trim(service.nameserver, "[]"), defaultPort)
+	}
+
+	// zone (optional)
+	if cfg.Zone != "" {
+		service.zone = dns.Fqdn(cfg.Zone)
+	}
+
+	// tsig
+	if cfg.TsigKeyName == "" || cfg.TsigSecret == "" {
+		return nil, errTsigMissing
+	}
+	service.tsigKeyName = dns.Fqdn(cfg.TsigKeyName)
+
+	_, err := base64.StdEncoding.DecodeString(cfg.TsigSecret)
+	if err != nil {
+		return nil, errTsigSecretBad
+	}
+	service.tsigSecret = cfg.TsigSecret
+
+	algName := strings.ToLower(cfg.TsigAlgorithm)
+	if algName == "" {
+		algName = defaultTsigAlgorithm
+	}
+	var ok bool
+	service.tsigAlgorithm, ok = tsigAlgorithms[algName]
+	if !ok {
+		return nil, fmt.Errorf("dns-01 rfc2136 tsig algorithm %s is not supported (use hmac-sha256 or hmac-sha512)", cfg.TsigAlgorithm)
+	}
+
+	// ttl
+	service.ttl = defaultTtl
+	if cfg.Ttl > 0 {
+		service.ttl = uint32(cfg.Ttl)
+	}
+
+	// dns client
+	timeout := defaultTimeoutSeconds * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	network := "udp"
+	if cfg.UseTcp {
+		network = "tcp"
+	}
+
+	service.dnsClient = &dns.Client{
+		Net:        network,
+		Timeout:    timeout,
+		TsigSecret: map[string]string{service.tsigKeyName: service.tsigSecret},
+	}
+
+	return service, nil
+}
+
+// Update Service updates the Service to use the new config
+func (service *Service) UpdateService(app App, cfg *Config) error {
+	// if no config, error
+	if cfg == nil {
+		return errServiceComponent
+	}
+
+	// don't need to do anything with "old" Service, just set a new one
+	newServ, err := NewService(app, cfg)
+	if err != nil {
+		return err
+	}
+
+	// set content of old pointer so anything with the pointer calls the
+	// updated service
+	*service = *newServ
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package dns01rfc2136
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+const (
+	testZone        = "example.com."
+	testTsigKeyName = "lego-test."
+	testTsigSecret  = "c2VjcmV0LXRzaWcta2V5LWZvci10ZXN0aW5nLW9ubHk="
+)
+
+// testApp satisfies App
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger { return zap.NewNop().Sugar() }
+
+// testNameserver is an in-process nameserver that accepts TSIG signed updates for
+// testZone and records the update section of each one
+type testNameserver struct {
+	addr string
+
+	mu         sync.Mutex
+	updates    [][]dns.RR
+	algorithms []string
+	badTsig    int
+}
+
+// startTestNameserver starts a udp nameserver that trusts the specified tsig key
+func startTestNameserver(t *testing.T, tsigSecret string) *testNameserver {
+	ns := &testNameserver{}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns.addr = pc.LocalAddr().String()
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        pc,
+		TsigSecret:        map[string]string{testTsigKeyName: tsigSecret},
+		NotifyStartedFunc: func() { close(started) },
+		// the default accept func rejects updates
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+		Handler:       dns.HandlerFunc(ns.serveDNS),
+	}
+
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return ns
+}
+
+// serveDNS answers SOA queries for testZone unsigned, and applies signed updates
+func (ns *testNameserver) serveDNS(w dns.ResponseWriter, r *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(r)
+
+	switch r.Opcode {
+	case dns.OpcodeQuery:
+		resp.Ns = []dns.RR{&dns.SOA{
+			Hdr:    dns.RR_Header{Name: testZone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+			Ns:     "ns1." + testZone,
+			Mbox:   "admin." + testZone,
+			Serial: 1,
+		}}
+
+	case dns.OpcodeUpdate:
+		tsig := r.IsTsig()
+		if tsig == nil || w.TsigStatus() != nil {
+			ns.mu.Lock()
+			ns.badTsig++
+			ns.mu.Unlock()
+
+			resp.Rcode = dns.RcodeNotAuth
+			break
+		}
+
+		if len(r.Question) != 1 || r.Question[0].Name != testZone {
+			resp.Rcode = dns.RcodeNotZone
+			break
+		}
+
+		ns.mu.Lock()
+		ns.updates = append(ns.updates, r.Ns)
+		ns.algorithms = append(ns.algorithms, tsig.Algorithm)
+		ns.mu.Unlock()
+
+		// sign the response with the same key
+		resp.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsigFudge, time.Now().Unix())
+
+	default:
+		resp.Rcode = dns.RcodeNotImplemented
+	}
+
+	_ = w.WriteMsg(resp)
+}
+
+// checkTxtUpdate verifies an update section contains only the TXT record with the
+// expected class (INET to add, NONE to remove)
+func checkTxtUpdate(t *testing.T, desc string, update []dns.RR, name string, value string, class uint16) {
+	if len(update) != 1 {
+		t.Errorf("%s: update contained %d records, expected 1", desc, len(update))
+		return
+	}
+
+	txt, ok := update[0].(*dns.TXT)
+	if !ok {
+		t.Errorf("%s: update record is %s, expected TXT", desc, dns.TypeToString[update[0].Header().Rrtype])
+		return
+	}
+	if txt.Hdr.Name != name {
+		t.Errorf("%s: update record name is %s, expected %s", desc, txt.Hdr.Name, name)
+	}
+	if txt.Hdr.Class != class {
+		t.Errorf("%s: update record class is %s, expected %s", desc, dns.ClassToString[txt.Hdr.Class], dns.ClassToString[class])
+	}
+	if len(txt.Txt) != 1 || txt.Txt[0] != value {
+		t.Errorf("%s: update record value is %v, expected %s", desc, txt.Txt, value)
+	}
+}
+
+func TestDns01Rfc2136_SignedUpdate(t *testing.T) {
+	domain := "www.example.com"
+	keyAuth := "token.thumbprint"
+	recordName, recordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	for _, algorithm := range []string{"hmac-sha256", "hmac-sha512"} {
+		// with and without a configured zone (zone found by SOA query)
+		for _, zone := range []string{"", "example.com"} {
+			desc := algorithm + " zone '" + zone + "'"
+			ns := startTestNameserver(t, testTsigSecret)
+
+			service, err := NewService(testApp{}, &Config{
+				Nameserver:    ns.addr,
+				Zone:          zone,
+				TsigKeyName:   "lego-test",
+				TsigAlgorithm: algorithm,
+				TsigSecret:    testTsigSecret,
+			})
+			if err != nil {
+				t.Fatalf("%s: failed to make service (%s)", desc, err)
+			}
+
+			err = service.Provision(domain, "token", keyAuth)
+			if err != nil {
+				t.Fatalf("%s: provision failed (%s)", desc, err)
+			}
+			err = service.Deprovision(domain, "token", keyAuth)
+			if err != nil {
+				t.Fatalf("%s: deprovision failed (%s)", desc, err)
+			}
+
+			ns.mu.Lock()
+			if ns.badTsig != 0 {
+				t.Errorf("%s: nameserver rejected %d updates for bad tsig", desc, ns.badTsig)
+			}
+			if len(ns.updates) != 2 {
+				t.Fatalf("%s: nameserver received %d updates, expected 2", desc, len(ns.updates))
+			}
+			for _, usedAlgorithm := range ns.algorithms {
+				if usedAlgorithm != tsigAlgorithms[algorithm] {
+					t.Errorf("%s: update signed with %s", desc, usedAlgorithm)
+				}
+			}
+			checkTxtUpdate(t, desc+" add", ns.updates[0], dns.Fqdn(recordName), recordValue, dns.ClassINET)
+			checkTxtUpdate(t, desc+" remove", ns.updates[1], dns.Fqdn(recordName), recordValue, dns.ClassNONE)
+			ns.mu.Unlock()
+		}
+	}
+}
+
+func TestDns01Rfc2136_WrongTsigSecret(t *testing.T) {
+	ns := startTestNameserver(t, "ZGlmZmVyZW50LXNlY3JldC1rZXktbm90LXRydXN0ZWQ=")
+
+	for _, algorithm := range []string{"hmac-sha256", "hmac-sha512"} {
+		service, err := NewService(testApp{}, &Config{
+			Nameserver:    ns.addr,
+			Zone:          "example.com",
+			TsigKeyName:   "lego-test",
+			TsigAlgorithm: algorithm,
+			TsigSecret:    testTsigSecret,
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to make service (%s)", algorithm, err)
+		}
+
+		err = service.Provision("www.example.com", "token", "token.thumbprint")
+		if err == nil {
+			t.Errorf("%s: provision with wrong tsig secret succeeded", algorithm)
+		}
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if len(ns.updates) != 0 {
+		t.Errorf("nameserver applied %d updates signed with the wrong secret", len(ns.updates))
+	}
+	if ns.badTsig != 2 {
+		t.Errorf("nameserver rejected %d updates for bad tsig, expected 2", ns.badTsig)
+	}
+}
@@ -0,0 +1,106 @@
+package dns01webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"legocerthub-backend/pkg/acme"
+	"strconv"
+	"time"
+)
+
+// webhook actions
+const (
+	actionProvision   = "provision"
+	actionDeprovision = "deprovision"
+)
+
+// timestampHeader is sent with signed requests and is included in the signature
+// so the receiver can reject replayed requests
+const timestampHeader = "X-LeGo-Timestamp"
+
+// webhookPayload is the body POSTed to the webhook
+type webhookPayload struct {
+	Fqdn   string `json:"fqdn"`
+	Value  string `json:"value"`
+	Action string `json:"action"`
+}
+
+// postWebhook POSTs the record and action to the webhook. Any 2xx response is
+// considered success.
+func (service *Service) postWebhook(dnsRecordName, dnsRecordValue, action string) error {
+	// marshal for posting
+	payloadJson, err := json.Marshal(webhookPayload{
+		Fqdn:   dnsRecordName + ".",
+		Value:  dnsRecordValue,
+		Action: action,
+	})
+	if err != nil {
+		return err
+	}
+
+	// configured headers
+	header := service.headers.Clone()
+
+	// sign (sha256 hmac of "<timestamp>.<body>")
+	if service.hmacSecret != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		mac := hmac.New(sha256.New, service.hmacSecret)
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(payloadJson)
+
+		header.Set(timestampHeader, timestamp)
+		header.Set(service.hmacHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), service.timeout)
+	defer cancel()
+
+	resp, err := service.httpClient.PostWithHeaderWithContext(ctx, service.url, "application/json", bytes.NewBuffer(payloadJson), header)
+	if err != nil {
+		return fmt.Errorf("dns-01 webhook %s of %s failed (%w)", action, dnsRecordName, err)
+	}
+
+	// read body & close
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	// if not status 2xx, error
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("dns-01 webhook %s of %s failed (status %d)", action, dnsRecordName, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Provision calls the webhook to create the dns record
+func (service *Service) Provision(domain, _, keyAuth string) error {
+	// get dns record
+	dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	err := service.postWebhook(dnsRecordName, dnsRecordValue, actionProvision)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Deprovision calls the webhook to delete the dns record
+func (service *Service) Deprovision(domain, _, keyAuth string) error {
+	// get dns record
+	dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	err := service.postWebhook(dnsRecordName, dnsRecordValue, actionDeprovision)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package dns01webhook
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/secrets"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary dns-01 webhook component is missing")
+	errUrlBad           = errors.New("dns-01 webhook url must be a valid http or https url")
+)
+
+// defaults
+const (
+	defaultTimeoutSeconds = 30
+	defaultHmacHeader     = "X-LeGo-Signature"
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetHttpClient() *httpclient.Client
+}
+
+// Configuration options
+type Config struct {
+	// endpoint that is POSTed to on provision and deprovision
+	Url string `yaml:"url" json:"url"`
+	// additional headers to send (e.g. for authorization)
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// if set, the body is signed using hmac-sha256 and the signature is sent in
+	// the hmac header
+	HmacSecret string `yaml:"hmac_secret" json:"hmac_secret"`
+	HmacHeader string `yaml:"hmac_header" json:"hmac_header"`
+	// how long to wait for the endpoint to respond
+	TimeoutSeconds int `yaml:"timeout_seconds" json:"timeout_seconds"`
+}
+
+// SecretFields returns the config values that contain secrets (header values
+// commonly contain credentials so all are treated as secret)
+func (cfg *Config) SecretFields() []secrets.Field {
+	return append(secrets.MapFields(cfg.Headers), secrets.Field{Value: &cfg.HmacSecret})
+}
+
+// provider Service struct
+type Service struct {
+	logger     *zap.SugaredLogger
+	httpClient *httpclient.Client
+	url        string
+	headers    http.Header
+	hmacSecret []byte
+	hmacHeader string
+	timeout    time.Duration
+}
+
+// ChallengeType returns the ACME Challenge Type this provider uses, which is dns-01
+func (service *Service) AcmeChallengeType() acme.ChallengeType {
+	return acme.ChallengeTypeDns01
+}
+
+// Stop is used for any actions needed prior to deleting this provider. If no actions
+// are needed, it is just a no-op.
+func (service *Service) Stop() error { return nil }
+
+// NewService creates a new service
+func NewService(app App, cfg *Config) (*Service, error) {
+	// if no config, error
+	if cfg == nil {
+		return nil, errServiceComponent
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// http client
+	service.httpClient = app.GetHttpClient()
+	if service.httpClient == nil {
+		return nil, errServiceComponent
+	}
+
+	// url
+	u, err := url.Parse(cfg.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errUrlBad
+	}
+	service.url = cfg.Url
+
+	// headers
+	service.headers = make(http.Header)
+	for k, v := range cfg.Headers {
+		service.headers.Set(k, v)
+	}
+
+	// hmac (optional)
+	if cfg.HmacSecret != "" {
+		service.hmacSecret = []byte(cfg.HmacSecret)
+		service.hmacHeader = cfg.HmacHeader
+		if service.hmacHeader == "" {
+			service.hmacHeader = defaultHmacHeader
+		}
+	}
+
+	// timeout
+	service.timeout = defaultTimeoutSeconds * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		service.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	return service, nil
+}
+
+// Update Service updates the Service to use the new config
+func (service *Service) UpdateService(app App, cfg *Config) error {
+	// if no config, error
+	if cfg == nil {
+		return errServiceComponent
+	}
+
+	// don't need to do anything with "old" Service, just set a new one
+	newServ, err := NewService(app, cfg)
+	if err != nil {
+		return err
+	}
+
+	// set content of old pointer so anything with the pointer calls the
+	// updated service
+	*service = *newServ
+
+	return nil
+}
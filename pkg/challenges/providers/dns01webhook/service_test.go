@@ -0,0 +1,225 @@
+package dns01webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/httpclient"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const testHmacSecret = "webhook-hmac-secret-for-testing"
+
+// testApp satisfies App
+type testApp struct{}
+
+func (testApp) GetLogger() *zap.SugaredLogger     { return zap.NewNop().Sugar() }
+func (testApp) GetHttpClient() *httpclient.Client { return httpclient.New("lego-test") }
+
+// testRequest is what the test webhook received in one request
+type testRequest struct {
+	method string
+	header http.Header
+	body   []byte
+}
+
+// testWebhook is an httptest server that records each request and responds with
+// the configured status (after the configured delay)
+type testWebhook struct {
+	server *httptest.Server
+	status int
+	delay  time.Duration
+
+	mu       sync.Mutex
+	requests []testRequest
+}
+
+// startTestWebhook starts a webhook server that responds with status
+func startTestWebhook(t *testing.T, status int, delay time.Duration) *testWebhook {
+	wh := &testWebhook{status: status, delay: delay}
+
+	wh.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		wh.mu.Lock()
+		wh.requests = append(wh.requests, testRequest{method: r.Method, header: r.Header.Clone(), body: body})
+		wh.mu.Unlock()
+
+		select {
+		case <-time.After(wh.delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		w.WriteHeader(wh.status)
+	}))
+	t.Cleanup(wh.server.Close)
+
+	return wh
+}
+
+// checkSignature verifies the request carries a current timestamp and a signature of
+// "<timestamp>.<body>" in the specified header
+func checkSignature(t *testing.T, desc string, req testRequest, hmacHeader string) {
+	timestamp := req.header.Get(timestampHeader)
+	unixTime, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Errorf("%s: timestamp header is '%s', expected unix time", desc, timestamp)
+		return
+	}
+	if age := time.Since(time.Unix(unixTime, 0)); age < -time.Minute || age > time.Minute {
+		t.Errorf("%s: timestamp is %s old, expected current", desc, age)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testHmacSecret))
+	mac.Write([]byte(timestamp + "." + string(req.body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if signature := req.header.Get(hmacHeader); signature != expected {
+		t.Errorf("%s: signature header '%s' is '%s', expected '%s'", desc, hmacHeader, signature, expected)
+	}
+}
+
+func TestDns01Webhook_Requests(t *testing.T) {
+	domain := "www.example.com"
+	keyAuth := "token.thumbprint"
+	recordName, recordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+	// default and custom signature header, and unsigned
+	for _, hmacHeader := range []string{"", "X-Custom-Signature", "unsigned"} {
+		desc := "hmac header '" + hmacHeader + "'"
+		wh := startTestWebhook(t, http.StatusNoContent, 0)
+
+		cfg := &Config{
+			Url: wh.server.URL + "/hook",
+			Headers: map[string]string{
+				"Authorization": "Bearer test-token",
+				"X-Extra":       "extra-value",
+			},
+		}
+		if hmacHeader != "unsigned" {
+			cfg.HmacSecret = testHmacSecret
+			cfg.HmacHeader = hmacHeader
+		}
+
+		service, err := NewService(testApp{}, cfg)
+		if err != nil {
+			t.Fatalf("%s: failed to make service (%s)", desc, err)
+		}
+
+		err = service.Provision(domain, "token", keyAuth)
+		if err != nil {
+			t.Fatalf("%s: provision failed (%s)", desc, err)
+		}
+		err = service.Deprovision(domain, "token", keyAuth)
+		if err != nil {
+			t.Fatalf("%s: deprovision failed (%s)", desc, err)
+		}
+
+		wh.mu.Lock()
+		if len(wh.requests) != 2 {
+			t.Fatalf("%s: webhook received %d requests, expected 2", desc, len(wh.requests))
+		}
+
+		for i, action := range []string{actionProvision, actionDeprovision} {
+			req := wh.requests[i]
+			reqDesc := desc + " " + action
+
+			if req.method != http.MethodPost {
+				t.Errorf("%s: method is %s, expected POST", reqDesc, req.method)
+			}
+			if contentType := req.header.Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("%s: content type is '%s', expected 'application/json'", reqDesc, contentType)
+			}
+
+			// body fields
+			payload := webhookPayload{}
+			err = json.Unmarshal(req.body, &payload)
+			if err != nil {
+				t.Errorf("%s: failed to unmarshal body (%s)", reqDesc, err)
+			}
+			if payload.Fqdn != recordName+"." || payload.Value != recordValue || payload.Action != action {
+				t.Errorf("%s: body is %+v, expected fqdn '%s.', value '%s', action '%s'", reqDesc, payload, recordName, recordValue, action)
+			}
+
+			// custom headers
+			if req.header.Get("Authorization") != "Bearer test-token" || req.header.Get("X-Extra") != "extra-value" {
+				t.Errorf("%s: custom headers missing or wrong (%v)", reqDesc, req.header)
+			}
+
+			// signature
+			switch hmacHeader {
+			case "unsigned":
+				if req.header.Get(timestampHeader) != "" || req.header.Get(defaultHmacHeader) != "" {
+					t.Errorf("%s: unsigned request has signature headers", reqDesc)
+				}
+			case "":
+				checkSignature(t, reqDesc, req, defaultHmacHeader)
+			default:
+				checkSignature(t, reqDesc, req, hmacHeader)
+			}
+		}
+		wh.mu.Unlock()
+	}
+}
+
+func TestDns01Webhook_Failures(t *testing.T) {
+	// non-2xx responses
+	for _, status := range []int{http.StatusMultipleChoices, http.StatusUnauthorized, http.StatusInternalServerError} {
+		wh := startTestWebhook(t, status, 0)
+
+		service, err := NewService(testApp{}, &Config{Url: wh.server.URL})
+		if err != nil {
+			t.Fatalf("status %d: failed to make service (%s)", status, err)
+		}
+
+		err = service.Provision("www.example.com", "token", "token.thumbprint")
+		if err == nil {
+			t.Errorf("status %d: provision succeeded, expected error", status)
+		}
+		err = service.Deprovision("www.example.com", "token", "token.thumbprint")
+		if err == nil {
+			t.Errorf("status %d: deprovision succeeded, expected error", status)
+		}
+	}
+
+	// webhook responds slower than the timeout
+	wh := startTestWebhook(t, http.StatusOK, 5*time.Second)
+
+	service, err := NewService(testApp{}, &Config{Url: wh.server.URL, TimeoutSeconds: 1})
+	if err != nil {
+		t.Fatalf("timeout: failed to make service (%s)", err)
+	}
+	if service.timeout != time.Second {
+		t.Errorf("timeout: service timeout is %s, expected 1s", service.timeout)
+	}
+	service.timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	err = service.Provision("www.example.com", "token", "token.thumbprint")
+	if err == nil {
+		t.Error("timeout: provision succeeded, expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("timeout: provision took %s, expected it to stop at the timeout", elapsed)
+	}
+}
+
+func TestDns01Webhook_BadUrl(t *testing.T) {
+	for _, u := range []string{"", "example.com/hook", "ftp://example.com/hook", "http://"} {
+		_, err := NewService(testApp{}, &Config{Url: u})
+		if err != errUrlBad {
+			t.Errorf("url '%s' returned error '%v', expected bad url error", u, err)
+		}
+	}
+}
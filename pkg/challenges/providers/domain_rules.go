@@ -0,0 +1,306 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/validation"
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// A provider's domains are rules that decide which identifiers the provider is used
+// for. The forms are:
+//   - 'example.com': example.com and all of its subdomains
+//   - 'children:example.com': only direct children of example.com (e.g. foo.example.com
+//     but not example.com or foo.bar.example.com)
+//   - 'regex:<pattern>': identifiers the pattern matches (the whole identifier must match)
+//   - '192.168.1.10' or '10.0.0.0/8': an ip address or ip prefix
+//   - '*': catch all
+//
+// Domain rules (other than '*' and ip rules) may be limited to wildcard identifiers
+// with the 'wildcard:' qualifier or to non-wildcard identifiers with the 'plain:'
+// qualifier (e.g. 'wildcard:example.com'). Any rule other than '*' may be made an
+// exclusion by prefixing it with '!', identifiers an exclusion matches are never
+// routed to that provider.
+const (
+	ruleExcludePrefix   = "!"
+	ruleWildcardPrefix  = "wildcard:"
+	rulePlainPrefix     = "plain:"
+	ruleChildrenPrefix  = "children:"
+	ruleRegexPrefix     = "regex:"
+	ruleCatchAllPattern = "*"
+)
+
+// ruleKind is the type of domain rule
+type ruleKind int
+
+const (
+	ruleKindDomain ruleKind = iota
+	ruleKindChildren
+	ruleKindRegex
+	ruleKindIpAddress
+	ruleKindIpPrefix
+	ruleKindCatchAll
+)
+
+// wildcardScope limits which identifiers a rule applies to
+type wildcardScope int
+
+const (
+	wildcardScopeAny wildcardScope = iota
+	wildcardScopeOnly
+	wildcardScopeNever
+)
+
+// domainRule is a parsed provider domain
+type domainRule struct {
+	raw      string
+	exclude  bool
+	scope    wildcardScope
+	kind     ruleKind
+	domain   string
+	regex    *regexp.Regexp
+	ipAddr   netip.Addr
+	ipPrefix netip.Prefix
+}
+
+// match tiers, from least to most specific; when more than one provider matches an
+// identifier, only the providers with the most specific match are used
+const (
+	matchTierCatchAll = iota + 1
+	matchTierSubdomain
+	matchTierRegex
+	matchTierExact
+)
+
+// matchScore is how specifically a rule matched an identifier. Within a tier, a
+// higher specificity wins (e.g. a longer parent domain or a longer ip prefix).
+type matchScore struct {
+	tier        int
+	specificity int
+}
+
+// moreSpecificThan returns true if score is more specific than other
+func (score matchScore) moreSpecificThan(other matchScore) bool {
+	if score.tier != other.tier {
+		return score.tier > other.tier
+	}
+	return score.specificity > other.specificity
+}
+
+// parseDomainRule parses a single provider domain into a domainRule
+func parseDomainRule(raw string) (domainRule, error) {
+	rule := domainRule{raw: raw}
+	s := raw
+
+	// catch all
+	if s == ruleCatchAllPattern {
+		rule.kind = ruleKindCatchAll
+		return rule, nil
+	}
+
+	// exclusion
+	if after, found := strings.CutPrefix(s, ruleExcludePrefix); found {
+		rule.exclude = true
+		s = after
+	}
+
+	// wildcard qualifier
+	if after, found := strings.CutPrefix(s, ruleWildcardPrefix); found {
+		rule.scope = wildcardScopeOnly
+		s = after
+	} else if after, found := strings.CutPrefix(s, rulePlainPrefix); found {
+		rule.scope = wildcardScopeNever
+		s = after
+	}
+
+	// regex
+	if pattern, found := strings.CutPrefix(s, ruleRegexPrefix); found {
+		if pattern == "" {
+			return domainRule{}, fmt.Errorf("domain rule %s has an empty regex", raw)
+		}
+
+		var err error
+		rule.kind = ruleKindRegex
+		rule.regex, err = regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return domainRule{}, fmt.Errorf("domain rule %s has an invalid regex (%s)", raw, err)
+		}
+		return rule, nil
+	}
+
+	// children
+	if parent, found := strings.CutPrefix(s, ruleChildrenPrefix); found {
+		if !validation.DomainValid(parent, false) {
+			return domainRule{}, fmt.Errorf("domain rule %s does not have a validly formatted parent domain", raw)
+		}
+
+		rule.kind = ruleKindChildren
+		rule.domain = parent
+		return rule, nil
+	}
+
+	// ip address or ip prefix (wildcard qualifiers don't apply to ips)
+	if validation.IPAddressValid(s) || validation.IPPrefixValid(s) {
+		if rule.scope != wildcardScopeAny {
+			return domainRule{}, fmt.Errorf("domain rule %s: ip addresses and prefixes cannot be wildcard qualified", raw)
+		}
+
+		if addr, err := netip.ParseAddr(s); err == nil {
+			rule.kind = ruleKindIpAddress
+			rule.ipAddr = addr
+		} else {
+			rule.kind = ruleKindIpPrefix
+			rule.ipPrefix = netip.MustParsePrefix(s)
+		}
+		return rule, nil
+	}
+
+	// plain domain
+	if !validation.DomainValid(s, false) {
+		if s == ruleCatchAllPattern {
+			return domainRule{}, fmt.Errorf("domain rule %s: wildcard domain * cannot be qualified or excluded", raw)
+		}
+		return domainRule{}, fmt.Errorf("domain %s is not a validly formatted domain, ip address, ip prefix, or rule", raw)
+	}
+
+	rule.kind = ruleKindDomain
+	rule.domain = s
+	return rule, nil
+}
+
+// parseDomainRules parses all of a provider's domains and verifies they are usable
+// together. There must be at least one rule that is not an exclusion and the catch
+// all '*' may only be combined with exclusions.
+func parseDomainRules(domains []string) ([]domainRule, error) {
+	// if there are none, invalid
+	if len(domains) <= 0 {
+		return nil, errors.New("provider doesn't have any domains (must have at least 1)")
+	}
+
+	rules := make([]domainRule, 0, len(domains))
+	hasCatchAll := false
+	includeCount := 0
+
+	for _, domain := range domains {
+		rule, err := parseDomainRule(domain)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.kind == ruleKindCatchAll {
+			hasCatchAll = true
+		}
+		if !rule.exclude {
+			includeCount++
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if includeCount == 0 {
+		return nil, errors.New("provider only has exclusion domains (must have at least 1 that is not an exclusion)")
+	}
+	if hasCatchAll && includeCount > 1 {
+		return nil, errors.New("when using wildcard domain * it must be the only specified domain on the provider (other than exclusions)")
+	}
+
+	return rules, nil
+}
+
+// matches returns how specifically the rule matches the identifier. If the rule
+// does not match, false is returned.
+func (rule domainRule) matches(identifier acme.Identifier, wildcard bool) (matchScore, bool) {
+	switch rule.scope {
+	case wildcardScopeOnly:
+		if !wildcard {
+			return matchScore{}, false
+		}
+	case wildcardScopeNever:
+		if wildcard {
+			return matchScore{}, false
+		}
+	}
+
+	if rule.kind == ruleKindCatchAll {
+		return matchScore{tier: matchTierCatchAll}, true
+	}
+
+	// ip identifiers only match ip rules
+	if identifier.Type == acme.IdentifierTypeIp {
+		addr, err := netip.ParseAddr(identifier.Value)
+		if err != nil {
+			return matchScore{}, false
+		}
+
+		switch rule.kind {
+		case ruleKindIpAddress:
+			if rule.ipAddr == addr {
+				return matchScore{tier: matchTierExact}, true
+			}
+		case ruleKindIpPrefix:
+			if rule.ipPrefix.Contains(addr) {
+				return matchScore{tier: matchTierSubdomain, specificity: rule.ipPrefix.Bits()}, true
+			}
+		}
+
+		return matchScore{}, false
+	}
+
+	// dns identifier
+	value := identifier.Value
+	switch rule.kind {
+	case ruleKindDomain:
+		if value == rule.domain {
+			return matchScore{tier: matchTierExact}, true
+		}
+		// include period to avoid matching something like hellodomain.com to domain.com
+		if strings.HasSuffix(value, "."+rule.domain) {
+			// a longer parent is more specific; at the same parent, children: wins
+			return matchScore{tier: matchTierSubdomain, specificity: 2 * len(rule.domain)}, true
+		}
+
+	case ruleKindChildren:
+		child, found := strings.CutSuffix(value, "."+rule.domain)
+		if found && child != "" && !strings.Contains(child, ".") {
+			return matchScore{tier: matchTierSubdomain, specificity: 2*len(rule.domain) + 1}, true
+		}
+
+	case ruleKindRegex:
+		if rule.regex.MatchString(value) {
+			return matchScore{tier: matchTierRegex}, true
+		}
+	}
+
+	return matchScore{}, false
+}
+
+// description returns a human readable explanation of why the rule matched
+func (rule domainRule) description() string {
+	var desc string
+	switch rule.kind {
+	case ruleKindDomain:
+		desc = fmt.Sprintf("is or is a subdomain of %s", rule.domain)
+	case ruleKindChildren:
+		desc = fmt.Sprintf("is a direct child of %s", rule.domain)
+	case ruleKindRegex:
+		desc = fmt.Sprintf("matches regex %s", rule.regex.String())
+	case ruleKindIpAddress:
+		desc = fmt.Sprintf("is ip address %s", rule.ipAddr)
+	case ruleKindIpPrefix:
+		desc = fmt.Sprintf("is within ip prefix %s", rule.ipPrefix)
+	case ruleKindCatchAll:
+		desc = "matches catch all *"
+	}
+
+	switch rule.scope {
+	case wildcardScopeOnly:
+		desc += " (wildcard identifiers only)"
+	case wildcardScopeNever:
+		desc += " (non-wildcard identifiers only)"
+	}
+
+	return desc
+}
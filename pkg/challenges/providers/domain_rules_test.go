@@ -0,0 +1,209 @@
+package providers
+
+import (
+	"legocerthub-backend/pkg/acme"
+	"testing"
+)
+
+// parseDomainRuleTest is a provider domain and what it should parse to
+type parseDomainRuleTest struct {
+	raw     string
+	valid   bool
+	kind    ruleKind
+	exclude bool
+	scope   wildcardScope
+}
+
+var parseDomainRuleTests = []parseDomainRuleTest{
+	// valid
+	{raw: "*", valid: true, kind: ruleKindCatchAll},
+	{raw: "example.com", valid: true, kind: ruleKindDomain},
+	{raw: "wildcard:example.com", valid: true, kind: ruleKindDomain, scope: wildcardScopeOnly},
+	{raw: "plain:example.com", valid: true, kind: ruleKindDomain, scope: wildcardScopeNever},
+	{raw: "!example.com", valid: true, kind: ruleKindDomain, exclude: true},
+	{raw: "!wildcard:example.com", valid: true, kind: ruleKindDomain, exclude: true, scope: wildcardScopeOnly},
+	{raw: "children:example.com", valid: true, kind: ruleKindChildren},
+	{raw: "plain:children:example.com", valid: true, kind: ruleKindChildren, scope: wildcardScopeNever},
+	{raw: "regex:web[0-9]+\\.example\\.com", valid: true, kind: ruleKindRegex},
+	{raw: "!regex:.*\\.internal\\.example\\.com", valid: true, kind: ruleKindRegex, exclude: true},
+	{raw: "192.168.1.10", valid: true, kind: ruleKindIpAddress},
+	{raw: "2001:db8::1", valid: true, kind: ruleKindIpAddress},
+	{raw: "10.0.0.0/8", valid: true, kind: ruleKindIpPrefix},
+	{raw: "!10.1.0.0/16", valid: true, kind: ruleKindIpPrefix, exclude: true},
+	{raw: "2001:db8::/32", valid: true, kind: ruleKindIpPrefix},
+
+	// invalid
+	{raw: ""},
+	{raw: "!*"},
+	{raw: "wildcard:*"},
+	{raw: "*.example.com"},
+	{raw: "example"},
+	{raw: "regex:"},
+	{raw: "regex:web[0-9"},
+	{raw: "children:"},
+	{raw: "children:example"},
+	{raw: "wildcard:10.0.0.0/8"},
+	{raw: "plain:192.168.1.10"},
+	{raw: "10.0.0.0/33"},
+}
+
+func TestProviders_ParseDomainRule(t *testing.T) {
+	for _, test := range parseDomainRuleTests {
+		rule, err := parseDomainRule(test.raw)
+
+		if !test.valid {
+			if err == nil {
+				t.Errorf("invalid domain rule test case '%s' returned valid", test.raw)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("valid domain rule test case '%s' returned invalid (%s)", test.raw, err)
+			continue
+		}
+		if rule.kind != test.kind {
+			t.Errorf("domain rule test case '%s' parsed kind %d, expected %d", test.raw, rule.kind, test.kind)
+		}
+		if rule.exclude != test.exclude {
+			t.Errorf("domain rule test case '%s' parsed exclude %t, expected %t", test.raw, rule.exclude, test.exclude)
+		}
+		if rule.scope != test.scope {
+			t.Errorf("domain rule test case '%s' parsed scope %d, expected %d", test.raw, rule.scope, test.scope)
+		}
+	}
+}
+
+// parseDomainRulesTest is a provider's full domains list and if it is usable
+var parseDomainRulesTests = []struct {
+	domains []string
+	valid   bool
+}{
+	{domains: []string{"*"}, valid: true},
+	{domains: []string{"*", "!example.com", "!10.0.0.0/8"}, valid: true},
+	{domains: []string{"example.com", "!children:example.com"}, valid: true},
+	{domains: []string{}, valid: false},
+	{domains: []string{"!example.com"}, valid: false},
+	{domains: []string{"*", "example.com"}, valid: false},
+}
+
+func TestProviders_ParseDomainRules(t *testing.T) {
+	for _, test := range parseDomainRulesTests {
+		_, err := parseDomainRules(test.domains)
+		if test.valid && err != nil {
+			t.Errorf("valid domains test case %v returned invalid (%s)", test.domains, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("invalid domains test case %v returned valid", test.domains)
+		}
+	}
+}
+
+// testService is a provider Service that only reports its challenge type
+type testService struct {
+	challType acme.ChallengeType
+}
+
+func (s *testService) AcmeChallengeType() acme.ChallengeType    { return s.challType }
+func (s *testService) Provision(string, string, string) error   { return nil }
+func (s *testService) Deprovision(string, string, string) error { return nil }
+func (s *testService) Stop() error                              { return nil }
+
+// newTestProvider makes a provider with the specified domains and challenge type
+func newTestProvider(t *testing.T, id int, priority int, challType acme.ChallengeType, domains ...string) *provider {
+	rules, err := parseDomainRules(domains)
+	if err != nil {
+		t.Fatalf("test provider %d domains invalid (%s)", id, err)
+	}
+
+	return &provider{
+		ID:       id,
+		Domains:  domains,
+		Priority: priority,
+		Service:  &testService{challType: challType},
+		rules:    rules,
+	}
+}
+
+// providerResolutionTest is an identifier and the ids of the providers that should be
+// selected for it (in the order they will be tried)
+type providerResolutionTest struct {
+	name       string
+	identifier string
+	wildcard   bool
+	expectIDs  []int
+}
+
+var providerResolutionTests = []providerResolutionTest{
+	// catch all only when nothing else matches
+	{name: "unmatched uses catch all", identifier: "other.org", expectIDs: []int{0}},
+
+	// exact beats regex beats subdomain beats catch all
+	{name: "exact domain (same tier, by priority)", identifier: "example.com", expectIDs: []int{2, 1}},
+	{name: "regex beats subdomain", identifier: "web12.example.com", expectIDs: []int{3}},
+	{name: "subdomain", identifier: "api.example.com", expectIDs: []int{2, 1}},
+
+	// longer parent and children: are more specific
+	{name: "longer parent domain", identifier: "host.lab.example.com", expectIDs: []int{4}},
+	{name: "children: beats subdomain of same parent", identifier: "db.corp.example.com", expectIDs: []int{5}},
+	{name: "children: only direct children", identifier: "a.db.corp.example.com", expectIDs: []int{2, 1}},
+
+	// exclusions
+	{name: "excluded from more specific provider", identifier: "secret.lab.example.com", expectIDs: []int{2, 1}},
+	{name: "excluded from catch all", identifier: "blocked.net", expectIDs: []int{}},
+
+	// wildcard and plain scopes
+	{name: "wildcard scope", identifier: "shop.example.com", wildcard: true, expectIDs: []int{6}},
+	{name: "plain scope skips wildcard", identifier: "lab.example.com", wildcard: true, expectIDs: []int{6}},
+	{name: "plain scope", identifier: "shop.example.com", expectIDs: []int{2, 1}},
+
+	// ip addresses and prefixes
+	{name: "ip exact beats prefix", identifier: "10.1.2.3", expectIDs: []int{8}},
+	{name: "longer ip prefix", identifier: "10.1.9.9", expectIDs: []int{9}},
+	{name: "shorter ip prefix", identifier: "10.9.9.9", expectIDs: []int{7}},
+	{name: "ip excluded from prefix", identifier: "10.200.0.1", expectIDs: []int{0}},
+	{name: "ipv6 prefix", identifier: "2001:db8::5", expectIDs: []int{9}},
+}
+
+func TestProviders_Resolution(t *testing.T) {
+	http01 := acme.ChallengeTypeHttp01
+	dns01 := acme.ChallengeTypeDns01
+
+	mgr := &Manager{
+		providers: []*provider{
+			newTestProvider(t, 0, 0, http01, "*", "!blocked.net"),
+			newTestProvider(t, 1, 5, dns01, "example.com", "wildcard:example.com"),
+			// same tier and specificity as 1, lower priority number is tried first
+			newTestProvider(t, 2, 1, dns01, "plain:example.com"),
+			newTestProvider(t, 3, 0, http01, "regex:web[0-9]+\\.example\\.com"),
+			newTestProvider(t, 4, 0, dns01, "plain:lab.example.com", "!secret.lab.example.com"),
+			newTestProvider(t, 5, 0, http01, "children:corp.example.com"),
+			newTestProvider(t, 6, 0, dns01, "wildcard:shop.example.com", "wildcard:lab.example.com"),
+			newTestProvider(t, 7, 0, http01, "10.0.0.0/8", "!10.200.0.0/16"),
+			newTestProvider(t, 8, 0, http01, "10.1.2.3"),
+			newTestProvider(t, 9, 0, http01, "10.1.0.0/16", "2001:db8::/32"),
+		},
+	}
+
+	for _, test := range providerResolutionTests {
+		matches, err := mgr.unsafeMatchProviders(acme.NewIdentifier(test.identifier), test.wildcard)
+		if err != nil {
+			t.Errorf("provider resolution test case '%s' returned error (%s)", test.name, err)
+			continue
+		}
+
+		selectedIDs := []int{}
+		for _, m := range matches {
+			if m.Result == matchResultSelected {
+				selectedIDs = append(selectedIDs, m.ProviderID)
+			}
+		}
+
+		same := len(selectedIDs) == len(test.expectIDs)
+		for i := 0; same && i < len(selectedIDs); i++ {
+			same = selectedIDs[i] == test.expectIDs[i]
+		}
+		if !same {
+			t.Errorf("provider resolution test case '%s' (%s) selected providers %v, expected %v", test.name, test.identifier, selectedIDs, test.expectIDs)
+		}
+	}
+}
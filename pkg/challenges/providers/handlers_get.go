@@ -1,9 +1,12 @@
 package providers
 
 import (
+	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/validation"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -21,7 +24,7 @@ func (mgr *Manager) GetAllProviders(w http.ResponseWriter, r *http.Request) *out
 	// read all providers
 	var allProviders []provider
 	for _, p := range mgr.providers {
-		allProviders = append(allProviders, *p)
+		allProviders = append(allProviders, mgr.redactedProvider(p))
 	}
 
 	// write response
@@ -106,7 +109,8 @@ func (mgr *Manager) GetOneProvider(w http.ResponseWriter, r *http.Request) *outp
 	response := &providerResponse{}
 	response.StatusCode = http.StatusOK
 	response.Message = "ok"
-	response.Provider = p
+	redactedP := mgr.redactedProvider(p)
+	response.Provider = &redactedP
 
 	// return response to client
 	err = mgr.output.WriteJSON(w, response)
@@ -117,3 +121,52 @@ func (mgr *Manager) GetOneProvider(w http.ResponseWriter, r *http.Request) *outp
 
 	return nil
 }
+
+type identifierMatchResponse struct {
+	output.JsonResponse
+	Identifier acme.Identifier `json:"identifier"`
+	Wildcard   bool            `json:"wildcard"`
+	Matches    []providerMatch `json:"matches"`
+}
+
+// GetProvidersForIdentifier returns which providers would be used to solve a challenge
+// for the identifier query param and why. The providers that would be tried are listed
+// first (in the order they would be tried) followed by any other providers that have a
+// rule matching the identifier. A wildcard identifier is specified with a '*.' prefix.
+func (mgr *Manager) GetProvidersForIdentifier(w http.ResponseWriter, r *http.Request) *output.Error {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+
+	// params
+	value := r.URL.Query().Get("identifier")
+	if !validation.DomainValid(value, true) && !validation.IPAddressValid(value) {
+		mgr.logger.Debugf("identifier %s is not a valid domain or ip address", value)
+		return output.ErrValidationFailed
+	}
+
+	// ACME wildcard identifiers don't include the '*.' (see: rfc8555 s 7.1.3)
+	value, wildcard := strings.CutPrefix(value, "*.")
+	identifier := acme.NewIdentifier(value)
+
+	matches, err := mgr.unsafeMatchProviders(identifier, wildcard)
+	if err != nil {
+		mgr.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// write response
+	response := &identifierMatchResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.Identifier = identifier
+	response.Wildcard = wildcard
+	response.Matches = matches
+
+	err = mgr.output.WriteJSON(w, response)
+	if err != nil {
+		mgr.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
@@ -7,7 +7,12 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
 	"legocerthub-backend/pkg/challenges/providers/dns01goacme"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dns01rfc2136"
+	"legocerthub-backend/pkg/challenges/providers/dns01webhook"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/challenges/providers/http01remote"
+	"legocerthub-backend/pkg/challenges/providers/http01webroot"
+	"legocerthub-backend/pkg/challenges/providers/tlsalpn01internal"
 	"legocerthub-backend/pkg/output"
 	"net/http"
 )
@@ -17,13 +22,21 @@ type newPayload struct {
 	// mandatory
 	Domains []string `json:"domains"`
 
+	// optional (lower is tried first)
+	Priority int `json:"priority"`
+
 	// + only one of these
-	Http01InternalConfig  *http01internal.Config  `json:"http_01_internal,omitempty"`
-	Dns01ManualConfig     *dns01manual.Config     `json:"dns_01_manual,omitempty"`
-	Dns01AcmeDnsConfig    *dns01acmedns.Config    `json:"dns_01_acme_dns,omitempty"`
-	Dns01AcmeShConfig     *dns01acmesh.Config     `json:"dns_01_acme_sh,omitempty"`
-	Dns01CloudflareConfig *dns01cloudflare.Config `json:"dns_01_cloudflare,omitempty"`
-	Dns01GoAcmeConfig     *dns01goacme.Config     `json:"dns_01_go_acme,omitempty"`
+	Http01InternalConfig    *http01internal.Config    `json:"http_01_internal,omitempty"`
+	TlsAlpn01InternalConfig *tlsalpn01internal.Config `json:"tls_alpn_01_internal,omitempty"`
+	Dns01ManualConfig       *dns01manual.Config       `json:"dns_01_manual,omitempty"`
+	Dns01AcmeDnsConfig      *dns01acmedns.Config      `json:"dns_01_acme_dns,omitempty"`
+	Dns01AcmeShConfig       *dns01acmesh.Config       `json:"dns_01_acme_sh,omitempty"`
+	Dns01CloudflareConfig   *dns01cloudflare.Config   `json:"dns_01_cloudflare,omitempty"`
+	Dns01GoAcmeConfig       *dns01goacme.Config       `json:"dns_01_go_acme,omitempty"`
+	Http01RemoteConfig      *http01remote.Config      `json:"http_01_remote,omitempty"`
+	Http01WebrootConfig     *http01webroot.Config     `json:"http_01_webroot,omitempty"`
+	Dns01WebhookConfig      *dns01webhook.Config      `json:"dns_01_webhook,omitempty"`
+	Dns01Rfc2136Config      *dns01rfc2136.Config      `json:"dns_01_rfc2136,omitempty"`
 }
 
 // CreateProvider creates a new provider using the specified configuration.
@@ -44,6 +57,9 @@ func (mgr *Manager) CreateProvider(w http.ResponseWriter, r *http.Request) *outp
 	if payload.Http01InternalConfig != nil {
 		configCount++
 	}
+	if payload.TlsAlpn01InternalConfig != nil {
+		configCount++
+	}
 	if payload.Dns01ManualConfig != nil {
 		configCount++
 	}
@@ -59,6 +75,18 @@ func (mgr *Manager) CreateProvider(w http.ResponseWriter, r *http.Request) *outp
 	if payload.Dns01GoAcmeConfig != nil {
 		configCount++
 	}
+	if payload.Http01RemoteConfig != nil {
+		configCount++
+	}
+	if payload.Http01WebrootConfig != nil {
+		configCount++
+	}
+	if payload.Dns01WebhookConfig != nil {
+		configCount++
+	}
+	if payload.Dns01Rfc2136Config != nil {
+		configCount++
+	}
 	if configCount != 1 {
 		mgr.logger.Debugf("new provider expects 1 config, received %d", configCount)
 		return output.ErrValidationFailed
@@ -67,22 +95,37 @@ func (mgr *Manager) CreateProvider(w http.ResponseWriter, r *http.Request) *outp
 	// try to add the specified provider (actual action)
 	var p *provider
 	if payload.Http01InternalConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Http01InternalConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Http01InternalConfig)
+
+	} else if payload.TlsAlpn01InternalConfig != nil {
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.TlsAlpn01InternalConfig)
 
 	} else if payload.Dns01ManualConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Dns01ManualConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01ManualConfig)
 
 	} else if payload.Dns01AcmeDnsConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Dns01AcmeDnsConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01AcmeDnsConfig)
 
 	} else if payload.Dns01AcmeShConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Dns01AcmeShConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01AcmeShConfig)
 
 	} else if payload.Dns01CloudflareConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Dns01CloudflareConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01CloudflareConfig)
 
 	} else if payload.Dns01GoAcmeConfig != nil {
-		p, err = mgr.unsafeAddProvider(payload.Domains, payload.Dns01GoAcmeConfig)
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01GoAcmeConfig)
+
+	} else if payload.Http01RemoteConfig != nil {
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Http01RemoteConfig)
+
+	} else if payload.Http01WebrootConfig != nil {
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Http01WebrootConfig)
+
+	} else if payload.Dns01WebhookConfig != nil {
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01WebhookConfig)
+
+	} else if payload.Dns01Rfc2136Config != nil {
+		p, err = mgr.unsafeAddProvider("", payload.Domains, payload.Priority, payload.Dns01Rfc2136Config)
 
 	} else {
 		mgr.logger.Error("new provider cfg missing, this error should never trigger though, report lego bug")
@@ -105,7 +148,8 @@ func (mgr *Manager) CreateProvider(w http.ResponseWriter, r *http.Request) *outp
 	response := &providerResponse{}
 	response.StatusCode = http.StatusCreated
 	response.Message = "created provider"
-	response.Provider = p
+	redactedP := mgr.redactedProvider(p)
+	response.Provider = &redactedP
 
 	err = mgr.output.WriteJSON(w, response)
 	if err != nil {
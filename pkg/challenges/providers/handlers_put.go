@@ -7,7 +7,12 @@ import (
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
 	"legocerthub-backend/pkg/challenges/providers/dns01goacme"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dns01rfc2136"
+	"legocerthub-backend/pkg/challenges/providers/dns01webhook"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/challenges/providers/http01remote"
+	"legocerthub-backend/pkg/challenges/providers/http01webroot"
+	"legocerthub-backend/pkg/challenges/providers/tlsalpn01internal"
 	"legocerthub-backend/pkg/output"
 	"net/http"
 	"strconv"
@@ -22,15 +27,21 @@ type modifyPayload struct {
 	Tag string `json:"tag"`
 
 	// optional
-	Domains []string `json:"domains,omitempty"`
+	Domains  []string `json:"domains,omitempty"`
+	Priority *int     `json:"priority,omitempty"`
 
 	// plus only one of these
-	Http01InternalConfig  *http01internal.Config  `json:"http_01_internal,omitempty"`
-	Dns01ManualConfig     *dns01manual.Config     `json:"dns_01_manual,omitempty"`
-	Dns01AcmeDnsConfig    *dns01acmedns.Config    `json:"dns_01_acme_dns,omitempty"`
-	Dns01AcmeShConfig     *dns01acmesh.Config     `json:"dns_01_acme_sh,omitempty"`
-	Dns01CloudflareConfig *dns01cloudflare.Config `json:"dns_01_cloudflare,omitempty"`
-	Dns01GoAcmeConfig     *dns01goacme.Config     `json:"dns_01_go_acme,omitempty"`
+	Http01InternalConfig    *http01internal.Config    `json:"http_01_internal,omitempty"`
+	TlsAlpn01InternalConfig *tlsalpn01internal.Config `json:"tls_alpn_01_internal,omitempty"`
+	Dns01ManualConfig       *dns01manual.Config       `json:"dns_01_manual,omitempty"`
+	Dns01AcmeDnsConfig      *dns01acmedns.Config      `json:"dns_01_acme_dns,omitempty"`
+	Dns01AcmeShConfig       *dns01acmesh.Config       `json:"dns_01_acme_sh,omitempty"`
+	Dns01CloudflareConfig   *dns01cloudflare.Config   `json:"dns_01_cloudflare,omitempty"`
+	Dns01GoAcmeConfig       *dns01goacme.Config       `json:"dns_01_go_acme,omitempty"`
+	Http01RemoteConfig      *http01remote.Config      `json:"http_01_remote,omitempty"`
+	Http01WebrootConfig     *http01webroot.Config     `json:"http_01_webroot,omitempty"`
+	Dns01WebhookConfig      *dns01webhook.Config      `json:"dns_01_webhook,omitempty"`
+	Dns01Rfc2136Config      *dns01rfc2136.Config      `json:"dns_01_rfc2136,omitempty"`
 }
 
 // ModifyProvider modifies the provider specified by the ID in manager with the specified
@@ -78,8 +89,9 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 	}
 
 	// if domains included, validate domains
+	var rules []domainRule
 	if payload.Domains != nil {
-		err = mgr.unsafeValidateDomains(payload.Domains, p)
+		rules, err = mgr.unsafeValidateDomains(payload.Domains, p)
 		if err != nil {
 			mgr.logger.Debugf("failed to validate domains (%s)", err)
 			return output.ErrValidationFailed
@@ -93,6 +105,10 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 		configCount++
 		pCfg = payload.Http01InternalConfig
 	}
+	if payload.TlsAlpn01InternalConfig != nil {
+		configCount++
+		pCfg = payload.TlsAlpn01InternalConfig
+	}
 	if payload.Dns01ManualConfig != nil {
 		configCount++
 		pCfg = payload.Dns01ManualConfig
@@ -113,12 +129,36 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 		configCount++
 		pCfg = payload.Dns01GoAcmeConfig
 	}
+	if payload.Http01RemoteConfig != nil {
+		configCount++
+		pCfg = payload.Http01RemoteConfig
+	}
+	if payload.Http01WebrootConfig != nil {
+		configCount++
+		pCfg = payload.Http01WebrootConfig
+	}
+	if payload.Dns01WebhookConfig != nil {
+		configCount++
+		pCfg = payload.Dns01WebhookConfig
+	}
+	if payload.Dns01Rfc2136Config != nil {
+		configCount++
+		pCfg = payload.Dns01Rfc2136Config
+	}
 
 	// check config count, also error on wrong config type
 	if configCount > 1 {
 		mgr.logger.Debugf("update provider expects max 1 config, received %d", configCount)
 		return output.ErrValidationFailed
 	} else if configCount == 1 {
+		// resolve secrets for the service, keep reference form for the provider config
+		var refCfg providerConfig
+		refCfg, err = mgr.prepareConfig(pCfg)
+		if err != nil {
+			mgr.logger.Debugf("failed to prepare provider config secrets (%s)", err)
+			return output.ErrValidationFailed
+		}
+
 		// update provider service first (if cfg specified) so if fails, domains are unchanged
 		switch pServ := p.Service.(type) {
 		case *http01internal.Service:
@@ -128,6 +168,13 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 			}
 			err = pServ.UpdateService(mgr.childApp, payload.Http01InternalConfig)
 
+		case *tlsalpn01internal.Service:
+			if payload.TlsAlpn01InternalConfig == nil {
+				mgr.logger.Debug("update provider wrong config received")
+				return output.ErrValidationFailed
+			}
+			err = pServ.UpdateService(mgr.childApp, payload.TlsAlpn01InternalConfig)
+
 		case *dns01manual.Service:
 			if payload.Dns01ManualConfig == nil {
 				mgr.logger.Debug("update provider wrong config received")
@@ -163,6 +210,34 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 			}
 			err = pServ.UpdateService(mgr.childApp, payload.Dns01GoAcmeConfig)
 
+		case *http01remote.Service:
+			if payload.Http01RemoteConfig == nil {
+				mgr.logger.Debug("update provider wrong config received")
+				return output.ErrValidationFailed
+			}
+			err = pServ.UpdateService(mgr.childApp, payload.Http01RemoteConfig)
+
+		case *http01webroot.Service:
+			if payload.Http01WebrootConfig == nil {
+				mgr.logger.Debug("update provider wrong config received")
+				return output.ErrValidationFailed
+			}
+			err = pServ.UpdateService(mgr.childApp, payload.Http01WebrootConfig)
+
+		case *dns01webhook.Service:
+			if payload.Dns01WebhookConfig == nil {
+				mgr.logger.Debug("update provider wrong config received")
+				return output.ErrValidationFailed
+			}
+			err = pServ.UpdateService(mgr.childApp, payload.Dns01WebhookConfig)
+
+		case *dns01rfc2136.Service:
+			if payload.Dns01Rfc2136Config == nil {
+				mgr.logger.Debug("update provider wrong config received")
+				return output.ErrValidationFailed
+			}
+			err = pServ.UpdateService(mgr.childApp, payload.Dns01Rfc2136Config)
+
 		default:
 			// default fail
 			mgr.logger.Error("provider service is unsupported, please report this as a lego bug")
@@ -176,11 +251,16 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 		}
 
 		// success, update config
-		p.Config = pCfg
+		p.Config = refCfg
 	}
 
 	// actually do domains update
-	mgr.unsafeUpdateProviderDomains(p, payload.Domains)
+	mgr.unsafeUpdateProviderDomains(p, payload.Domains, rules)
+
+	// update priority
+	if payload.Priority != nil {
+		mgr.unsafeUpdateProviderPriority(p, *payload.Priority)
+	}
 
 	// update config file
 	err = mgr.unsafeWriteProvidersConfig()
@@ -193,7 +273,8 @@ func (mgr *Manager) ModifyProvider(w http.ResponseWriter, r *http.Request) *outp
 	response := &providerResponse{}
 	response.StatusCode = http.StatusCreated
 	response.Message = "updated provider"
-	response.Provider = p
+	redactedP := mgr.redactedProvider(p)
+	response.Provider = &redactedP
 
 	err = mgr.output.WriteJSON(w, response)
 	if err != nil {
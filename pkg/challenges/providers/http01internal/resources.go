@@ -32,3 +32,9 @@ func (service *Service) Deprovision(_, token, _ string) error {
 
 	return nil
 }
+
+// InMemoryResources indicates resources are only held in memory (so there is nothing
+// to clean up after the app stops)
+func (service *Service) InMemoryResources() bool {
+	return true
+}
@@ -0,0 +1,159 @@
+package http01remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// responder actions
+const (
+	actionProvision   = "provision"
+	actionDeprovision = "deprovision"
+)
+
+// responderPayload is the body POSTed to the remote responder
+type responderPayload struct {
+	Token            string `json:"token"`
+	KeyAuthorization string `json:"key_authorization,omitempty"`
+	Action           string `json:"action"`
+}
+
+// maxSelfCheckBody limits how much of a challenge response is read during self check
+const maxSelfCheckBody = 4096
+
+// postResponder POSTs the token and action to the remote responder. Any 2xx response
+// is considered success.
+func (service *Service) postResponder(payload responderPayload) error {
+	// marshal for posting
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// auth
+	header := make(http.Header)
+	header.Set("Authorization", "Bearer "+service.apiKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), service.timeout)
+	defer cancel()
+
+	resp, err := service.httpClient.PostWithHeaderWithContext(ctx, service.url, "application/json", bytes.NewBuffer(payloadJson), header)
+	if err != nil {
+		return fmt.Errorf("http-01 remote %s of token %s failed (%w)", payload.Action, payload.Token, err)
+	}
+
+	// read body & close
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	// if not status 2xx, error
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("http-01 remote %s of token %s failed (status %d)", payload.Action, payload.Token, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// challengeUrl returns the url the acme server will fetch to validate the challenge
+// (see: rfc8555 s 8.3)
+func challengeUrl(domain, token string) string {
+	host := domain
+	// ipv6 must be bracketed
+	if ip := net.ParseIP(domain); ip != nil && strings.Contains(domain, ":") {
+		host = "[" + domain + "]"
+	}
+
+	return "http://" + host + "/.well-known/acme-challenge/" + token
+}
+
+// checkChallenge fetches the challenge url until it returns the key authorization or
+// the configured number of attempts is exhausted
+func (service *Service) checkChallenge(domain, token, keyAuth string) (err error) {
+	url := challengeUrl(domain, token)
+
+	for i := 0; i < service.selfCheckAttempts; i++ {
+		if i > 0 {
+			time.Sleep(selfCheckInterval)
+		}
+
+		err = service.fetchChallenge(url, keyAuth)
+		if err == nil {
+			return nil
+		}
+		service.logger.Debugf("http-01 remote self check attempt %d of %s failed (%s)", i+1, url, err)
+	}
+
+	return fmt.Errorf("http-01 remote self check of %s failed (%w)", url, err)
+}
+
+// fetchChallenge fetches url and returns an error if the response is not the key
+// authorization
+func (service *Service) fetchChallenge(url, keyAuth string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), service.timeout)
+	defer cancel()
+
+	resp, err := service.httpClient.GetWithContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSelfCheckBody))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	// servers commonly add trailing whitespace, the acme server should ignore it
+	if strings.TrimSpace(string(body)) != keyAuth {
+		return fmt.Errorf("response does not match key authorization")
+	}
+
+	return nil
+}
+
+// Provision pushes the token and key authorization to the remote responder and then
+// (unless disabled) confirms the challenge url serves the key authorization. If the
+// self check fails, the token is left on the remote; the caller always deprovisions.
+func (service *Service) Provision(domain, token, keyAuth string) error {
+	err := service.postResponder(responderPayload{
+		Token:            token,
+		KeyAuthorization: keyAuth,
+		Action:           actionProvision,
+	})
+	if err != nil {
+		return err
+	}
+
+	if service.selfCheck {
+		err = service.checkChallenge(domain, token, keyAuth)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deprovision tells the remote responder to stop serving the token
+func (service *Service) Deprovision(_, token, _ string) error {
+	err := service.postResponder(responderPayload{
+		Token:  token,
+		Action: actionDeprovision,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package http01remote
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/httpclient"
+	"legocerthub-backend/pkg/secrets"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary http-01 remote component is missing")
+	errUrlBad           = errors.New("http-01 remote url must be a valid http or https url")
+	errApiKeyMissing    = errors.New("http-01 remote api key must be specified")
+)
+
+// defaults
+const (
+	defaultTimeoutSeconds    = 30
+	defaultSelfCheckAttempts = 5
+	selfCheckInterval        = 2 * time.Second
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetHttpClient() *httpclient.Client
+}
+
+// Configuration options
+type Config struct {
+	// responder endpoint on the remote web server
+	Url string `yaml:"url" json:"url"`
+	// sent to the responder as a bearer token
+	ApiKey string `yaml:"api_key" json:"api_key"`
+	// how long to wait for the responder to respond
+	TimeoutSeconds int `yaml:"timeout_seconds" json:"timeout_seconds"`
+	// after provisioning, the challenge url is fetched to confirm the remote server
+	// serves the key authorization before the acme server is told to validate
+	SkipSelfCheck     bool `yaml:"skip_self_check" json:"skip_self_check"`
+	SelfCheckAttempts int  `yaml:"self_check_attempts" json:"self_check_attempts"`
+}
+
+// SecretFields returns the config values that contain secrets
+func (cfg *Config) SecretFields() []secrets.Field {
+	return []secrets.Field{{Value: &cfg.ApiKey}}
+}
+
+// provider Service struct
+type Service struct {
+	logger            *zap.SugaredLogger
+	httpClient        *httpclient.Client
+	url               string
+	apiKey            string
+	timeout           time.Duration
+	selfCheck         bool
+	selfCheckAttempts int
+}
+
+// ChallengeType returns the ACME Challenge Type this provider uses, which is http-01
+func (service *Service) AcmeChallengeType() acme.ChallengeType {
+	return acme.ChallengeTypeHttp01
+}
+
+// Stop is used for any actions needed prior to deleting this provider. If no actions
+// are needed, it is just a no-op.
+func (service *Service) Stop() error { return nil }
+
+// NewService creates a new service
+func NewService(app App, cfg *Config) (*Service, error) {
+	// if no config, error
+	if cfg == nil {
+		return nil, errServiceComponent
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// http client
+	service.httpClient = app.GetHttpClient()
+	if service.httpClient == nil {
+		return nil, errServiceComponent
+	}
+
+	// url
+	u, err := url.Parse(cfg.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errUrlBad
+	}
+	service.url = cfg.Url
+
+	// api key
+	if cfg.ApiKey == "" {
+		return nil, errApiKeyMissing
+	}
+	service.apiKey = cfg.ApiKey
+
+	// timeout
+	service.timeout = defaultTimeoutSeconds * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		service.timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	// self check
+	service.selfCheck = !cfg.SkipSelfCheck
+	service.selfCheckAttempts = defaultSelfCheckAttempts
+	if cfg.SelfCheckAttempts > 0 {
+		service.selfCheckAttempts = cfg.SelfCheckAttempts
+	}
+
+	return service, nil
+}
+
+// Update Service updates the Service to use the new config
+func (service *Service) UpdateService(app App, cfg *Config) error {
+	// if no config, error
+	if cfg == nil {
+		return errServiceComponent
+	}
+
+	// don't need to do anything with "old" Service, just set a new one
+	newServ, err := NewService(app, cfg)
+	if err != nil {
+		return err
+	}
+
+	// set content of old pointer so anything with the pointer calls the
+	// updated service
+	*service = *newServ
+
+	return nil
+}
@@ -0,0 +1,47 @@
+//go:build !js && !windows
+
+package http01webroot
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// lookupUid returns the uid of the specified user name or numeric id. If owner
+// is blank, -1 is returned (which leaves ownership unchanged).
+func lookupUid(owner string) (int, error) {
+	if owner == "" {
+		return -1, nil
+	}
+
+	if uid, err := strconv.Atoi(owner); err == nil {
+		return uid, nil
+	}
+
+	u, err := user.Lookup(owner)
+	if err != nil {
+		return 0, fmt.Errorf("http-01 webroot owner %s not found (%w)", owner, err)
+	}
+
+	return strconv.Atoi(u.Uid)
+}
+
+// lookupGid returns the gid of the specified group name or numeric id. If group
+// is blank, -1 is returned (which leaves ownership unchanged).
+func lookupGid(group string) (int, error) {
+	if group == "" {
+		return -1, nil
+	}
+
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, fmt.Errorf("http-01 webroot group %s not found (%w)", group, err)
+	}
+
+	return strconv.Atoi(g.Gid)
+}
@@ -0,0 +1,27 @@
+package http01webroot
+
+import (
+	"errors"
+)
+
+var errOwnershipUnsupported = errors.New("http-01 webroot owner and group are not supported on windows")
+
+// lookupUid always returns -1 (unchanged) on windows, and an error if an owner
+// was specified
+func lookupUid(owner string) (int, error) {
+	if owner != "" {
+		return 0, errOwnershipUnsupported
+	}
+
+	return -1, nil
+}
+
+// lookupGid always returns -1 (unchanged) on windows, and an error if a group
+// was specified
+func lookupGid(group string) (int, error) {
+	if group != "" {
+		return 0, errOwnershipUnsupported
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,134 @@
+package http01webroot
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// challengePath is the path (relative to the webroot) that challenge files are
+// served from (see: rfc8555 s 8.3)
+var challengePath = filepath.Join(".well-known", "acme-challenge")
+
+// tokens are base64url (see: rfc8555 s 8.1), anything else could escape the
+// challenge directory
+var tokenRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Provision writes the key authorization to the token's file in every webroot. If
+// writing to any webroot fails, files already written are removed.
+func (service *Service) Provision(_, token, keyAuth string) error {
+	if !tokenRegex.MatchString(token) {
+		return fmt.Errorf("http-01 webroot token %s contains invalid characters", token)
+	}
+
+	for i, webroot := range service.webroots {
+		err := service.writeChallengeFile(webroot, token, keyAuth)
+		if err != nil {
+			// undo any already written
+			for _, writtenRoot := range service.webroots[:i] {
+				rmErr := os.Remove(filepath.Join(writtenRoot, challengePath, token))
+				if rmErr != nil {
+					service.logger.Errorf("http-01 webroot failed to remove token file (%s)", rmErr)
+				}
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeChallengeFile creates the challenge directory in webroot (if it does not exist)
+// and writes the token's file
+func (service *Service) writeChallengeFile(webroot, token, keyAuth string) error {
+	// create any missing directories (with the configured mode and ownership)
+	err := service.mkdirs(webroot)
+	if err != nil {
+		return err
+	}
+
+	// write the file (chmod after as WriteFile is subject to umask)
+	fileName := filepath.Join(webroot, challengePath, token)
+	err = os.WriteFile(fileName, []byte(keyAuth), service.fileMode)
+	if err != nil {
+		return fmt.Errorf("http-01 webroot failed to write %s (%w)", fileName, err)
+	}
+
+	err = service.setPermissions(fileName, service.fileMode)
+	if err != nil {
+		_ = os.Remove(fileName)
+		return err
+	}
+
+	return nil
+}
+
+// mkdirs creates .well-known and .well-known/acme-challenge in webroot if they do not
+// already exist. The webroot itself must already exist.
+func (service *Service) mkdirs(webroot string) error {
+	info, err := os.Stat(webroot)
+	if err != nil {
+		return fmt.Errorf("http-01 webroot %s is not usable (%w)", webroot, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("http-01 webroot %s is not a directory", webroot)
+	}
+
+	dir := webroot
+	for _, elem := range []string{".well-known", "acme-challenge"} {
+		dir = filepath.Join(dir, elem)
+
+		err = os.Mkdir(dir, service.dirMode)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("http-01 webroot failed to create %s (%w)", dir, err)
+		}
+
+		err = service.setPermissions(dir, service.dirMode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setPermissions sets the mode and (if configured) ownership of name
+func (service *Service) setPermissions(name string, mode fs.FileMode) error {
+	err := os.Chmod(name, mode)
+	if err != nil {
+		return fmt.Errorf("http-01 webroot failed to chmod %s (%w)", name, err)
+	}
+
+	if service.uid != -1 || service.gid != -1 {
+		err = os.Chown(name, service.uid, service.gid)
+		if err != nil {
+			return fmt.Errorf("http-01 webroot failed to chown %s (%w)", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Deprovision removes the token's file from every webroot. The challenge directories
+// are left in place.
+func (service *Service) Deprovision(_, token, _ string) error {
+	if !tokenRegex.MatchString(token) {
+		return fmt.Errorf("http-01 webroot token %s contains invalid characters", token)
+	}
+
+	var errs []error
+	for _, webroot := range service.webroots {
+		err := os.Remove(filepath.Join(webroot, challengePath, token))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("http-01 webroot failed to remove token file (%w)", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
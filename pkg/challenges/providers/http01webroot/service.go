@@ -0,0 +1,146 @@
+package http01webroot
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"legocerthub-backend/pkg/acme"
+	"path/filepath"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary http-01 webroot component is missing")
+	errWebrootsMissing  = errors.New("http-01 webroot requires at least one webroot directory")
+)
+
+// defaults
+const (
+	defaultFileMode = fs.FileMode(0644)
+	defaultDirMode  = fs.FileMode(0755)
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+}
+
+// Configuration options
+type Config struct {
+	// directories the web server serves from, challenge files are written to
+	// [webroot]/.well-known/acme-challenge/[token]
+	Webroots []string `yaml:"webroots" json:"webroots"`
+	// optional ownership (user/group name or numeric id), if blank ownership
+	// is not changed
+	Owner string `yaml:"owner" json:"owner"`
+	Group string `yaml:"group" json:"group"`
+	// optional octal permissions (e.g. '0644'), if blank the defaults are used
+	FileMode string `yaml:"file_mode" json:"file_mode"`
+	DirMode  string `yaml:"dir_mode" json:"dir_mode"`
+}
+
+// provider Service struct
+type Service struct {
+	logger   *zap.SugaredLogger
+	webroots []string
+	uid      int
+	gid      int
+	fileMode fs.FileMode
+	dirMode  fs.FileMode
+}
+
+// ChallengeType returns the ACME Challenge Type this provider uses, which is http-01
+func (service *Service) AcmeChallengeType() acme.ChallengeType {
+	return acme.ChallengeTypeHttp01
+}
+
+// Stop is used for any actions needed prior to deleting this provider. If no actions
+// are needed, it is just a no-op.
+func (service *Service) Stop() error { return nil }
+
+// NewService creates a new service
+func NewService(app App, cfg *Config) (*Service, error) {
+	// if no config, error
+	if cfg == nil {
+		return nil, errServiceComponent
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// webroots
+	if len(cfg.Webroots) == 0 {
+		return nil, errWebrootsMissing
+	}
+	for _, webroot := range cfg.Webroots {
+		if webroot == "" {
+			return nil, errWebrootsMissing
+		}
+		service.webroots = append(service.webroots, filepath.Clean(webroot))
+	}
+
+	// ownership (-1 leaves unchanged)
+	var err error
+	service.uid, err = lookupUid(cfg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	service.gid, err = lookupGid(cfg.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	// modes
+	service.fileMode, err = parseMode(cfg.FileMode, defaultFileMode)
+	if err != nil {
+		return nil, err
+	}
+	service.dirMode, err = parseMode(cfg.DirMode, defaultDirMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// parseMode parses an octal permission string, returning defaultMode if the string
+// is blank
+func parseMode(mode string, defaultMode fs.FileMode) (fs.FileMode, error) {
+	if mode == "" {
+		return defaultMode, nil
+	}
+
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || parsed > 0777 {
+		return 0, fmt.Errorf("http-01 webroot mode %s is not a valid octal permission", mode)
+	}
+
+	return fs.FileMode(parsed), nil
+}
+
+// Update Service updates the Service to use the new config
+func (service *Service) UpdateService(app App, cfg *Config) error {
+	// if no config, error
+	if cfg == nil {
+		return errServiceComponent
+	}
+
+	// don't need to do anything with "old" Service, just set a new one
+	newServ, err := NewService(app, cfg)
+	if err != nil {
+		return err
+	}
+
+	// set content of old pointer so anything with the pointer calls the
+	// updated service
+	*service = *newServ
+
+	return nil
+}
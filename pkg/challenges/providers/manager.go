@@ -3,8 +3,10 @@ package providers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"legocerthub-backend/pkg/httpclient"
 	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/secrets"
 	"sync"
 
 	"go.uber.org/zap"
@@ -18,6 +20,7 @@ type application interface {
 	GetShutdownContext() context.Context
 	GetHttpClient() *httpclient.Client
 	GetShutdownWaitGroup() *sync.WaitGroup
+	GetSecretsKeyPath() string
 }
 
 // Manager manages the child providers
@@ -28,7 +31,7 @@ type Manager struct {
 	configFile string
 	nextId     int
 	providers  []*provider
-	dP         map[string]*provider // domain -> provider
+	secrets    *secrets.Service
 	mu         sync.RWMutex
 }
 
@@ -40,25 +43,52 @@ func MakeManager(app application, cfg Config) (mgr *Manager, err error) {
 		output:     app.GetOutputter(),
 		configFile: app.GetConfigFilenameWithPath(),
 		nextId:     0,
-		// []*providers
-		dP: make(map[string]*provider), // domain -> provider
+		secrets:    secrets.NewService(app.GetSecretsKeyPath()),
 	}
 
 	// get all provider cfgs as array
 	allCfgs := cfg.All()
 
+	// note if any secrets are in the config file as plain values
+	plainSecrets := false
+	// and if any providers don't have a key yet
+	missingKeys := false
+	for i := range allCfgs {
+		if secretsCfg, ok := allCfgs[i].providerCfg.(secrets.Config); ok && secrets.ContainsPlain(secretsCfg) {
+			plainSecrets = true
+		}
+		if allCfgs[i].key == "" {
+			missingKeys = true
+		}
+	}
+
 	// add each provider to manager
 	for i := range allCfgs {
-		_, err = mgr.unsafeAddProvider(allCfgs[i].domains, allCfgs[i].providerCfg)
+		_, err = mgr.unsafeAddProvider(allCfgs[i].key, allCfgs[i].domains, allCfgs[i].priority, allCfgs[i].providerCfg)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// verify at least one domain / provider exists
-	if len(mgr.dP) <= 0 {
+	// verify at least one provider exists
+	if len(mgr.providers) <= 0 {
 		return nil, errors.New("no challenge providers are properly configured (at least one must be enabled)")
 	}
 
+	// rewrite config file so the plain secrets are replaced with encrypted ones and
+	// the generated provider keys are saved
+	if plainSecrets || missingKeys {
+		err = mgr.unsafeWriteProvidersConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to update providers in config file (%s)", err)
+		}
+		if plainSecrets {
+			mgr.logger.Info("plain provider secrets in the config file were encrypted")
+		}
+		if missingKeys {
+			mgr.logger.Info("generated provider keys were saved to the config file")
+		}
+	}
+
 	return mgr, nil
 }
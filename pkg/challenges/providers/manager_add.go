@@ -2,24 +2,45 @@ package providers
 
 import (
 	"errors"
+	"fmt"
 	"legocerthub-backend/pkg/challenges/providers/dns01acmedns"
 	"legocerthub-backend/pkg/challenges/providers/dns01acmesh"
 	"legocerthub-backend/pkg/challenges/providers/dns01cloudflare"
 	"legocerthub-backend/pkg/challenges/providers/dns01goacme"
 	"legocerthub-backend/pkg/challenges/providers/dns01manual"
+	"legocerthub-backend/pkg/challenges/providers/dns01rfc2136"
+	"legocerthub-backend/pkg/challenges/providers/dns01webhook"
 	"legocerthub-backend/pkg/challenges/providers/http01internal"
+	"legocerthub-backend/pkg/challenges/providers/http01remote"
+	"legocerthub-backend/pkg/challenges/providers/http01webroot"
+	"legocerthub-backend/pkg/challenges/providers/tlsalpn01internal"
 	"legocerthub-backend/pkg/randomness"
 	"reflect"
 	"strings"
 )
 
 // unsafeAddProvider creates the provider specified in cfg and adds it to
-// manager. It MUST be called from a Locked state OR during initial Manager
-// creation which is single threaded (and thus safe)
-func (mgr *Manager) unsafeAddProvider(domains []string, cfg providerConfig) (*provider, error) {
-	// verify every domain ir properly formatted, or verify this is wildcard cfg (* only)
-	// and also verify all domains are available in manager
-	err := mgr.unsafeValidateDomains(domains, nil)
+// manager. If key is blank, a new key is generated. It MUST be called from a
+// Locked state OR during initial Manager creation which is single threaded (and
+// thus safe)
+func (mgr *Manager) unsafeAddProvider(key string, domains []string, priority int, cfg providerConfig) (*provider, error) {
+	// key must be unique as it identifies the provider's challenge history
+	if key != "" {
+		for _, p := range mgr.providers {
+			if p.Key == key {
+				return nil, fmt.Errorf("provider key %s is used by more than one provider", key)
+			}
+		}
+	}
+
+	// verify every domain is a properly formatted rule
+	rules, err := mgr.unsafeValidateDomains(domains, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve secrets for the service, keep reference form for the provider config
+	refCfg, err := mgr.prepareConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +52,9 @@ func (mgr *Manager) unsafeAddProvider(domains []string, cfg providerConfig) (*pr
 	case *http01internal.Config:
 		serv, err = http01internal.NewService(mgr.childApp, realCfg)
 
+	case *tlsalpn01internal.Config:
+		serv, err = tlsalpn01internal.NewService(mgr.childApp, realCfg)
+
 	case *dns01manual.Config:
 		serv, err = dns01manual.NewService(mgr.childApp, realCfg)
 
@@ -46,6 +70,18 @@ func (mgr *Manager) unsafeAddProvider(domains []string, cfg providerConfig) (*pr
 	case *dns01goacme.Config:
 		serv, err = dns01goacme.NewService(mgr.childApp, realCfg)
 
+	case *dns01rfc2136.Config:
+		serv, err = dns01rfc2136.NewService(mgr.childApp, realCfg)
+
+	case *dns01webhook.Config:
+		serv, err = dns01webhook.NewService(mgr.childApp, realCfg)
+
+	case *http01webroot.Config:
+		serv, err = http01webroot.NewService(mgr.childApp, realCfg)
+
+	case *http01remote.Config:
+		serv, err = http01remote.NewService(mgr.childApp, realCfg)
+
 	default:
 		// default fail
 		return nil, errors.New("cannot create provider service, unsupported provider cfg")
@@ -56,19 +92,36 @@ func (mgr *Manager) unsafeAddProvider(domains []string, cfg providerConfig) (*pr
 		return nil, err
 	}
 
+	// ip and wildcard domains require a challenge type that can validate them
+	err = validateDomainsChallengeType(rules, serv.AcmeChallengeType())
+	if err != nil {
+		stopErr := serv.Stop()
+		if stopErr != nil {
+			mgr.logger.Errorf("failed to stop provider service after domains validation failed (%s)", stopErr)
+		}
+		return nil, err
+	}
+
 	// all valid, good to add provider to mgr
 
 	// create Provider from service and config
 	typeOf, _ := strings.CutPrefix(reflect.TypeOf(cfg).String(), "*")
 	typeOf, _ = strings.CutSuffix(typeOf, ".Config")
 
+	if key == "" {
+		key = randomness.GenerateInsecureString(16)
+	}
+
 	p := &provider{
-		ID:      mgr.nextId,
-		Tag:     randomness.GenerateInsecureString(10),
-		Domains: domains,
-		Type:    typeOf,
-		Config:  cfg,
-		Service: serv,
+		ID:       mgr.nextId,
+		Key:      key,
+		Tag:      randomness.GenerateInsecureString(10),
+		Domains:  domains,
+		Priority: priority,
+		Type:     typeOf,
+		Config:   refCfg,
+		Service:  serv,
+		rules:    rules,
 	}
 
 	// increment next id
@@ -77,11 +130,5 @@ func (mgr *Manager) unsafeAddProvider(domains []string, cfg providerConfig) (*pr
 	// add provider to provider slice
 	mgr.providers = append(mgr.providers, p)
 
-	// add each domain to domain map
-	for _, domain := range domains {
-		// add domain to domains map
-		mgr.dP[domain] = p
-	}
-
 	return p, nil
 }
@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"legocerthub-backend/pkg/secrets"
+	"reflect"
+)
+
+// cloneConfig returns a deep copy of cfg
+func cloneConfig(cfg providerConfig) (providerConfig, error) {
+	cfgJson, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := reflect.New(reflect.TypeOf(cfg).Elem()).Interface()
+	err = json.Unmarshal(cfgJson, clone)
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// prepareConfig resolves the secret references in cfg (in place) so it can be used to
+// start the provider service. It returns a copy of the original cfg, with any plain
+// secrets encrypted, to save as the provider's config (so secrets are never written
+// to the config file as plain values).
+func (mgr *Manager) prepareConfig(cfg providerConfig) (refCfg providerConfig, err error) {
+	secretsCfg, ok := cfg.(secrets.Config)
+	if !ok {
+		return cfg, nil
+	}
+
+	refCfg, err = cloneConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = mgr.secrets.Protect(refCfg.(secrets.Config))
+	if err != nil {
+		return nil, err
+	}
+
+	err = mgr.secrets.Resolve(secretsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve provider secrets (%w)", err)
+	}
+
+	return refCfg, nil
+}
+
+// redactedProvider returns a copy of p with the secrets in its config redacted
+func (mgr *Manager) redactedProvider(p *provider) provider {
+	redactedP := *p
+
+	if _, ok := p.Config.(secrets.Config); ok {
+		redactedCfg, err := cloneConfig(p.Config)
+		if err != nil {
+			// never return the unredacted config
+			mgr.logger.Errorf("failed to redact provider %d config (%s)", p.ID, err)
+			redactedP.Config = nil
+			return redactedP
+		}
+
+		secrets.Redact(redactedCfg.(secrets.Config))
+		redactedP.Config = redactedCfg
+	}
+
+	return redactedP
+}
@@ -1,23 +1,19 @@
 package providers
 
-// unsafeUpdateProviderDomains updates the domains serviced by a provider, if no domains
-// are specified, no modification is performed
-func (mgr *Manager) unsafeUpdateProviderDomains(p *provider, newDomains []string) {
+// unsafeUpdateProviderDomains updates the domains (and corresponding parsed rules)
+// serviced by a provider, if no domains are specified, no modification is performed
+func (mgr *Manager) unsafeUpdateProviderDomains(p *provider, newDomains []string, newRules []domainRule) {
 	// no domains == no-op
 	if newDomains == nil || len(newDomains) < 1 {
 		return
 	}
 
-	// remove existing domain -> p mappings
-	for _, oldDomain := range p.Domains {
-		delete(mgr.dP, oldDomain)
-	}
-
 	// update p's domains
 	p.Domains = newDomains
+	p.rules = newRules
+}
 
-	// add each new domain to map
-	for _, newDomain := range newDomains {
-		mgr.dP[newDomain] = p
-	}
+// unsafeUpdateProviderPriority updates the priority of a provider
+func (mgr *Manager) unsafeUpdateProviderPriority(p *provider, newPriority int) {
+	p.Priority = newPriority
 }
@@ -4,49 +4,195 @@ import (
 	"errors"
 	"fmt"
 	"legocerthub-backend/pkg/acme"
-	"strings"
+	"sort"
 )
 
-// ProviderFor returns the provider Service for the given acme Identifier. If
-// there is no provider for the Identifier, an error is returned instead.
-func (mgr *Manager) ProviderFor(identifier acme.Identifier) (*provider, error) {
+// match results of a provider for an identifier
+const (
+	matchResultSelected      = "selected"
+	matchResultLessSpecific  = "less_specific"
+	matchResultExcluded      = "excluded"
+	matchResultWrongChalType = "unsupported_challenge_type"
+)
+
+// providerMatch is one provider's outcome when matching an identifier
+type providerMatch struct {
+	ProviderID   int    `json:"provider_id"`
+	ProviderType string `json:"provider_type"`
+	Priority     int    `json:"priority"`
+	Rule         string `json:"rule"`
+	Result       string `json:"result"`
+	Reason       string `json:"reason"`
+
+	provider *provider
+	score    matchScore
+}
+
+// ProvidersFor returns the providers for the given acme Identifier, in the order they
+// should be tried (by priority). If there is no provider for the Identifier, an error
+// is returned instead.
+func (mgr *Manager) ProvidersFor(identifier acme.Identifier, wildcard bool) ([]*provider, error) {
 	mgr.mu.RLock()
 	defer mgr.mu.RUnlock()
 
-	// confirm Type is correct (only dns is supported)
-	if identifier.Type != acme.IdentifierTypeDns {
-		return nil, errors.New("acme identifier is not dns type (challenges pkg can only solve dns type)")
+	matches, err := mgr.unsafeMatchProviders(identifier, wildcard)
+	if err != nil {
+		return nil, err
 	}
 
-	// if exact domain is in the list, return its provider
-	p, exists := mgr.dP[identifier.Value]
-	if exists {
-		return p, nil
+	ps := []*provider{}
+	for _, m := range matches {
+		if m.Result == matchResultSelected {
+			ps = append(ps, m.provider)
+		}
 	}
 
-	// find best match from options (if there is a provider for a more specific subdomain, choose that one)
-	providerDomain := ""
-	for domain := range mgr.dP {
-		// include period to avoid matching something like hellodomain.com to domain.com 's provider
-		if strings.HasSuffix(identifier.Value, "."+domain) {
-			// for a provider with the proper suffix, check length of existing match and update
-			// match if the new match is longer
-			if len(domain) > len(providerDomain) {
-				providerDomain = domain
-			}
+	if len(ps) == 0 {
+		return nil, fmt.Errorf("could not find a challenge provider for the specified identifier (%s; %s)", identifier.Type, identifier.Value)
+	}
+
+	return ps, nil
+}
+
+// unsafeMatchProviders checks every provider's rules against the identifier and returns
+// the outcome for each provider that had a matching rule. The most specific rule each
+// provider has is used and the providers with the most specific match overall are
+// selected (in priority order), a provider with a matching exclusion is never selected.
+// Providers whose challenge type can't validate the identifier are skipped (dns-01
+// can't validate ip identifiers and only dns-01 can validate wildcard identifiers).
+// Manager MUST be AT LEAST RLocked before calling this func.
+func (mgr *Manager) unsafeMatchProviders(identifier acme.Identifier, wildcard bool) ([]providerMatch, error) {
+	// confirm Type is correct (only dns and ip are supported)
+	switch identifier.Type {
+	case acme.IdentifierTypeDns, acme.IdentifierTypeIp:
+		// no-op
+	default:
+		return nil, errors.New("acme identifier is not dns or ip type (challenges pkg can only solve dns and ip types)")
+	}
+
+	matches := []providerMatch{}
+	var best matchScore
+	for _, p := range mgr.providers {
+		m, matched := p.match(identifier, wildcard)
+		if !matched {
+			continue
 		}
+
+		if m.Result == matchResultSelected && m.score.moreSpecificThan(best) {
+			best = m.score
+		}
+
+		matches = append(matches, m)
 	}
-	// if a match was found, return its provider
-	if providerDomain != "" {
-		return mgr.dP[providerDomain], nil
+
+	// only the most specific matches remain selected
+	for i := range matches {
+		if matches[i].Result == matchResultSelected && best.moreSpecificThan(matches[i].score) {
+			matches[i].Result = matchResultLessSpecific
+			matches[i].Reason += " (another provider has a more specific match)"
+		}
 	}
 
-	// if domain was not found, return wild provider if it exists
-	p, exists = mgr.dP["*"]
-	if exists {
-		return p, nil
+	// selected first (in the order to try them), then everything else
+	sort.SliceStable(matches, func(i, j int) bool {
+		iSelected := matches[i].Result == matchResultSelected
+		jSelected := matches[j].Result == matchResultSelected
+		if iSelected != jSelected {
+			return iSelected
+		}
+		if iSelected && matches[i].Priority != matches[j].Priority {
+			return matches[i].Priority < matches[j].Priority
+		}
+		return false
+	})
+
+	return matches, nil
+}
+
+// match returns the provider's outcome for the identifier. If none of the provider's
+// rules match the identifier, false is returned.
+func (p *provider) match(identifier acme.Identifier, wildcard bool) (providerMatch, bool) {
+	m := providerMatch{
+		ProviderID:   p.ID,
+		ProviderType: p.Type,
+		Priority:     p.Priority,
+		provider:     p,
 	}
 
-	return nil, fmt.Errorf("could not find a challenge provider for the specified identifier (%s; %s)", identifier.Type, identifier.Value)
+	// exclusions take precedence over all other rules
+	for _, rule := range p.rules {
+		if !rule.exclude {
+			continue
+		}
+		if _, matched := rule.matches(identifier, wildcard); matched {
+			m.Rule = rule.raw
+			m.Result = matchResultExcluded
+			m.Reason = "identifier " + rule.description() + " which is excluded"
+			return m, true
+		}
+	}
+
+	// find the most specific rule
+	found := false
+	for _, rule := range p.rules {
+		if rule.exclude {
+			continue
+		}
+		score, matched := rule.matches(identifier, wildcard)
+		if matched && (!found || score.moreSpecificThan(m.score)) {
+			found = true
+			m.score = score
+			m.Rule = rule.raw
+			m.Reason = "identifier " + rule.description()
+		}
+	}
+	if !found {
+		return providerMatch{}, false
+	}
+
+	// confirm the challenge type can validate the identifier
+	challType := p.AcmeChallengeType()
+	if identifier.Type == acme.IdentifierTypeIp && challType == acme.ChallengeTypeDns01 {
+		m.Result = matchResultWrongChalType
+		m.Reason += " but dns-01 cannot validate ip identifiers"
+		return m, true
+	}
+	if wildcard && challType != acme.ChallengeTypeDns01 {
+		m.Result = matchResultWrongChalType
+		m.Reason += fmt.Sprintf(" but %s cannot validate wildcard identifiers", challType)
+		return m, true
+	}
+
+	m.Result = matchResultSelected
+	return m, true
+}
+
+// ProviderByID returns the provider with the specified ID. If there is no such
+// provider, an error is returned instead.
+func (mgr *Manager) ProviderByID(id int) (*provider, error) {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+
+	for _, p := range mgr.providers {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+
+	return nil, errBadID(id)
+}
+
+// ProviderByKey returns the provider with the specified key. If there is no such
+// provider, an error is returned instead.
+func (mgr *Manager) ProviderByKey(key string) (*provider, error) {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+
+	for _, p := range mgr.providers {
+		if p.Key == key {
+			return p, nil
+		}
+	}
 
+	return nil, fmt.Errorf("no provider with key %s", key)
 }
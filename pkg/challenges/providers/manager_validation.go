@@ -1,40 +1,61 @@
 package providers
 
 import (
-	"errors"
 	"fmt"
-	"legocerthub-backend/pkg/validation"
+	"legocerthub-backend/pkg/acme"
 )
 
-// unsafeValidateDomains verifies that the domains are all valid
-// and also that they're available in manager. p is optional and if specified
-// domains will also be condidered valid if they're not available but are
-// currently assigned to p.  If validation succeeds, nil is returned, if it
-// fails, an error is returned.
-func (mgr *Manager) unsafeValidateDomains(domains []string, p *provider) error {
-	// verify every domain is properly formatted, or verify this is wildcard cfg (* only)
-	// and also verify all domains are available in manager
-
-	// if there are none, invalid
-	if len(domains) <= 0 {
-		return errors.New("provider doesn't have any domains (must have at least 1)")
+// unsafeValidateDomains verifies that the domains are all valid rules and that none
+// are duplicated. A domain may be assigned to more than one provider (the providers
+// are then used in priority order). p is optional and if specified, domains are also
+// validated against p's challenge type. If validation succeeds, the parsed rules are
+// returned, if it fails, an error is returned.
+func (mgr *Manager) unsafeValidateDomains(domains []string, p *provider) ([]domainRule, error) {
+	// check for duplicates
+	seen := make(map[string]struct{}, len(domains))
+	for _, domain := range domains {
+		if _, exists := seen[domain]; exists {
+			return nil, fmt.Errorf("failed to configure domain %s, each domain can only be configured once per provider", domain)
+		}
+		seen[domain] = struct{}{}
 	}
 
-	// validate domain names
-	for _, domain := range domains {
-		// check validity -or- wildcard
-		if !validation.DomainValid(domain, false) && !(len(domains) == 1 && domains[0] == "*") {
-			if domain == "*" {
-				return errors.New("when using wildcard domain * it must be the only specified domain on the provider")
-			}
-			return fmt.Errorf("domain %s is not a validly formatted domain", domain)
+	// parse and validate each rule (and the combination of them)
+	rules, err := parseDomainRules(domains)
+	if err != nil {
+		return nil, err
+	}
+
+	// if provider is known, also validate against its challenge type
+	if p != nil {
+		err = validateDomainsChallengeType(rules, p.AcmeChallengeType())
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// check manager availability
-		currentP, exists := mgr.dP[domain]
-		if exists && (p == nil || p != currentP) {
-			return fmt.Errorf("failed to configure domain %s, each domain can only be configured once", domain)
+	return rules, nil
+}
+
+// validateDomainsChallengeType verifies the rules can be served by the challenge type.
+// ip addresses and ip prefixes cannot be configured on a provider using dns-01 since
+// dns-01 cannot be used to validate ip identifiers (see: rfc8738 s 7) and wildcard
+// identifiers can only be validated by dns-01 (see: rfc8555 s 7.1.3).
+func validateDomainsChallengeType(rules []domainRule, challType acme.ChallengeType) error {
+	for _, rule := range rules {
+		// exclusions never route anything to the provider
+		if rule.exclude {
+			continue
+		}
+
+		if challType == acme.ChallengeTypeDns01 && (rule.kind == ruleKindIpAddress || rule.kind == ruleKindIpPrefix) {
+			return fmt.Errorf("ip address or prefix %s cannot be used with a dns-01 provider", rule.raw)
+		}
+
+		if challType != acme.ChallengeTypeDns01 && rule.scope == wildcardScopeOnly {
+			return fmt.Errorf("wildcard rule %s can only be used with a dns-01 provider", rule.raw)
 		}
 	}
+
 	return nil
 }
@@ -15,12 +15,31 @@ type Service interface {
 	Stop() error
 }
 
-// provider is the structure of a provider that is being managed
+// DelegatingService is optionally implemented by dns-01 provider services that write
+// the validation record to a delegated fqdn instead of _acme-challenge.[domain] (which
+// must then be a CNAME to the delegated fqdn)
+type DelegatingService interface {
+	DelegatedFqdn(domain string) (fqdn string, delegated bool)
+}
+
+// InMemoryService is optionally implemented by provider services whose resources only
+// exist in the app's memory and therefore never outlive the app
+type InMemoryService interface {
+	InMemoryResources() bool
+}
+
+// provider is the structure of a provider that is being managed. ID is assigned
+// in order each time the app starts, Key is saved in the config file and does not
+// change.
 type provider struct {
-	ID      int      `json:"id"`
-	Tag     string   `json:"tag"`
-	Type    string   `json:"type"`
-	Domains []string `json:"domains"`
-	Config  any      `json:"config"`
-	Service `json:"-"`
+	ID       int      `json:"id"`
+	Key      string   `json:"key"`
+	Tag      string   `json:"tag"`
+	Type     string   `json:"type"`
+	Domains  []string `json:"domains"`
+	Priority int      `json:"priority"`
+	Config   any      `json:"config"`
+	Service  `json:"-"`
+
+	rules []domainRule // parsed Domains
 }
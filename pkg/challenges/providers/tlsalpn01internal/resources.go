@@ -0,0 +1,52 @@
+package tlsalpn01internal
+
+import (
+	"crypto/tls"
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"strings"
+)
+
+// Provision adds a validation certificate to serve for the domain
+func (service *Service) Provision(domain, _, keyAuth string) error {
+	// make validation cert
+	cert, err := acme.ValidationResourceTlsAlpn01(domain, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	// add new entry keyed by the SNI the ACME server will send (SNI is case insensitive,
+	// key by lower case)
+	exists, _ := service.provisionedResources.Add(strings.ToLower(acme.TlsAlpn01ServerName(domain)), cert)
+
+	// if it already exists, log an error and fail (should never happen if challenges is working
+	// properly)
+	if exists {
+		err := fmt.Errorf("tls-alpn-01 resource name %s already in use, this should never happen", domain)
+		service.logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Deprovision removes a validation certificate from those being served
+func (service *Service) Deprovision(domain, _, _ string) error {
+	// delete entry
+	delFunc := func(domainKey string, _ *tls.Certificate) bool {
+		return domainKey == strings.ToLower(acme.TlsAlpn01ServerName(domain))
+	}
+
+	deleteOk := service.provisionedResources.DeleteFunc(delFunc)
+	if !deleteOk {
+		return fmt.Errorf("tls-alpn-01 resource %s failed to delete", domain)
+	}
+
+	return nil
+}
+
+// InMemoryResources indicates validation certificates are only held in memory (so
+// there is nothing to clean up after the app stops)
+func (service *Service) InMemoryResources() bool {
+	return true
+}
@@ -0,0 +1,127 @@
+package tlsalpn01internal
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tls server timeouts
+const tlsHandshakeTimeout = 10 * time.Second
+
+var errNotAcmeTlsAlpn = errors.New("client did not offer acme-tls/1 alpn protocol")
+
+// getCertificate returns the validation certificate for the SNI the client requested.
+// Only clients offering the acme-tls/1 protocol are served (see: rfc8737 s 4).
+func (service *Service) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if !slices.Contains(hello.SupportedProtos, acme.TlsAlpn01Protocol) {
+		service.logger.Debugf("tls-alpn-01 client (sni: %s) did not offer %s", hello.ServerName, acme.TlsAlpn01Protocol)
+		return nil, errNotAcmeTlsAlpn
+	}
+
+	// try to read resource
+	cert, err := service.provisionedResources.Read(strings.ToLower(hello.ServerName))
+	if err != nil {
+		service.logger.Debugf("tls-alpn-01 challenge resource %s not found", hello.ServerName)
+		return nil, err
+	}
+
+	service.logger.Debugf("serving resource (name: %s) to tls-alpn-01 client", hello.ServerName)
+	return cert, nil
+}
+
+func (service *Service) startServer() (err error) {
+	// make child context for stopping server
+	ctx, stopServer := context.WithCancel(service.shutdownContext)
+	service.stopServerFunc = stopServer
+
+	// err chan for stop
+	service.stopErrChan = make(chan error)
+
+	// configure server
+
+	// TODO: modify to allow specifying specific interface addresses
+	hostName := ""
+
+	servAddr := fmt.Sprintf("%s:%d", hostName, service.port)
+	tlsConf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{acme.TlsAlpn01Protocol},
+		GetCertificate: service.getCertificate,
+	}
+
+	// launch server
+	service.logger.Infof("attempting to start tls-alpn-01 challenge server on %s.", servAddr)
+	if service.port != 443 {
+		service.logger.Warnf("tls-alpn-01 challenge server is not configured on port 443; internet "+
+			"facing port 443 must be proxied to port %d to function.", service.port)
+	}
+
+	// create listener for server
+	ln, err := net.Listen("tcp", servAddr)
+	if err != nil {
+		service.logger.Error(fmt.Errorf("failed to start tls-alpn-01 challenge server, cannot bind to %s (%s)", servAddr, err))
+		return err
+	}
+
+	// track open connections so shutdown can wait for them
+	connWg := new(sync.WaitGroup)
+
+	// start server
+	service.shutdownWaitgroup.Add(1)
+	go func() {
+		defer service.shutdownWaitgroup.Done()
+
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				// listener closed is the normal shutdown
+				if !errors.Is(err, net.ErrClosed) {
+					service.logger.Errorf("tlsalpn01internal server returned error (%s)", err)
+				}
+				break
+			}
+
+			// the only thing to do is complete the handshake, the ACME server closes the
+			// connection after it verifies the certificate
+			connWg.Add(1)
+			go func() {
+				defer connWg.Done()
+
+				tlsConn := tls.Server(conn, tlsConf)
+				defer tlsConn.Close()
+
+				_ = tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+				err := tlsConn.Handshake()
+				if err != nil {
+					service.logger.Debugf("tls-alpn-01 handshake with %s failed (%s)", conn.RemoteAddr(), err)
+				}
+			}()
+		}
+
+		connWg.Wait()
+		service.logger.Infof("tls-alpn-01 challenge server (%s) shutdown complete", servAddr)
+	}()
+
+	// monitor shutdown context
+	go func() {
+		<-ctx.Done()
+
+		err := ln.Close()
+		if err != nil {
+			service.logger.Errorf("error shutting down tls-alpn-01 challenge server %s (%s)", servAddr, err)
+		}
+
+		// send shutdown result to err chan
+		service.stopErrChan <- err
+	}()
+
+	return nil
+}
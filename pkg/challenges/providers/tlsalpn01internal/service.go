@@ -0,0 +1,157 @@
+package tlsalpn01internal
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/datatypes/safemap"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errServiceComponent = errors.New("necessary tls-alpn-01 internal challenge service component is missing")
+	errConfigComponent  = errors.New("necessary tls-alpn-01 config option missing")
+)
+
+// App interface is for connecting to the main app
+type App interface {
+	GetLogger() *zap.SugaredLogger
+	GetShutdownContext() context.Context
+	GetShutdownWaitGroup() *sync.WaitGroup
+}
+
+// provider Service struct
+type Service struct {
+	logger               *zap.SugaredLogger
+	shutdownContext      context.Context
+	shutdownWaitgroup    *sync.WaitGroup
+	stopServerFunc       context.CancelFunc
+	stopErrChan          chan error
+	port                 int
+	provisionedResources *safemap.SafeMap[*tls.Certificate]
+}
+
+// ChallengeType returns the ACME Challenge Type this provider uses, which is tls-alpn-01
+func (service *Service) AcmeChallengeType() acme.ChallengeType {
+	return acme.ChallengeTypeTlsAlpn01
+}
+
+// Stop is used for any actions needed prior to deleting this provider. For tls-alpn-01
+// internal, the tls server must be shutdown.
+func (service *Service) Stop() (err error) {
+	// stop server
+	service.stopServerFunc()
+
+	// wait for result of server shutdown
+	timeoutTimer := time.NewTimer(240 * time.Second)
+
+	select {
+	case <-timeoutTimer.C:
+		// shutdown timeout
+		err = errors.New("tls-alpn-01 internal server shutdown timed out")
+		return err
+	case err = <-service.stopErrChan:
+		// ensure timer releases resources
+		if !timeoutTimer.Stop() {
+			<-timeoutTimer.C
+		}
+
+		// no-op, proceed to err check
+	}
+
+	// common err check (shutdown err = fatal unstable)
+	if err != nil {
+		err = fmt.Errorf("stop tls alpn 01 server failed (%s) leaving tls alpn 01 internal provider in an unstable state", err)
+		service.logger.Fatal(err)
+		// ^ app terminates
+		return err
+	}
+
+	return nil
+}
+
+// Configuration options
+type Config struct {
+	Port *int `yaml:"port" json:"port"`
+}
+
+// NewService creates a new service
+func NewService(app App, cfg *Config) (*Service, error) {
+	// if no config, error
+	if cfg == nil {
+		return nil, errServiceComponent
+	}
+
+	service := new(Service)
+
+	// logger
+	service.logger = app.GetLogger()
+	if service.logger == nil {
+		return nil, errServiceComponent
+	}
+
+	// allocate resources map
+	service.provisionedResources = safemap.NewSafeMap[*tls.Certificate]()
+
+	// set port
+	if cfg.Port == nil {
+		return nil, errConfigComponent
+	}
+	service.port = *cfg.Port
+
+	// parent shutdown context
+	service.shutdownContext = app.GetShutdownContext()
+
+	// parent shutdown wg
+	service.shutdownWaitgroup = app.GetShutdownWaitGroup()
+
+	// start tls server for tls-alpn-01 challenges
+	err := service.startServer()
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
+
+// Update Service updates the Service to use the new config
+func (service *Service) UpdateService(app App, cfg *Config) (err error) {
+	// if no config, error
+	if cfg == nil {
+		return errServiceComponent
+	}
+
+	// if port changed, stop server and remake service
+	if cfg.Port != nil && *cfg.Port != service.port {
+		// stop old server
+		err = service.Stop()
+		if err != nil {
+			return err
+		}
+
+		// make new service
+		newServ, err := NewService(app, cfg)
+		if err != nil {
+			// if failed to make, restart old server
+			errRestart := service.startServer()
+			if errRestart != nil {
+				service.logger.Panicf("failed to restart tls alpn 01 server leaving tls alpn 01 internal provider in an unstable state")
+				return errRestart
+			}
+			return err
+		}
+
+		// set content of old pointer so anything with the pointer calls the
+		// updated service
+		*service = *newServ
+	}
+
+	// nothing else to update on service (domains handled by parent pkg)
+
+	return nil
+}
@@ -9,12 +9,41 @@ import (
 var (
 	errShutdown        = errors.New("challenge solving aborted due to challenges shutdown")
 	errNameUnavailable = errors.New("failed to add challenge record due to resource name never becoming free (timeout)")
+	errNameInUse       = errors.New("failed to add challenge record due to resource name currently being in use")
 )
 
 // Provision adds the specified ACME Challenge resource name to the in use tracker and then calls the provider
 // to provision the actual resource. If the resource name is already in use, it waits until the name is free
 // and then proceeds.
-func (service *Service) provision(domain, token, keyAuth string, provider providers.Service) (err error) {
+func (service *Service) provision(res *ProvisionedResource, provider providers.Service) (err error) {
+	// add domain to those currently provisioned (wait if not available)
+	err = service.reserveResourceName(res.Domain, true)
+	if err != nil {
+		return err
+	}
+
+	return service.provisionReserved(res, provider)
+}
+
+// provisionReserved journals the resource and then calls the provider to provision it. The
+// resource name must already be reserved.
+func (service *Service) provisionReserved(res *ProvisionedResource, provider providers.Service) (err error) {
+	// journal before provisioning so a partially created resource is also cleaned up
+	service.journalResource(res, provider)
+
+	// Provision with the appropriate provider
+	err = provider.Provision(res.Domain, res.Token, res.KeyAuth)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// reserveResourceName adds the resource name to the in use tracker. If the name is already in
+// use and wait is true, it waits until the name is free, otherwise errNameInUse is returned.
+// The name is freed by deprovision.
+func (service *Service) reserveResourceName(domain string, wait bool) error {
 	// loop to add domain to those currently provisioned and wait if not available
 	// if multiple callers are in the waiting state, it is random which will execute next
 	for {
@@ -23,7 +52,11 @@ func (service *Service) provision(domain, token, keyAuth string, provider provid
 		// if didn't already exist, break loop and provision
 		if !alreadyExisted {
 			service.logger.Debugf("added resource for %s to challenge work tracker", domain)
-			break
+			return nil
+		}
+
+		if !wait {
+			return errNameInUse
 		}
 
 		service.logger.Debugf("unable to add resource for %s to challenge work tracker; waiting for resource name to become free", domain)
@@ -55,19 +88,14 @@ func (service *Service) provision(domain, token, keyAuth string, provider provid
 			return errNameUnavailable
 		}
 	}
-
-	// Provision with the appropriate provider
-	err = provider.Provision(domain, token, keyAuth)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
-// Deprovision calls the provider to deprovision the actual resource. It then removes the resource name from
-// the in use (work) tracker to indicate the name is once again available for use.
-func (service *Service) deprovision(domain, token, keyAuth string, provider providers.Service) (err error) {
+// Deprovision calls the provider to deprovision the actual resource and, if successful, removes it from the
+// journal. It then removes the resource name from the in use (work) tracker to indicate the name is once
+// again available for use.
+func (service *Service) deprovision(res ProvisionedResource, provider providers.Service) (err error) {
+	domain := res.Domain
+
 	// delete resource name from tracker (after the rest of the deprovisioning steps are done or failed)
 	defer func() {
 		// delete func closes the signal channel before returning true
@@ -88,10 +116,12 @@ func (service *Service) deprovision(domain, token, keyAuth string, provider prov
 	}()
 
 	// Deprovision with the appropriate provider
-	err = provider.Deprovision(domain, token, keyAuth)
+	err = provider.Deprovision(domain, res.Token, res.KeyAuth)
 	if err != nil {
 		return err
 	}
 
+	service.clearJournaledResource(res)
+
 	return nil
 }
@@ -0,0 +1,267 @@
+package challenges
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/challenges/providers"
+	"legocerthub-backend/pkg/randomness"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// self test step names
+const (
+	selfTestStepProvision   = "provision"
+	selfTestStepCheck       = "check"
+	selfTestStepDeprovision = "deprovision"
+)
+
+// selfTestFetchInterval is how often http-01 and tls-alpn-01 resources are re-fetched
+// while waiting for them to be served
+const selfTestFetchInterval = 2 * time.Second
+
+// maxSelfTestBody limits how much of an http-01 response is read
+const maxSelfTestBody = 4096
+
+// selfTestStep is the outcome of one step of a provider self test
+type selfTestStep struct {
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+	DurationMs int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// selfTestResult is the report of a provider self test
+type selfTestResult struct {
+	ProviderID       int                `json:"provider_id"`
+	ProviderType     string             `json:"provider_type"`
+	ChallengeType    acme.ChallengeType `json:"challenge_type"`
+	Domain           string             `json:"domain"`
+	ProviderSelected bool               `json:"provider_selected"`
+	Success          bool               `json:"success"`
+	DurationMs       int64              `json:"duration_ms"`
+	Steps            []selfTestStep     `json:"steps"`
+}
+
+// runStep times f and adds the outcome to the result
+func (result *selfTestResult) runStep(name string, f func() (detail string, err error)) error {
+	start := time.Now()
+	detail, err := f()
+
+	step := selfTestStep{
+		Name:       name,
+		Success:    err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+		Detail:     detail,
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	result.Steps = append(result.Steps, step)
+
+	return err
+}
+
+// selfTestProvider provisions a random challenge resource for domain using the provider,
+// checks that it can be found the same way the ACME server would look for it and then
+// deprovisions it. Each step is timed and reported. Checking stops after timeout.
+func (service *Service) selfTestProvider(providerID int, providerKey string, providerType string, provider providers.Service, domain string, timeout time.Duration) *selfTestResult {
+	start := time.Now()
+
+	result := &selfTestResult{
+		ProviderID:    providerID,
+		ProviderType:  providerType,
+		ChallengeType: provider.AcmeChallengeType(),
+		Domain:        domain,
+	}
+
+	// note if the provider would actually be used for domain
+	selected, err := service.Providers.ProvidersFor(acme.NewIdentifier(domain), false)
+	if err == nil {
+		for _, oneP := range selected {
+			if oneP.ID == providerID {
+				result.ProviderSelected = true
+				break
+			}
+		}
+	}
+
+	// random resource (tokens are base64url, see: rfc8555 s 8.1)
+	token := randomness.GenerateInsecureString(43)
+	keyAuth := token + "." + randomness.GenerateInsecureString(43)
+
+	// don't wait on (or interfere with) a real challenge using the name
+	err = service.reserveResourceName(domain, false)
+	if err != nil {
+		_ = result.runStep(selfTestStepProvision, func() (string, error) { return "", err })
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result
+	}
+
+	res := &ProvisionedResource{
+		ProviderID:   providerID,
+		ProviderKey:  providerKey,
+		ProviderType: providerType,
+		Domain:       domain,
+		Token:        token,
+		KeyAuth:      keyAuth,
+	}
+
+	// provision
+	service.shutdownWaitgroup.Add(1)
+	provErr := result.runStep(selfTestStepProvision, func() (string, error) {
+		return "", service.provisionReserved(res, provider)
+	})
+
+	// check
+	checkErr := provErr
+	if provErr == nil {
+		checkErr = result.runStep(selfTestStepCheck, func() (string, error) {
+			return service.selfTestCheck(provider, domain, token, keyAuth, timeout)
+		})
+	}
+
+	// always deprovision (even if provisioning failed) to clean up anything partially created
+	deprovErr := result.runStep(selfTestStepDeprovision, func() (string, error) {
+		return "", service.deprovision(*res, provider)
+	})
+	service.shutdownWaitgroup.Done()
+
+	result.Success = checkErr == nil && deprovErr == nil
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	return result
+}
+
+// selfTestCheck confirms the provisioned resource is being served
+func (service *Service) selfTestCheck(provider providers.Service, domain, token, keyAuth string, timeout time.Duration) (detail string, err error) {
+	switch provider.AcmeChallengeType() {
+	case acme.ChallengeTypeDns01:
+		dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
+
+		delegatedFqdn := ""
+		if delegator, ok := provider.(providers.DelegatingService); ok {
+			delegatedFqdn, _ = delegator.DelegatedFqdn(domain)
+		}
+
+		propagated, path := service.dnsChecker.CheckTXTWithTimeout(dnsRecordName, dnsRecordValue, delegatedFqdn, timeout)
+		if len(path) > 1 {
+			detail = "followed cname chain " + strings.Join(path, " -> ")
+		}
+		if !propagated {
+			return detail, fmt.Errorf("dns TXT record %s did not propagate within %s", dnsRecordName, timeout)
+		}
+
+		return detail, nil
+
+	case acme.ChallengeTypeHttp01:
+		url := selfTestHttp01Url(domain, token)
+		return "fetched " + url, service.retryUntil(timeout, func(ctx context.Context) error {
+			return service.fetchHttp01(ctx, url, keyAuth)
+		})
+
+	case acme.ChallengeTypeTlsAlpn01:
+		address := net.JoinHostPort(domain, "443")
+		return "connected to " + address, service.retryUntil(timeout, func(ctx context.Context) error {
+			return fetchTlsAlpn01(ctx, address, domain, keyAuth)
+		})
+
+	default:
+		return "", fmt.Errorf("self test of challenge type %s is not supported", provider.AcmeChallengeType())
+	}
+}
+
+// retryUntil calls f until it succeeds or timeout elapses (or shutdown is called) and
+// returns the last error
+func (service *Service) retryUntil(timeout time.Duration, f func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(service.shutdownContext, timeout)
+	defer cancel()
+
+	for {
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(selfTestFetchInterval):
+			// try again
+		}
+	}
+}
+
+// selfTestHttp01Url returns the url the acme server will fetch to validate an http-01
+// challenge (see: rfc8555 s 8.3)
+func selfTestHttp01Url(domain, token string) string {
+	host := domain
+	// ipv6 must be bracketed
+	if strings.Contains(domain, ":") {
+		host = "[" + domain + "]"
+	}
+
+	return "http://" + host + "/.well-known/acme-challenge/" + token
+}
+
+// fetchHttp01 fetches url and returns an error if the response is not the key
+// authorization
+func (service *Service) fetchHttp01(ctx context.Context, url, keyAuth string) error {
+	resp, err := service.app.GetHttpClient().GetWithContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSelfTestBody))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+
+	// servers commonly add trailing whitespace, the acme server should ignore it
+	if strings.TrimSpace(string(body)) != keyAuth {
+		return fmt.Errorf("%s response does not match key authorization", url)
+	}
+
+	return nil
+}
+
+// fetchTlsAlpn01 connects to address using the tls-alpn-01 protocol and returns an error
+// if the certificate served is not the validation certificate (see: rfc8737 s 3)
+func fetchTlsAlpn01(ctx context.Context, address, domain, keyAuth string) error {
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName: acme.TlsAlpn01ServerName(domain),
+			NextProtos: []string{acme.TlsAlpn01Protocol},
+			// validation certs are self-signed
+			InsecureSkipVerify: true,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if state.NegotiatedProtocol != acme.TlsAlpn01Protocol {
+		return fmt.Errorf("%s did not negotiate %s", address, acme.TlsAlpn01Protocol)
+	}
+	if len(state.PeerCertificates) != 1 {
+		return errors.New("tls-alpn-01 server must present exactly one certificate")
+	}
+
+	return acme.CheckTlsAlpn01Certificate(state.PeerCertificates[0], keyAuth)
+}
@@ -8,6 +8,7 @@ import (
 	"legocerthub-backend/pkg/datatypes/safemap"
 	"legocerthub-backend/pkg/httpclient"
 	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/pagination_sort"
 	"sync"
 
 	"go.uber.org/zap"
@@ -24,9 +25,23 @@ type application interface {
 	GetShutdownContext() context.Context
 	GetShutdownWaitGroup() *sync.WaitGroup
 	GetOutputter() *output.Service
+	GetChallengesStorage() Storage
 
 	// for providers
 	GetHttpClient() *httpclient.Client
+	GetSecretsKeyPath() string
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	PostChallengeEvent(event Event) error
+	GetChallengeEvents(filter EventFilter, q pagination_sort.Query) (events []Event, totalRowCount int, err error)
+	GetChallengeProviderStats(since int) ([]ProviderStats, error)
+	DeleteChallengeEventsBefore(before int) (count int, err error)
+
+	PostChallengeResource(res ProvisionedResource) (id int, err error)
+	GetAllChallengeResources() ([]ProvisionedResource, error)
+	DeleteChallengeResource(id int) error
 }
 
 // Config holds all of the challenge config
@@ -43,9 +58,11 @@ type Service struct {
 	shutdownContext   context.Context
 	shutdownWaitgroup *sync.WaitGroup
 	output            *output.Service
+	storage           Storage
 	dnsChecker        *dns_checker.Service
 	Providers         *providers.Manager
 	resourcesInUse    *safemap.SafeMap[chan struct{}] // tracks all resource names currently in use (regardless of provider)
+	failover          *failover
 }
 
 // NewService creates a new service
@@ -67,6 +84,12 @@ func NewService(app application, cfg *Config) (service *Service, err error) {
 	// output
 	service.output = app.GetOutputter()
 
+	// storage
+	service.storage = app.GetChallengesStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
 	// shutdown context & wg
 	service.shutdownContext = app.GetShutdownContext()
 	service.shutdownWaitgroup = app.GetShutdownWaitGroup()
@@ -88,5 +111,18 @@ func NewService(app application, cfg *Config) (service *Service, err error) {
 	// make tracking map
 	service.resourcesInUse = safemap.NewSafeMap[chan struct{}]()
 
+	// make provider failover tracking
+	service.failover = newFailover()
+
+	// clean up resources left behind if the app previously stopped while solving
+	service.shutdownWaitgroup.Add(1)
+	go func() {
+		defer service.shutdownWaitgroup.Done()
+		service.cleanupOrphanedResources()
+	}()
+
+	// remove challenge history that is too old to be used
+	service.startHistoryPruner()
+
 	return service, nil
 }
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/challenges/providers"
 	"legocerthub-backend/pkg/randomness"
+	"sort"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -13,18 +15,91 @@ import (
 var (
 	errDnsDidntPropagate         = errors.New("solving failed: dns record didn't propagate")
 	errChallengeRetriesExhausted = errors.New("solving failed: challenge failed to move to final state")
-	errChallengeTypeNotFound     = errors.New("solving failed: provider's challenge type not found in challenges array (possibly trying to use a wildcard with http-01)")
+	errChallengeInvalid          = errors.New("solving failed: challenge status invalid")
+	errChallengeTypeNotFound     = errors.New("solving failed: provider's challenge type not found in challenges array (possibly trying to use a wildcard with http-01 or tls-alpn-01)")
 )
 
-// Solve accepts an ACME identifier and a slice of challenges and then solves the challenge using a provider
-// for the specific domain. If no provider exists or solving otherwise fails, an error is returned.
-func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Challenge, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
-	// get provider for identifier
-	provider, err := service.Providers.ProviderFor(identifier)
+// SolvedBy records which challenge type and provider solved (or failed) an authorization
+type SolvedBy struct {
+	ChallengeType acme.ChallengeType
+	ProviderID    int
+	ProviderType  string
+}
+
+// Solve accepts an ACME identifier (and whether it is a wildcard) and a slice of challenges and then
+// solves a challenge using the providers for the specific domain. Providers are tried in priority
+// order until one succeeds. Once a challenge has been submitted to the ACME server for validation its
+// result is final (an invalid challenge invalidates the authorization, see: rfc8555 s 7.1.6), so the
+// next provider is only tried here if provisioning or the checks done before submitting fail. If the
+// submitted challenge is invalid, the provider is demoted for the identifier (tried after its other
+// providers) and, if another provider remains, TakeFailover reports it so a new order can be placed.
+// If no provider exists or solving otherwise fails, an error is returned. solvedBy is the provider
+// whose challenge was submitted. Each step of each provider's attempt is recorded in the challenge
+// history.
+func (service *Service) Solve(identifier acme.Identifier, wildcard bool, challenges []acme.Challenge, key acme.AccountKey, acmeService *acme.Service) (status string, solvedBy *SolvedBy, err error) {
+	// get providers for identifier
+	candidates, err := service.Providers.ProvidersFor(identifier, wildcard)
 	if err != nil {
-		return "", err
+		return "", nil, err
+	}
+
+	// providers that recently failed validation for this identifier go last
+	failoverId := failoverIdentifier(identifier, wildcard)
+	demoted := service.failover.demotedKeys(failoverId)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return !demoted[candidates[i].Key] && demoted[candidates[j].Key]
+	})
+
+	for i, provider := range candidates {
+		var submitted bool
+		rec := service.newAttemptRecorder(identifier, provider.ID, provider.Key, provider.Type, provider.AcmeChallengeType())
+		status, submitted, err = service.solveWithProvider(identifier, challenges, key, acmeService, provider.Service, rec)
+
+		if submitted {
+			solvedBy = &SolvedBy{
+				ChallengeType: provider.AcmeChallengeType(),
+				ProviderID:    provider.ID,
+				ProviderType:  provider.Type,
+			}
+
+			if err == nil {
+				service.logger.Infof("challenge for %s using provider %d (%s) finished with status %s", identifier.Value, provider.ID, provider.Type, status)
+
+				switch status {
+				case "valid":
+					service.failover.restore(failoverId, provider.Key)
+				case "invalid":
+					candidateKeys := []string{}
+					for _, candidate := range candidates {
+						candidateKeys = append(candidateKeys, candidate.Key)
+					}
+					if service.failover.demote(failoverId, provider.Key, candidateKeys) {
+						service.logger.Warnf("challenge for %s using provider %d (%s) failed validation, provider demoted for this identifier and the next provider will be used on a new order", identifier.Value, provider.ID, provider.Type)
+					}
+				}
+			}
+
+			return status, solvedBy, err
+		}
+
+		// another provider won't help if rate limited or shutting down
+		if _, rateLimited := acme.RateLimitedUntil(err); rateLimited || errors.Is(err, errShutdown) {
+			return "", nil, err
+		}
+
+		// failed before submitting, try next provider (if any)
+		if i < len(candidates)-1 {
+			service.logger.Warnf("challenge for %s using provider %d (%s) failed (%s), trying next provider", identifier.Value, provider.ID, provider.Type, err)
+		}
 	}
 
+	return "", nil, err
+}
+
+// solveWithProvider solves a challenge of the provider's type. submitted is true if the
+// challenge was submitted to the ACME server for validation. The outcome of each step is
+// saved using rec.
+func (service *Service) solveWithProvider(identifier acme.Identifier, challenges []acme.Challenge, key acme.AccountKey, acmeService *acme.Service, provider providers.Service, rec *attemptRecorder) (status string, submitted bool, err error) {
 	// range to the correct challenge to solve based on ACME Challenge Type (from provider)
 	challengeType := provider.AcmeChallengeType()
 	var challenge acme.Challenge
@@ -38,7 +113,7 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 		}
 	}
 	if !found {
-		return "", errChallengeTypeNotFound
+		return "", false, errChallengeTypeNotFound
 	}
 
 	// vars for provision/deprovision
@@ -46,13 +121,24 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 	token := challenge.Token
 	keyAuth, err := key.KeyAuthorization(token)
 	if err != nil {
-		return "", fmt.Errorf("failed to make key auth (%s)", err)
+		return "", false, fmt.Errorf("failed to make key auth (%s)", err)
+	}
+
+	res := &ProvisionedResource{
+		ProviderID:   rec.base.ProviderID,
+		ProviderKey:  rec.base.ProviderKey,
+		ProviderType: rec.base.ProviderType,
+		Domain:       domain,
+		Token:        token,
+		KeyAuth:      keyAuth,
 	}
 
 	// provision the needed resource for validation and defer deprovisioning
 	// add to wg to ensure deprovision completes during shutdown
 	service.shutdownWaitgroup.Add(1)
-	err = service.provision(domain, token, keyAuth, provider)
+	provisionStart := time.Now()
+	err = service.provision(res, provider)
+	rec.record(StepProvision, provisionStart, err)
 	// do error check after Deprovision to ensure any records that were created
 	// get cleaned up, even if Provision errored.
 
@@ -60,7 +146,9 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 		// wg done do shutdown can proceed after deprovision
 		defer service.shutdownWaitgroup.Done()
 
-		err := service.deprovision(domain, token, keyAuth, provider)
+		deprovisionStart := time.Now()
+		err := service.deprovision(*res, provider)
+		rec.record(StepDeprovision, deprovisionStart, err)
 		if err != nil {
 			service.logger.Errorf("challenge solver deprovision failed (%s)", err)
 		}
@@ -68,7 +156,7 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 
 	// Provision error check
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	// if using dns-01 provider, utilize dnsChecker
@@ -77,12 +165,21 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 			// get dns record to check
 			dnsRecordName, dnsRecordValue := acme.ValidationResourceDns01(domain, keyAuth)
 
+			// if the provider writes to a delegated name, the checker confirms the CNAME leads there
+			delegatedFqdn := ""
+			if delegator, ok := provider.(providers.DelegatingService); ok {
+				delegatedFqdn, _ = delegator.DelegatedFqdn(domain)
+			}
+
 			// check for propagation
-			propagated := service.dnsChecker.CheckTXTWithRetry(dnsRecordName, dnsRecordValue)
+			propagationStart := time.Now()
+			propagated := service.dnsChecker.CheckTXTWithRetry(dnsRecordName, dnsRecordValue, delegatedFqdn)
 			// if failed to propagate
 			if !propagated {
-				return "", errDnsDidntPropagate
+				rec.record(StepPropagation, propagationStart, errDnsDidntPropagate)
+				return "", false, errDnsDidntPropagate
 			}
+			rec.record(StepPropagation, propagationStart, nil)
 		} else {
 			// dnschecker is needed but not configured, shouldn't happen but deal with it just in case
 			sleepWait := 240
@@ -97,9 +194,11 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 	// valid or invalid state.
 
 	// inform ACME that the challenge is ready
+	validationStart := time.Now()
 	_, err = acmeService.ValidateChallenge(challenge.Url, key)
 	if err != nil {
-		return "", err
+		rec.record(StepValidation, validationStart, err)
+		return "", false, err
 	}
 
 	// sleep a little before first check
@@ -136,8 +235,19 @@ func (service *Service) Solve(identifier acme.Identifier, challenges []acme.Chal
 	err = backoff.RetryNotify(challCheckFunc, bo, notifyFunc)
 	// if err returned, retry was exhausted
 	if err != nil {
-		return "", errChallengeRetriesExhausted
+		rec.record(StepValidation, validationStart, errChallengeRetriesExhausted)
+		return "", true, errChallengeRetriesExhausted
+	}
+
+	// record the acme server's reason if invalid
+	var validationErr error
+	if challenge.Status == "invalid" {
+		validationErr = errChallengeInvalid
+		if challenge.Error != nil {
+			validationErr = challenge.Error
+		}
 	}
+	rec.record(StepValidation, validationStart, validationErr)
 
-	return challenge.Status, nil
+	return challenge.Status, true, nil
 }
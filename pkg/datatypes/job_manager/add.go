@@ -2,6 +2,7 @@ package job_manager
 
 import (
 	"errors"
+	"time"
 )
 
 var (
@@ -13,6 +14,12 @@ var (
 // Equal() function to determine if the job already exists in manager. If the
 // job already exists, it is not added again.
 func (mgr *Manager[V]) AddJob(job V) error {
+	return mgr.AddJobAfter(job, time.Time{})
+}
+
+// AddJobAfter is the same as AddJob except the job won't be started before
+// notBefore. Until then, it waits in the queue like any other job.
+func (mgr *Manager[V]) AddJobAfter(job V, notBefore time.Time) error {
 	// fail if zeroVal
 	var zeroVal V
 	if job.Equal(zeroVal) {
@@ -28,18 +35,26 @@ func (mgr *Manager[V]) AddJob(job V) error {
 		return ErrAddDuplicateJob
 	}
 
-	// add to work queue
-	mgr.waitingJobs = append(mgr.waitingJobs, job)
-
-	// send ID to the appropriate channel
-	// async required as this will block until other end of channel reads the job
-	go func() {
-		if job.IsHighPriority() {
-			mgr.highJobsChan <- job
-		} else {
-			mgr.lowJobsChan <- job
-		}
-	}()
+	mgr.unsafeAddWaiting(job, notBefore)
 
 	return nil
 }
+
+// unsafeAddWaiting adds the job to the queue and wakes a worker to take it (once
+// notBefore has passed).
+// Manager MUST be Locked before calling this func.
+func (mgr *Manager[V]) unsafeAddWaiting(job V, notBefore time.Time) {
+	mgr.waitingJobs = append(mgr.waitingJobs, waitingJob[V]{job: job, notBefore: notBefore})
+
+	if wait := time.Until(notBefore); wait > 0 {
+		time.AfterFunc(wait, func() {
+			mgr.Lock()
+			defer mgr.Unlock()
+
+			mgr.jobsChanged.Broadcast()
+		})
+		return
+	}
+
+	mgr.jobsChanged.Signal()
+}
@@ -0,0 +1,86 @@
+package job_manager
+
+import (
+	"errors"
+)
+
+var (
+	ErrJobNotFound = errors.New("job manager: job not found")
+	ErrJobRunning  = errors.New("job manager: job is already running (only waiting jobs can be changed)")
+)
+
+// unsafeWaitingIndex returns the index of the Equal waiting job. If the job isn't
+// waiting, ErrJobRunning or ErrJobNotFound is returned.
+// Manager MUST be AT LEAST RLocked before callign this func.
+func (mgr *Manager[V]) unsafeWaitingIndex(job V) (int, error) {
+	workerNumb := mgr.unsafeJobExists(job)
+	if workerNumb == nil {
+		return -1, ErrJobNotFound
+	}
+
+	for i := range mgr.waitingJobs {
+		if job.Equal(mgr.waitingJobs[i].job) {
+			return i, nil
+		}
+	}
+
+	return -1, ErrJobRunning
+}
+
+// CancelJob removes the Equal job from the queue. Only waiting (including deferred)
+// jobs can be canceled.
+func (mgr *Manager[V]) CancelJob(job V) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	i, err := mgr.unsafeWaitingIndex(job)
+	if err != nil {
+		return err
+	}
+
+	mgr.waitingJobs = append(mgr.waitingJobs[:i], mgr.waitingJobs[i+1:]...)
+
+	return nil
+}
+
+// PromoteJob makes the Equal waiting job high priority. It keeps its place in the
+// queue relative to the other high priority jobs.
+func (mgr *Manager[V]) PromoteJob(job V) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	i, err := mgr.unsafeWaitingIndex(job)
+	if err != nil {
+		return err
+	}
+
+	mgr.waitingJobs[i].job.SetHighPriority()
+
+	return nil
+}
+
+// Pause stops workers from starting any more jobs. Jobs that are already running
+// finish and new jobs can still be added to the queue.
+func (mgr *Manager[V]) Pause() {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	mgr.paused = true
+}
+
+// Resume allows workers to start jobs again after Pause
+func (mgr *Manager[V]) Resume() {
+	mgr.Lock()
+	defer mgr.Unlock()
+
+	mgr.paused = false
+	mgr.jobsChanged.Broadcast()
+}
+
+// IsPaused returns if the manager is paused
+func (mgr *Manager[V]) IsPaused() bool {
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	return mgr.paused
+}
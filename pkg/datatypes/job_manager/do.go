@@ -1,29 +1,101 @@
 package job_manager
 
-// do updates the job to assign it to a worker and then executes the internal 'real'
-// job
-func (mgr *Manager[V]) doJob(job V, workerID int) {
-	// move job from waiting to working
+import (
+	"context"
+	"time"
+)
+
+// nextJob blocks until a job is waiting (and due to start) and the manager is not paused.
+// The job is then moved from waiting to the worker and returned. High priority jobs are
+// always taken before low priority jobs, otherwise jobs are taken in the order they were
+// added. If shutdown occurs first, ok is false.
+func (mgr *Manager[V]) nextJob(workerID int, shutdownCtx context.Context) (job V, ok bool) {
 	mgr.Lock()
-	for i, waitingJ := range mgr.waitingJobs {
-		if waitingJ.Equal(job) {
-			// remove from waiting
-			mgr.waitingJobs[i] = mgr.waitingJobs[len(mgr.waitingJobs)-1]
-			mgr.waitingJobs = mgr.waitingJobs[:len(mgr.waitingJobs)-1]
-
-			// add to worker
-			mgr.workingJobs[workerID] = job
+	defer mgr.Unlock()
+
+	for {
+		if shutdownCtx.Err() != nil {
+			return job, false
+		}
+
+		if !mgr.paused {
+			i := mgr.unsafeNextJobIndex()
+			if i >= 0 {
+				job = mgr.waitingJobs[i].job
+
+				// remove from waiting (keeping order)
+				mgr.waitingJobs = append(mgr.waitingJobs[:i], mgr.waitingJobs[i+1:]...)
+
+				// add to worker
+				mgr.workingJobs[workerID] = job
+				mgr.workingStart[workerID] = time.Now()
+
+				return job, true
+			}
+		}
+
+		mgr.jobsChanged.Wait()
+	}
+}
+
+// unsafeNextJobIndex returns the index of the waiting job that should be worked next,
+// or -1 if there are no waiting jobs that are due to start.
+// Manager MUST be AT LEAST RLocked before callign this func.
+func (mgr *Manager[V]) unsafeNextJobIndex() int {
+	now := time.Now()
+	next := -1
+
+	for i := range mgr.waitingJobs {
+		// deferred and not due yet
+		if mgr.waitingJobs[i].notBefore.After(now) {
+			continue
 		}
 
+		if mgr.waitingJobs[i].job.IsHighPriority() {
+			return i
+		}
+
+		if next < 0 {
+			next = i
+		}
 	}
-	mgr.Unlock()
 
+	return next
+}
+
+// do executes the internal 'real' job (which must already be assigned to the worker)
+// and then records it as finished. If the job deferred itself, it is put back in the
+// queue.
+func (mgr *Manager[V]) doJob(job V, workerID int) {
 	// run job
 	job.Do(workerID)
 
 	// after job completes, remove it from worker
 	mgr.Lock()
+	defer mgr.Unlock()
+
+	info := JobInfo[V]{
+		Job:          job,
+		HighPriority: job.IsHighPriority(),
+		QueuedAt:     job.QueuedAt(),
+		StartedAt:    mgr.workingStart[workerID],
+		FinishedAt:   time.Now(),
+	}
+
 	var zeroVal V
 	mgr.workingJobs[workerID] = zeroVal
-	mgr.Unlock()
+	delete(mgr.workingStart, workerID)
+
+	// keep the most recently finished jobs
+	mgr.finishedJobs = append([]JobInfo[V]{info}, mgr.finishedJobs...)
+	if len(mgr.finishedJobs) > maxFinishedJobs {
+		mgr.finishedJobs = mgr.finishedJobs[:maxFinishedJobs]
+	}
+
+	// requeue deferred job
+	if deferrable, ok := any(job).(DeferrableJob); ok {
+		if notBefore := deferrable.DeferredUntil(); !notBefore.IsZero() {
+			mgr.unsafeAddWaiting(job, notBefore)
+		}
+	}
 }
@@ -3,10 +3,15 @@ package job_manager
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+// maxFinishedJobs is how many of the most recently finished jobs are kept for
+// reporting their timing
+const maxFinishedJobs = 25
+
 // Job is the interface that the external job struct will need to satisfy
 type Job[V any] interface {
 	// Description should return information to help identify a specific job in the logs
@@ -16,6 +21,13 @@ type Job[V any] interface {
 	// IsHighPriority returns if the job should be considered high priority
 	IsHighPriority() bool
 
+	// SetHighPriority makes the job high priority (it is only called while the job
+	// is waiting in the queue)
+	SetHighPriority()
+
+	// QueuedAt returns when the job was originally queued
+	QueuedAt() time.Time
+
 	// Equal compares two jobs to determine if the job should be considered duplicate
 	// and therefore not be added if it has already been added
 	Equal(job V) bool
@@ -24,15 +36,34 @@ type Job[V any] interface {
 	Do(workerID int)
 }
 
+// DeferrableJob is optionally implemented by jobs that may need to run again later
+// (e.g. when rate limited). If DeferredUntil returns a non-zero time after Do
+// completes, the job is put back in the queue and won't start before that time.
+type DeferrableJob interface {
+	DeferredUntil() time.Time
+}
+
+// waitingJob is a job in the queue and the earliest time it may start (zero if it
+// may start right away)
+type waitingJob[V Job[V]] struct {
+	job       V
+	notBefore time.Time
+}
+
 // Manager manages jobs and their interaction with the workers
 type Manager[V Job[V]] struct {
 	// readable list of all jobs in the manager
-	workingJobs map[int]V // workerID:job
-	waitingJobs []V
+	workingJobs  map[int]V         // workerID:job
+	workingStart map[int]time.Time // workerID:time job started
+	waitingJobs  []waitingJob[V]   // in the order they were added
+	finishedJobs []JobInfo[V]      // newest first
 
-	// channels to send work to workers
-	highJobsChan chan V
-	lowJobsChan  chan V
+	// paused stops workers from starting new jobs
+	paused bool
+
+	// jobsChanged signals workers when a job is added, a deferred job is due, or the
+	// manager is resumed
+	jobsChanged *sync.Cond
 
 	sync.RWMutex
 }
@@ -46,11 +77,19 @@ func NewManager[V Job[V]](workerCount int, workLabel string, shutdownCtx context
 
 	// make manager
 	mgr := &Manager[V]{
-		workingJobs: make(map[int]V),
-
-		highJobsChan: make(chan V),
-		lowJobsChan:  make(chan V),
+		workingJobs:  make(map[int]V),
+		workingStart: make(map[int]time.Time),
 	}
+	mgr.jobsChanged = sync.NewCond(&mgr.RWMutex)
+
+	// wake all workers on shutdown so they can exit
+	go func() {
+		<-shutdownCtx.Done()
+
+		mgr.Lock()
+		mgr.jobsChanged.Broadcast()
+		mgr.Unlock()
+	}()
 
 	// make workers
 	for i := 0; i < workerCount; i++ {
@@ -65,40 +104,21 @@ func NewManager[V Job[V]](workerCount int, workLabel string, shutdownCtx context
 			defer shutdownWg.Done()
 			logger.Debugf("%s worker %d: started", workLabel, workerId)
 
-		doingWork:
 			for {
-				select {
-				case <-shutdownCtx.Done():
-					// break to shutdown
-					break doingWork
-
-				case highJob := <-mgr.highJobsChan:
-					logger.Debugf("%s worker %d: start high priority job (%s)", workLabel, workerId, highJob.Description())
-					mgr.doJob(highJob, workerId)
-					logger.Debugf("%s worker %d: end high priority job (%s)", workLabel, workerId, highJob.Description())
-
-				case lowJob := <-mgr.lowJobsChan:
-				lower:
-					for {
-						select {
-						case <-shutdownCtx.Done():
-							// break to shutdown
-							break doingWork
-
-						case highJob := <-mgr.highJobsChan:
-							logger.Debugf("%s worker %d: start high priority job (%s)", workLabel, workerId, highJob.Description())
-							mgr.doJob(highJob, workerId)
-							logger.Debugf("%s worker %d: end high priority job (%s)", workLabel, workerId, highJob.Description())
-
-						default:
-							break lower
-						}
-					}
-
-					logger.Debugf("%s worker %d: start low priority job (%s)", workLabel, workerId, lowJob.Description())
-					mgr.doJob(lowJob, workerId)
-					logger.Debugf("%s worker %d: end low priority job (%s)", workLabel, workerId, lowJob.Description())
+				// blocks until there is a job for this worker (or shutdown)
+				job, ok := mgr.nextJob(workerId, shutdownCtx)
+				if !ok {
+					break
 				}
+
+				priority := "low"
+				if job.IsHighPriority() {
+					priority = "high"
+				}
+
+				logger.Debugf("%s worker %d: start %s priority job (%s)", workLabel, workerId, priority, job.Description())
+				mgr.doJob(job, workerId)
+				logger.Debugf("%s worker %d: end %s priority job (%s)", workLabel, workerId, priority, job.Description())
 			}
 
 			logger.Debugf("%s worker %d: shutdown complete", workLabel, workerId)
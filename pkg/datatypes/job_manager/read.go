@@ -1,5 +1,7 @@
 package job_manager
 
+import "time"
+
 // unsafeJobExists searches for an Equal job in manager. If one is found,
 // the worker number it is associated with is returned. If the job is in
 // queue without a worker, a negative number is returned. If the job is not
@@ -24,7 +26,7 @@ func (mgr *Manager[V]) unsafeJobExists(job V) *int {
 
 	// check waiting
 	for i, mgrJ := range mgr.waitingJobs {
-		if !mgrJ.Equal(zeroVal) && job.Equal(mgrJ) {
+		if !mgrJ.job.Equal(zeroVal) && job.Equal(mgrJ.job) {
 			i *= -1
 			return &i
 		}
@@ -50,32 +52,134 @@ func (mgr *Manager[V]) JobExists(job V) *int {
 	return mgr.unsafeJobExists(job)
 }
 
+// JobInfo is a job in the manager along with its timing. StartedAt is zero if
+// the job is still waiting and FinishedAt is zero if it hasn't finished. NotBefore
+// is only set for a waiting job that was deferred.
+type JobInfo[V Job[V]] struct {
+	Job          V
+	HighPriority bool
+	QueuedAt     time.Time
+	NotBefore    time.Time
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// Duration returns how long the job ran (or has been running, if not finished). If
+// the job hasn't started, 0 is returned.
+func (info JobInfo[V]) Duration() time.Duration {
+	if info.StartedAt.IsZero() {
+		return 0
+	}
+
+	if info.FinishedAt.IsZero() {
+		return time.Since(info.StartedAt)
+	}
+
+	return info.FinishedAt.Sub(info.StartedAt)
+}
+
+// unsafeWorkingInfo returns the JobInfo of the job the worker is working on, or nil
+// if the worker is idle.
+// Manager MUST be AT LEAST RLocked before callign this func.
+func (mgr *Manager[V]) unsafeWorkingInfo(workerID int) *JobInfo[V] {
+	// workers only have a start time while working
+	startedAt, working := mgr.workingStart[workerID]
+	if !working {
+		return nil
+	}
+
+	job := mgr.workingJobs[workerID]
+	return &JobInfo[V]{
+		Job:          job,
+		HighPriority: job.IsHighPriority(),
+		QueuedAt:     job.QueuedAt(),
+		StartedAt:    startedAt,
+	}
+}
+
+// unsafeWaitingInfo returns the JobInfo of a waiting job.
+// Manager MUST be AT LEAST RLocked before callign this func.
+func unsafeWaitingInfo[V Job[V]](waiting waitingJob[V]) JobInfo[V] {
+	return JobInfo[V]{
+		Job:          waiting.job,
+		HighPriority: waiting.job.IsHighPriority(),
+		QueuedAt:     waiting.job.QueuedAt(),
+		NotBefore:    waiting.notBefore,
+	}
+}
+
 // allManagerJobs is a struct to return all of the jobs currently in Manager
 type AllManagerJobs[V Job[V]] struct {
-	WorkingJobs map[int]V // workerID:job
-	WaitingJobs []V
+	Paused       bool
+	WorkingJobs  map[int]*JobInfo[V] // workerID:job (nil if worker is idle)
+	WaitingJobs  []JobInfo[V]
+	FinishedJobs []JobInfo[V] // most recently finished, newest first
 }
 
 // AllCurrentJobs returns all of the jobs in manager. Jobs are separated by those
-// currently being worked on, and those waiting in the queue.
+// currently being worked on, those waiting in the queue, and those that recently
+// finished.
 func (mgr *Manager[V]) AllCurrentJobs() *AllManagerJobs[V] {
 	mgr.RLock()
 	defer mgr.RUnlock()
 
 	// working jobs
-	workingJobs := make(map[int]V)
-	for i, mgrJob := range mgr.workingJobs {
-		// copy jobs to new map
-		workingJobs[i] = mgrJob
+	workingJobs := make(map[int]*JobInfo[V])
+	for workerID := range mgr.workingJobs {
+		workingJobs[workerID] = mgr.unsafeWorkingInfo(workerID)
 	}
 
 	// waiting (queue) jobs
-	waitingJobs := make([]V, len(mgr.waitingJobs))
-	_ = copy(waitingJobs, mgr.waitingJobs)
+	waitingJobs := make([]JobInfo[V], 0, len(mgr.waitingJobs))
+	for _, waiting := range mgr.waitingJobs {
+		waitingJobs = append(waitingJobs, unsafeWaitingInfo(waiting))
+	}
+
+	// finished jobs
+	finishedJobs := make([]JobInfo[V], len(mgr.finishedJobs))
+	_ = copy(finishedJobs, mgr.finishedJobs)
 
 	// return result
 	return &AllManagerJobs[V]{
-		WorkingJobs: workingJobs,
-		WaitingJobs: waitingJobs,
+		Paused:       mgr.paused,
+		WorkingJobs:  workingJobs,
+		WaitingJobs:  waitingJobs,
+		FinishedJobs: finishedJobs,
 	}
 }
+
+// FindJob returns the JobInfo of the Equal job in the manager. Working and waiting
+// jobs are checked first, then recently finished jobs. If the job is not found,
+// nil is returned.
+func (mgr *Manager[V]) FindJob(job V) *JobInfo[V] {
+	// zero value job will never be in manager
+	var zeroVal V
+	if job.Equal(zeroVal) {
+		return nil
+	}
+
+	mgr.RLock()
+	defer mgr.RUnlock()
+
+	for workerID, mgrJ := range mgr.workingJobs {
+		if !mgrJ.Equal(zeroVal) && job.Equal(mgrJ) {
+			return mgr.unsafeWorkingInfo(workerID)
+		}
+	}
+
+	for _, mgrJ := range mgr.waitingJobs {
+		if job.Equal(mgrJ.job) {
+			info := unsafeWaitingInfo(mgrJ)
+			return &info
+		}
+	}
+
+	for i := range mgr.finishedJobs {
+		if job.Equal(mgr.finishedJobs[i].Job) {
+			info := mgr.finishedJobs[i]
+			return &info
+		}
+	}
+
+	return nil
+}
@@ -49,6 +49,14 @@ func (rb *RingBuffer[V]) lenUnsafe() (length int) {
 	return rb.size - rb.readNext + rb.writeNext
 }
 
+// Len returns the number of values currently in the ring buffer
+func (rb *RingBuffer[V]) Len() int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	return rb.lenUnsafe()
+}
+
 // Read reads the value from the next read position and then
 // updates the buffer properties accordingly.  An error is returned
 // if the buffer is empty
@@ -78,6 +78,8 @@ type accountDetailedResponse struct {
 	CreatedAt  int                           `json:"created_at"`
 	UpdatedAt  int                           `json:"updated_at"`
 	Kid        string                        `json:"kid"`
+	// from acme service
+	RateLimitedUntil *int `json:"rate_limited_until"`
 }
 
 type accountServerDetailedResponse struct {
@@ -98,6 +100,16 @@ func (acct Account) detailedResponse(service *Service) (accountDetailedResponse,
 		return accountDetailedResponse{}, err
 	}
 
+	// rate limit (only applies to registered accounts)
+	var rateLimitedUntil *int
+	if acct.Kid != "" {
+		blockedUntil := as.BlockedUntil(acme.AccountKey{Kid: acct.Kid})
+		if !blockedUntil.IsZero() {
+			rateLimitedUntil = new(int)
+			*rateLimitedUntil = int(blockedUntil.Unix())
+		}
+	}
+
 	return accountDetailedResponse{
 		AccountSummaryResponse: acct.SummaryResponse(),
 		AcmeServer: accountServerDetailedResponse{
@@ -120,6 +132,8 @@ func (acct Account) detailedResponse(service *Service) (accountDetailedResponse,
 		CreatedAt: acct.CreatedAt,
 		UpdatedAt: acct.UpdatedAt,
 		Kid:       acct.Kid,
+
+		RateLimitedUntil: rateLimitedUntil,
 	}, nil
 }
 
@@ -21,7 +21,7 @@ func (service *Service) DeleteAccount(w http.ResponseWriter, r *http.Request) *o
 
 	// validation
 	// verify account exists
-	_, outErr := service.getAccount(id)
+	_, outErr := service.GetAccount(id)
 	if outErr != nil {
 		return outErr
 	}
@@ -76,7 +76,7 @@ func (service *Service) GetOneAccount(w http.ResponseWriter, r *http.Request) *o
 	}
 
 	// get from storage
-	account, outErr := service.getAccount(id)
+	account, outErr := service.GetAccount(id)
 	if outErr != nil {
 		return outErr
 	}
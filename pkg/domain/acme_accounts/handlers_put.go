@@ -39,7 +39,7 @@ func (service *Service) PutNameDescAccount(w http.ResponseWriter, r *http.Reques
 
 	// validation
 	// id
-	_, outErr := service.getAccount(payload.ID)
+	_, outErr := service.GetAccount(payload.ID)
 	if outErr != nil {
 		return outErr
 	}
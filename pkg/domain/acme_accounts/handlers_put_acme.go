@@ -40,7 +40,7 @@ func (service *Service) ChangeEmail(w http.ResponseWriter, r *http.Request) *out
 
 	// validation
 	// id
-	account, outErr := service.getAccount(id)
+	account, outErr := service.GetAccount(id)
 	if outErr != nil {
 		return outErr
 	}
@@ -137,7 +137,7 @@ func (service *Service) RolloverKey(w http.ResponseWriter, r *http.Request) *out
 
 	// validation
 	// id
-	account, outErr := service.getAccount(payload.ID)
+	account, outErr := service.GetAccount(payload.ID)
 	if outErr != nil {
 		return outErr
 	}
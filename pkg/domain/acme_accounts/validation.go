@@ -19,8 +19,8 @@ var (
 	ErrEmailBad = errors.New("email is not valid")
 )
 
-// getAccount returns the Account for the specified account id.
-func (service *Service) getAccount(id int) (Account, *output.Error) {
+// GetAccount returns the Account for the specified account id.
+func (service *Service) GetAccount(id int) (Account, *output.Error) {
 	// if id is not in valid range, it is definitely not valid
 	if !validation.IsIdExistingValidRange(id) {
 		service.logger.Debug(ErrIdBad)
@@ -112,3 +112,35 @@ func (service *Service) AccountUsable(accountId int) bool {
 
 	return false
 }
+
+// ProfileValid returns true if the specified certificate profile is available
+// on the specified account's acme server. A blank profile is always valid.
+func (service *Service) ProfileValid(accountId int, profile string) bool {
+	if profile == "" {
+		return true
+	}
+
+	account, outErr := service.GetAccount(accountId)
+	if outErr != nil {
+		return false
+	}
+
+	return service.acmeServerService.ProfileValid(account.AcmeServer.ID, profile)
+}
+
+// AutoRenewalValid returns true if the specified account's acme server supports STAR
+// orders with the specified certificate lifetime, order duration, start offset and
+// lifetime adjustment (all in seconds). Zero for all values is always valid (STAR
+// disabled).
+func (service *Service) AutoRenewalValid(accountId int, lifetime int, duration int, startOffset int, lifetimeAdjust int) bool {
+	if lifetime == 0 && duration == 0 && startOffset == 0 && lifetimeAdjust == 0 {
+		return true
+	}
+
+	account, outErr := service.GetAccount(accountId)
+	if outErr != nil {
+		return false
+	}
+
+	return service.acmeServerService.AutoRenewalValid(account.AcmeServer.ID, lifetime, duration, startOffset, lifetimeAdjust)
+}
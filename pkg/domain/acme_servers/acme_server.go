@@ -3,7 +3,9 @@ package acme_servers
 import (
 	"fmt"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/acme/nonces"
 	"legocerthub-backend/pkg/pagination_sort"
+	"time"
 )
 
 // Server is the struct for an ACME Server
@@ -42,8 +44,11 @@ type ServerSummaryResponse struct {
 	DirectoryURL string `json:"directory_url"`
 	IsStaging    bool   `json:"is_staging"`
 	// from remote server
-	ExternalAccountRequired bool   `json:"external_account_required"`
-	TermsOfService          string `json:"terms_of_service"`
+	ExternalAccountRequired bool              `json:"external_account_required"`
+	TermsOfService          string            `json:"terms_of_service"`
+	Profiles                map[string]string `json:"profiles"`
+	SupportsAutoRenewal     bool              `json:"supports_auto_renewal"`
+	RateLimitedUntil        *int              `json:"rate_limited_until"`
 }
 
 func (serv Server) summaryResponse(service *Service) (ServerSummaryResponse, error) {
@@ -60,9 +65,22 @@ func (serv Server) summaryResponse(service *Service) (ServerSummaryResponse, err
 		IsStaging:               serv.IsStaging,
 		ExternalAccountRequired: acmeService.RequiresEAB(),
 		TermsOfService:          acmeService.TosUrl(),
+		Profiles:                acmeService.Profiles(),
+		SupportsAutoRenewal:     acmeService.SupportsAutoRenewal(),
+		RateLimitedUntil:        unixTimeOrNil(acmeService.ServerBlockedUntil()),
 	}, nil
 }
 
+// unixTimeOrNil returns a pointer to the unix time of t, or nil if t is the zero time
+func unixTimeOrNil(t time.Time) *int {
+	if t.IsZero() {
+		return nil
+	}
+
+	unix := int(t.Unix())
+	return &unix
+}
+
 // serverDetailedResponse contains full details about an ACME server
 type serverDetailedResponse struct {
 	ServerSummaryResponse
@@ -107,3 +125,37 @@ func (service *Service) ListAllServersSummaries() ([]ServerSummaryResponse, erro
 
 	return serverSummaries, nil
 }
+
+// ServerNonceStatsResponse contains the nonce manager metrics of an ACME server
+type ServerNonceStatsResponse struct {
+	ID     int          `json:"id"`
+	Name   string       `json:"name"`
+	Nonces nonces.Stats `json:"nonces"`
+}
+
+// ListAllServersNonceStats returns the nonce manager metrics of all configured Servers. A
+// server whose metrics can't be read is logged and omitted.
+func (service *Service) ListAllServersNonceStats() ([]ServerNonceStatsResponse, error) {
+	// fetch from storage
+	servers, _, err := service.storage.GetAllAcmeServers(pagination_sort.QueryAll)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []ServerNonceStatsResponse{}
+	for i := range servers {
+		acmeService, err := service.AcmeService(servers[i].ID)
+		if err != nil {
+			service.logger.Errorf("failed to get nonce stats for acme server %d (%s)", servers[i].ID, err)
+			continue
+		}
+
+		stats = append(stats, ServerNonceStatsResponse{
+			ID:     servers[i].ID,
+			Name:   servers[i].Name,
+			Nonces: acmeService.NonceStats(),
+		})
+	}
+
+	return stats, nil
+}
@@ -7,6 +7,7 @@ import (
 	"legocerthub-backend/pkg/storage"
 	"legocerthub-backend/pkg/validation"
 	"strings"
+	"time"
 )
 
 var (
@@ -54,6 +55,38 @@ func (service *Service) AcmeServerValid(acmeServerId int) bool {
 	return err == nil
 }
 
+// ProfileValid returns true if the specified certificate profile is advertised by
+// the specified acme server. A blank profile is always valid (server default).
+func (service *Service) ProfileValid(acmeServerId int, profile string) bool {
+	if profile == "" {
+		return true
+	}
+
+	acmeService, err := service.AcmeService(acmeServerId)
+	if err != nil {
+		return false
+	}
+
+	return acmeService.SupportsProfile(profile)
+}
+
+// AutoRenewalValid returns true if the specified acme server supports STAR orders with
+// the specified certificate lifetime, order duration, start offset and lifetime adjustment
+// (all in seconds). Zero for all values is always valid (STAR disabled).
+func (service *Service) AutoRenewalValid(acmeServerId int, lifetime int, duration int, startOffset int, lifetimeAdjust int) bool {
+	if lifetime == 0 && duration == 0 && startOffset == 0 && lifetimeAdjust == 0 {
+		return true
+	}
+
+	acmeService, err := service.AcmeService(acmeServerId)
+	if err != nil {
+		return false
+	}
+
+	return acmeService.AutoRenewalValid(time.Duration(lifetime)*time.Second, time.Duration(duration)*time.Second,
+		time.Duration(startOffset)*time.Second, time.Duration(lifetimeAdjust)*time.Second)
+}
+
 // nameValid returns true if the specified server name is acceptable and
 // false if it is not. This check includes validating specified
 // characters and also confirms the name is not already in use by another
@@ -36,6 +36,9 @@ const dataStorageRootPath = "./data"
 const dataStorageAppDataDirName = "app"
 const dataStorageAppDataPath = dataStorageRootPath + "/" + dataStorageAppDataDirName
 
+// master key for secrets in the config file (excluded from backups)
+const dataStorageSecretsKeyPath = dataStorageRootPath + "/secrets.key"
+
 // http server timeouts
 const httpServerReadTimeout = 5 * time.Second
 const httpServerWriteTimeout = 10 * time.Second
@@ -91,6 +94,10 @@ func (app *Application) GetDataStorageAppDataPath() string {
 	return dataStorageAppDataPath
 }
 
+func (app *Application) GetSecretsKeyPath() string {
+	return dataStorageSecretsKeyPath
+}
+
 // LockSQLForBackup locks sql storage from writes so that a copy can be read without
 // the risk of corruption. It returns a function to unlock the db after the backup
 // is completed.
@@ -161,6 +168,12 @@ func (app *Application) GetOrderStorage() orders.Storage {
 func (app *Application) GetDownloadStorage() download.Storage {
 	return app.storage
 }
+func (app *Application) GetAuthorizationsStorage() authorizations.Storage {
+	return app.storage
+}
+func (app *Application) GetChallengesStorage() challenges.Storage {
+	return app.storage
+}
 
 //
 
@@ -51,6 +51,12 @@ func (service *Service) createDataBackup(withOnDiskBackups bool) (zipFileBytes [
 			return nil
 		}
 
+		// never include the secrets master key, the encrypted secrets in the config
+		// should not be readable by anyone with just the backup
+		if path == service.cleanSecretsKeyPath {
+			return nil
+		}
+
 		// this is a file, zip it and a hash of it
 		f, err := os.Open(path)
 		if err != nil {
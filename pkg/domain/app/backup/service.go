@@ -17,6 +17,7 @@ var errServiceComponent = errors.New("necessary backup service component is miss
 // App interface is for connecting to the main app
 type App interface {
 	GetDataStorageRootPath() string
+	GetSecretsKeyPath() string
 	GetLogger() *zap.SugaredLogger
 	GetOutputter() *output.Service
 	LockSQLForBackup() (unlockFunc func(), err error)
@@ -28,6 +29,7 @@ type App interface {
 type Service struct {
 	cleanDataStorageRootPath   string
 	cleanDataStorageBackupPath string
+	cleanSecretsKeyPath        string
 	lockSQLForBackup           func() (unlockFunc func(), err error)
 	logger                     *zap.SugaredLogger
 	output                     *output.Service
@@ -40,6 +42,7 @@ func NewService(app App) (*Service, error) {
 
 	service.cleanDataStorageRootPath = filepath.Clean(app.GetDataStorageRootPath())
 	service.cleanDataStorageBackupPath = filepath.Clean(app.GetDataStorageRootPath() + "/" + dataStorageBackupDirName)
+	service.cleanSecretsKeyPath = filepath.Clean(app.GetSecretsKeyPath())
 
 	// logger
 	service.logger = app.GetLogger()
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"legocerthub-backend/pkg/domain/acme_servers"
 	"legocerthub-backend/pkg/output"
 	"legocerthub-backend/pkg/storage/sqlite"
 	"net/http"
@@ -16,6 +17,7 @@ type serverStatusResponse struct {
 		ConfigVersion int    `json:"config_version"`
 		DbUserVersion int    `json:"database_version"`
 	} `json:"server"`
+	AcmeServerNonces []acme_servers.ServerNonceStatsResponse `json:"acme_server_nonces"`
 }
 
 // statusHandler writes some basic info about the status of the Application
@@ -30,7 +32,15 @@ func (app *Application) statusHandler(w http.ResponseWriter, r *http.Request) *o
 	response.ServerStatus.ConfigVersion = *app.config.ConfigVersion
 	response.ServerStatus.DbUserVersion = sqlite.DbCurrentUserVersion
 
-	err := app.output.WriteJSON(w, response)
+	// nonce stats are informational, don't fail the status if they're unavailable
+	var err error
+	response.AcmeServerNonces, err = app.acmeServers.ListAllServersNonceStats()
+	if err != nil {
+		app.logger.Errorf("failed to get acme server nonce stats (%s)", err)
+		response.AcmeServerNonces = []acme_servers.ServerNonceStatsResponse{}
+	}
+
+	err = app.output.WriteJSON(w, response)
 	if err != nil {
 		app.logger.Errorf("failed to write json (%s)", err)
 		return output.ErrWriteJsonError
@@ -69,10 +69,16 @@ func (app *Application) makeRouterAndRoutes() {
 	// router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/providers/domains", app.challenges.Providers.GetAllDomains)
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/providers/services", app.challenges.Providers.GetAllProviders)
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/providers/services/:id", app.challenges.Providers.GetOneProvider)
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/providers/match", app.challenges.Providers.GetProvidersForIdentifier)
 
 	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/app/challenges/providers/services", app.challenges.Providers.CreateProvider)
 	router.handleAPIRouteSecure(http.MethodPut, apiUrlPath+"/v1/app/challenges/providers/services/:id", app.challenges.Providers.ModifyProvider)
 	router.handleAPIRouteSecure(http.MethodDelete, apiUrlPath+"/v1/app/challenges/providers/services/:id", app.challenges.Providers.DeleteProvider)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/app/challenges/providers/services/:id/test", app.challenges.TestProvider)
+
+	// challenges (history)
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/history", app.challenges.GetChallengeHistory)
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/app/challenges/providers/stats", app.challenges.GetProviderStats)
 
 	// acme_servers
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/acmeservers", app.acmeServers.GetAllServers)
@@ -98,6 +104,7 @@ func (app *Application) makeRouterAndRoutes() {
 	// acme_accounts
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/acmeaccounts", app.accounts.GetAllAccounts)
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/acmeaccounts/:id", app.accounts.GetOneAccount)
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/acmeaccounts/:id/authorizations", app.authorizations.GetAccountAuthorizations)
 
 	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/acmeaccounts", app.accounts.PostNewAccount)
 
@@ -107,6 +114,8 @@ func (app *Application) makeRouterAndRoutes() {
 
 	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/acmeaccounts/:id/register-account", app.accounts.NewAcmeAccount)
 	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/acmeaccounts/:id/deactivate", app.accounts.Deactivate)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/acmeaccounts/:id/pre-authorize", app.authorizations.PreAuthorize)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/acmeaccounts/:id/sync-orders", app.orders.SyncAccountOrders)
 
 	router.handleAPIRouteSecure(http.MethodDelete, apiUrlPath+"/v1/acmeaccounts/:id", app.accounts.DeleteAccount)
 
@@ -129,6 +138,18 @@ func (app *Application) makeRouterAndRoutes() {
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/orders/fulfilling/status", app.orders.GetFulfillWorkStatus)
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/orders/post-process/status", app.orders.GetPostProcessWorkStatus)
 
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/orders/fulfilling/jobs/:orderid", app.orders.GetFulfillJob)
+	router.handleAPIRouteSecure(http.MethodDelete, apiUrlPath+"/v1/orders/fulfilling/jobs/:orderid", app.orders.CancelFulfillJob)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/fulfilling/jobs/:orderid/promote", app.orders.PromoteFulfillJob)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/fulfilling/pause", app.orders.PauseFulfilling)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/fulfilling/resume", app.orders.ResumeFulfilling)
+
+	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/orders/post-process/jobs/:orderid", app.orders.GetPostProcessJob)
+	router.handleAPIRouteSecure(http.MethodDelete, apiUrlPath+"/v1/orders/post-process/jobs/:orderid", app.orders.CancelPostProcessJob)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/post-process/jobs/:orderid/promote", app.orders.PromotePostProcessJob)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/post-process/pause", app.orders.PausePostProcessing)
+	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/orders/post-process/resume", app.orders.ResumePostProcessing)
+
 	router.handleAPIRouteSecure(http.MethodGet, apiUrlPath+"/v1/certificates/:certid/orders", app.orders.GetCertOrders)
 	router.handleAPIRouteSecure(http.MethodPost, apiUrlPath+"/v1/certificates/:certid/orders", app.orders.NewOrder)
 
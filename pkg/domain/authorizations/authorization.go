@@ -0,0 +1,72 @@
+package authorizations
+
+// Authorization is a stored ACME authorization (pre-authorizations created via newAuthz
+// and authorizations LeGo solved a challenge for are stored). Stored authorizations are
+// informational; the ACME server is always asked for an authorization's current status.
+type Authorization struct {
+	ID              int
+	AccountID       int
+	Location        string
+	IdentifierType  string
+	IdentifierValue string
+	Status          string
+	Expires         *int
+	SolvedBy        *SolvedBy
+	CreatedAt       int
+	UpdatedAt       int
+}
+
+// SolvedBy records which challenge and provider solved an authorization
+type SolvedBy struct {
+	ChallengeType string `json:"challenge_type"`
+	ProviderID    int    `json:"provider_id"`
+	ProviderType  string `json:"provider_type"`
+}
+
+// authorizationResponse is a JSON response for an Authorization
+type authorizationResponse struct {
+	ID         int    `json:"id"`
+	AccountID  int    `json:"acme_account_id"`
+	Location   string `json:"location"`
+	Identifier struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifier"`
+	Status    string    `json:"status"`
+	Expires   *int      `json:"expires"`
+	SolvedBy  *SolvedBy `json:"solved_by"`
+	CreatedAt int       `json:"created_at"`
+	UpdatedAt int       `json:"updated_at"`
+}
+
+func (auth Authorization) response() authorizationResponse {
+	resp := authorizationResponse{
+		ID:        auth.ID,
+		AccountID: auth.AccountID,
+		Location:  auth.Location,
+		Status:    auth.Status,
+		Expires:   auth.Expires,
+		SolvedBy:  auth.SolvedBy,
+		CreatedAt: auth.CreatedAt,
+		UpdatedAt: auth.UpdatedAt,
+	}
+	resp.Identifier.Type = auth.IdentifierType
+	resp.Identifier.Value = auth.IdentifierValue
+
+	return resp
+}
+
+// AuthorizationPayload is used to save an ACME authorization to storage. If an
+// authorization with the same Location already exists, it is updated. SolvedBy is
+// optional and if nil, any existing SolvedBy is left unchanged.
+type AuthorizationPayload struct {
+	AccountID       int
+	Location        string
+	IdentifierType  string
+	IdentifierValue string
+	Status          string
+	Expires         *int
+	SolvedBy        *SolvedBy
+	CreatedAt       int
+	UpdatedAt       int
+}
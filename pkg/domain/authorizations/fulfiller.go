@@ -3,7 +3,9 @@ package authorizations
 import (
 	"errors"
 	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/challenges"
 	"sync"
+	"time"
 )
 
 var errAuthPending = errors.New("one or more auths are still in 'pending' status")
@@ -11,7 +13,7 @@ var errAuthPending = errors.New("one or more auths are still in 'pending' status
 // FulfillAuths attempts to validate each of the auth URLs in the slice of auth URLs. It returns 'valid' Status if all auths were
 // determined to be 'valid'. It returns 'invalid' if any of the auths were determined to be in any state other than valid or pending.
 // It returns an error if any of the auth Statuses could not be determined or if any are still in pending.
-func (service *Service) FulfillAuths(authUrls []string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
+func (service *Service) FulfillAuths(accountId int, authUrls []string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
 	// aysnc checking the authz for validity
 	var wg sync.WaitGroup
 	wgSize := len(authUrls)
@@ -25,7 +27,7 @@ func (service *Service) FulfillAuths(authUrls []string, key acme.AccountKey, acm
 	for i := range authUrls {
 		go func(authUrl string) {
 			defer wg.Done()
-			status, err = service.fulfillAuth(authUrl, key, acmeService)
+			status, err = service.fulfillAuth(accountId, authUrl, key, acmeService)
 			wgStatuses <- status
 			wgErrors <- err
 		}(authUrls[i])
@@ -58,9 +60,16 @@ func (service *Service) FulfillAuths(authUrls []string, key acme.AccountKey, acm
 	return "valid", nil
 }
 
+// FailoverAvailable returns true if a challenge for any of the identifiers failed validation
+// and that identifier has another provider to try. Since the failed authorization can't be
+// retried, a new order is needed to use the other provider.
+func (service *Service) FailoverAvailable(identifiers acme.IdentifierSlice) bool {
+	return service.challenges.TakeFailover(identifiers)
+}
+
 // fulfillAuth attempts to validate an auth URL using the specified method. It will either respond from cache
 // or call an authWorker.  An error is returned if the auth status could not be determined.
-func (service *Service) fulfillAuth(authUrl string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
+func (service *Service) fulfillAuth(accountId int, authUrl string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
 	// add authUrl to working and call a worker, if the authUrl is already being worked,
 	// block and return the cached result. If the cached result is an error, try to work
 	// the auth again.
@@ -103,7 +112,7 @@ func (service *Service) fulfillAuth(authUrl string, key acme.AccountKey, acmeSer
 	}()
 
 	// work the auth
-	status, err = service.authWorker(authUrl, key, acmeService)
+	status, err = service.authWorker(accountId, authUrl, key, acmeService)
 
 	// cache result &
 	// error check
@@ -118,8 +127,11 @@ func (service *Service) fulfillAuth(authUrl string, key acme.AccountKey, acmeSer
 
 // authWorker returns the Status of an authorization URL. If the authorization Status is currently 'pending', authWorker attempts to
 // move the authorization to the 'valid' Status.  An error is returned if the Status can't be determined.
-func (service *Service) authWorker(authUrl string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
-	// PaG the authorization
+// Authorizations that a challenge is submitted for are saved to storage along with which provider
+// solved them.
+func (service *Service) authWorker(accountId int, authUrl string, key acme.AccountKey, acmeService *acme.Service) (status string, err error) {
+	// PaG the authorization (always ask the ACME server, a stored authorization may
+	// have since been deactivated or revoked)
 	auth, err := acmeService.GetAuth(authUrl, key)
 	if err != nil {
 		return "", err
@@ -129,7 +141,17 @@ func (service *Service) authWorker(authUrl string, key acme.AccountKey, acmeServ
 	switch auth.Status {
 	// try to solve a challenge if auth is pending
 	case "pending":
-		auth.Status, err = service.challenges.Solve(auth.Identifier, auth.Challenges, key, acmeService)
+		var solvedBy *challenges.SolvedBy
+		auth.Status, solvedBy, err = service.challenges.Solve(auth.Identifier, auth.Wildcard, auth.Challenges, key, acmeService)
+
+		// record which provider solved it (failure is not fatal)
+		if solvedBy != nil {
+			saveErr := service.saveSolvedAuthorization(accountId, authUrl, auth, solvedBy)
+			if saveErr != nil {
+				service.logger.Errorf("failed to save authorization %s (%s)", authUrl, saveErr)
+			}
+		}
+
 		// return error if couldn't solve
 		if err != nil {
 			return "", err
@@ -148,3 +170,36 @@ func (service *Service) authWorker(authUrl string, key acme.AccountKey, acmeServ
 
 	return auth.Status, nil
 }
+
+// saveSolvedAuthorization saves the authorization (with its status after solving) and
+// which provider solved it. The saved authorization is only a record of how it was
+// solved; it is never used in place of the status the ACME server reports.
+func (service *Service) saveSolvedAuthorization(accountId int, authUrl string, auth acme.Authorization, solvedBy *challenges.SolvedBy) error {
+	var expires *int
+	if unixExpires := auth.Expires.ToUnixTime(); unixExpires != 0 {
+		expires = &unixExpires
+	}
+
+	// status isn't known if solving errored after submitting
+	status := auth.Status
+	if status == "" {
+		status = "processing"
+	}
+
+	now := int(time.Now().Unix())
+	return service.storage.PutAuthorization(AuthorizationPayload{
+		AccountID:       accountId,
+		Location:        authUrl,
+		IdentifierType:  string(auth.Identifier.Type),
+		IdentifierValue: auth.Identifier.Value,
+		Status:          status,
+		Expires:         expires,
+		SolvedBy: &SolvedBy{
+			ChallengeType: string(solvedBy.ChallengeType),
+			ProviderID:    solvedBy.ProviderID,
+			ProviderType:  solvedBy.ProviderType,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
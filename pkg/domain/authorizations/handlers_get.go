@@ -0,0 +1,60 @@
+package authorizations
+
+import (
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// authorizationsResponse provides the json response struct
+// to answer a query for an account's stored authorizations
+type authorizationsResponse struct {
+	output.JsonResponse
+	Authorizations []authorizationResponse `json:"authorizations"`
+}
+
+// GetAccountAuthorizations returns all of the stored authorizations for the
+// specified acme account
+func (service *Service) GetAccountAuthorizations(w http.ResponseWriter, r *http.Request) *output.Error {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	accountId, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validate account exists
+	_, outErr := service.accounts.GetAccount(accountId)
+	if outErr != nil {
+		return outErr
+	}
+
+	// get from storage
+	auths, err := service.storage.GetAccountAuthorizations(accountId)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	authsResponse := []authorizationResponse{}
+	for i := range auths {
+		authsResponse = append(authsResponse, auths[i].response())
+	}
+
+	// write response
+	response := &authorizationsResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.Authorizations = authsResponse
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
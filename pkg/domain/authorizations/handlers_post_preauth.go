@@ -0,0 +1,199 @@
+package authorizations
+
+import (
+	"encoding/json"
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/validation"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var (
+	errAccountNotUsable     = errors.New("acme account is not usable (must be registered and valid)")
+	errPreAuthNoIdentifiers = errors.New("no identifiers specified")
+	errPreAuthIdentifierBad = errors.New("identifier is not valid (must be a non-wildcard domain or an ip address)")
+	errPreAuthUnsupported   = errors.New("acme server does not support pre-authorization")
+)
+
+// preAuthorizeWriteTimeout is how long the server may take to respond to a
+// pre-authorization request; solving challenges (e.g. waiting for dns propagation)
+// takes much longer than the server's usual write timeout
+const preAuthorizeWriteTimeout = 30 * time.Minute
+
+// preAuthorizePayload is the payload to pre-authorize identifiers for an account
+type preAuthorizePayload struct {
+	Identifiers []string `json:"identifiers"`
+}
+
+// preAuthorizeResult is the outcome of pre-authorizing one identifier
+type preAuthorizeResult struct {
+	Identifier string `json:"identifier"`
+	Location   string `json:"location,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Expires    *int   `json:"expires,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// preAuthorizeResponse is the response to a pre-authorization request
+type preAuthorizeResponse struct {
+	output.JsonResponse
+	PreAuthorizations []preAuthorizeResult `json:"pre_authorizations"`
+}
+
+// PreAuthorize creates a new authorization for each of the specified identifiers using
+// the ACME server's newAuthz endpoint (see: rfc8555 s 7.4.1), solves them using the
+// configured challenge providers, and stores the results. Orders placed before the
+// authorizations expire will not need to solve challenges for these identifiers.
+func (service *Service) PreAuthorize(w http.ResponseWriter, r *http.Request) *output.Error {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	accountId, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// decode body into payload
+	var payload preAuthorizePayload
+	err = json.NewDecoder(r.Body).Decode(&payload)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	// account
+	account, outErr := service.accounts.GetAccount(accountId)
+	if outErr != nil {
+		return outErr
+	}
+	if !service.accounts.AccountUsable(accountId) {
+		service.logger.Debug(errAccountNotUsable)
+		return output.ErrValidationFailed
+	}
+	// identifiers (wildcards can't be pre-authorized, see: rfc8555 s 7.4.1)
+	if len(payload.Identifiers) == 0 {
+		service.logger.Debug(errPreAuthNoIdentifiers)
+		return output.ErrValidationFailed
+	}
+	for _, identifier := range payload.Identifiers {
+		if !validation.DomainValid(identifier, false) && !validation.IPAddressValid(identifier) {
+			service.logger.Debug(errPreAuthIdentifierBad)
+			return output.ErrValidationFailed
+		}
+	}
+	// acme server
+	acmeService, err := service.acmeServerService.AcmeService(account.AcmeServer.ID)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+	if !acmeService.SupportsPreAuthorization() {
+		service.logger.Debug(errPreAuthUnsupported)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// get account key
+	key, err := account.AcmeAccountKey()
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrInternal
+	}
+
+	// solving runs longer than the server's usual write timeout
+	err = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(preAuthorizeWriteTimeout))
+	if err != nil {
+		service.logger.Errorf("failed to extend write deadline for pre-authorization (%s)", err)
+	}
+
+	// pre-authorize each identifier concurrently
+	results := make([]preAuthorizeResult, len(payload.Identifiers))
+	var wg sync.WaitGroup
+	wg.Add(len(payload.Identifiers))
+	for i := range payload.Identifiers {
+		go func(i int) {
+			defer wg.Done()
+			results[i] = service.preAuthorize(accountId, payload.Identifiers[i], key, acmeService)
+		}(i)
+	}
+	wg.Wait()
+
+	// write response
+	response := &preAuthorizeResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "pre-authorization complete"
+	response.PreAuthorizations = results
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
+
+// preAuthorize creates, fulfills, and stores a new authorization for one identifier
+func (service *Service) preAuthorize(accountId int, identifier string, key acme.AccountKey, acmeService *acme.Service) preAuthorizeResult {
+	result := preAuthorizeResult{
+		Identifier: identifier,
+	}
+
+	// new authorization
+	acmeAuth, err := acmeService.NewAuthz(acme.NewAuthzPayload{Identifier: acme.NewIdentifier(identifier)}, key)
+	if err != nil {
+		service.logger.Errorf("pre-authorization of %s failed (%s)", identifier, err)
+		result.Error = err.Error()
+		return result
+	}
+	result.Location = acmeAuth.Location
+
+	// solve
+	_, err = service.fulfillAuth(accountId, acmeAuth.Location, key, acmeService)
+	if err != nil {
+		service.logger.Errorf("pre-authorization of %s failed (%s)", identifier, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	// refresh for final status and expiration
+	acmeAuth, err = acmeService.GetAuth(result.Location, key)
+	if err != nil {
+		service.logger.Errorf("pre-authorization of %s failed to refresh (%s)", identifier, err)
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = acmeAuth.Status
+	if expires := acmeAuth.Expires.ToUnixTime(); expires != 0 {
+		result.Expires = &expires
+	}
+
+	// save
+	now := int(time.Now().Unix())
+	err = service.storage.PutAuthorization(AuthorizationPayload{
+		AccountID:       accountId,
+		Location:        result.Location,
+		IdentifierType:  string(acmeAuth.Identifier.Type),
+		IdentifierValue: acmeAuth.Identifier.Value,
+		Status:          acmeAuth.Status,
+		Expires:         result.Expires,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if err != nil {
+		service.logger.Errorf("failed to save pre-authorization of %s (%s)", identifier, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	service.logger.Infof("pre-authorization of %s complete with status %s", identifier, result.Status)
+
+	return result
+}
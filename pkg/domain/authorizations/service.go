@@ -4,7 +4,9 @@ import (
 	"errors"
 	"legocerthub-backend/pkg/challenges"
 	"legocerthub-backend/pkg/datatypes/safemap"
+	"legocerthub-backend/pkg/domain/acme_accounts"
 	"legocerthub-backend/pkg/domain/acme_servers"
+	"legocerthub-backend/pkg/output"
 
 	"go.uber.org/zap"
 )
@@ -14,14 +16,27 @@ var errServiceComponent = errors.New("necessary authorizations service component
 // App interface is for connecting to the main app
 type App interface {
 	GetLogger() *zap.SugaredLogger
+	GetOutputter() *output.Service
+	GetAuthorizationsStorage() Storage
 	GetChallengesService() *challenges.Service
 	GetAcmeServerService() *acme_servers.Service
+	GetAcctsService() *acme_accounts.Service
+}
+
+// Storage interface for storage functions
+type Storage interface {
+	GetAccountAuthorizations(accountId int) ([]Authorization, error)
+
+	PutAuthorization(payload AuthorizationPayload) error
 }
 
 // service struct
 type Service struct {
 	logger            *zap.SugaredLogger
+	output            *output.Service
+	storage           Storage
 	acmeServerService *acme_servers.Service
+	accounts          *acme_accounts.Service
 	challenges        *challenges.Service
 	authsWorking      *safemap.SafeMap[chan struct{}] // tracks auths currently being worked
 	cache             *cache                          // tracks results of auths after worked
@@ -37,12 +52,30 @@ func NewService(app App) (service *Service, err error) {
 		return nil, errServiceComponent
 	}
 
+	// output service
+	service.output = app.GetOutputter()
+	if service.output == nil {
+		return nil, errServiceComponent
+	}
+
+	// storage
+	service.storage = app.GetAuthorizationsStorage()
+	if service.storage == nil {
+		return nil, errServiceComponent
+	}
+
 	// acme services
 	service.acmeServerService = app.GetAcmeServerService()
 	if service.acmeServerService == nil {
 		return nil, errServiceComponent
 	}
 
+	// account services
+	service.accounts = app.GetAcctsService()
+	if service.accounts == nil {
+		return nil, errServiceComponent
+	}
+
 	// challenge solver
 	service.challenges = app.GetChallengesService()
 	if service.challenges == nil {
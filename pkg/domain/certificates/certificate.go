@@ -4,6 +4,7 @@ import (
 	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/domain/acme_accounts"
 	"legocerthub-backend/pkg/domain/private_keys"
+	"time"
 )
 
 // Certificate is a single certificate with all of its fields
@@ -29,6 +30,12 @@ type Certificate struct {
 	PostProcessingCommand      string
 	PostProcessingEnvironment  []string
 	PostProcessingClientKeyB64 string
+	PreferredIssuer            string
+	Profile                    string
+	StarLifetime               int
+	StarDuration               int
+	StarStartOffset            int
+	StarLifetimeAdjust         int
 }
 
 // certificateSummaryResponse is a JSON response containing only
@@ -102,6 +109,12 @@ type certificateDetailedResponse struct {
 	PostProcessingCommand      string              `json:"post_processing_command"`
 	PostProcessingEnvironment  []string            `json:"post_processing_environment"`
 	PostProcessingClientKeyB64 string              `json:"post_processing_client_key"`
+	PreferredIssuer            string              `json:"preferred_issuer"`
+	Profile                    string              `json:"profile"`
+	StarLifetime               int                 `json:"star_lifetime"`
+	StarDuration               int                 `json:"star_duration"`
+	StarStartOffset            int                 `json:"star_start_offset"`
+	StarLifetimeAdjust         int                 `json:"star_lifetime_adjust"`
 }
 
 func (cert Certificate) detailedResponse() certificateDetailedResponse {
@@ -127,6 +140,12 @@ func (cert Certificate) detailedResponse() certificateDetailedResponse {
 		PostProcessingCommand:      cert.PostProcessingCommand,
 		PostProcessingEnvironment:  cert.PostProcessingEnvironment,
 		PostProcessingClientKeyB64: cert.PostProcessingClientKeyB64,
+		PreferredIssuer:            cert.PreferredIssuer,
+		Profile:                    cert.Profile,
+		StarLifetime:               cert.StarLifetime,
+		StarDuration:               cert.StarDuration,
+		StarStartOffset:            cert.StarStartOffset,
+		StarLifetimeAdjust:         cert.StarLifetimeAdjust,
 	}
 }
 
@@ -135,17 +154,38 @@ func (cert *Certificate) NewOrderPayload() acme.NewOrderPayload {
 	var identifiers []acme.Identifier
 
 	// subject is always required and should be first
-	// dns is the only supported type and is hardcoded
-	identifiers = append(identifiers, acme.Identifier{Type: "dns", Value: cert.Subject})
+	// type is dns unless the name is an ip address
+	identifiers = append(identifiers, acme.NewIdentifier(cert.Subject))
 
 	// add alt names if they exist
 	if cert.SubjectAltNames != nil {
 		for _, name := range cert.SubjectAltNames {
-			identifiers = append(identifiers, acme.Identifier{Type: "dns", Value: name})
+			identifiers = append(identifiers, acme.NewIdentifier(name))
 		}
 	}
 
-	return acme.NewOrderPayload{
+	payload := acme.NewOrderPayload{
 		Identifiers: identifiers,
+		Profile:     cert.Profile,
 	}
+
+	// STAR (auto-renewal) order, starts when authorized (or after the start offset) and
+	// renews until duration has elapsed
+	if cert.StarLifetime > 0 {
+		now := time.Now()
+
+		var startDate time.Time
+		if cert.StarStartOffset > 0 {
+			startDate = now.Add(time.Duration(cert.StarStartOffset) * time.Second)
+		}
+
+		payload.AutoRenewal = acme.NewAutoRenewal(
+			startDate,
+			now.Add(time.Duration(cert.StarDuration)*time.Second),
+			time.Duration(cert.StarLifetime)*time.Second,
+			time.Duration(cert.StarLifetimeAdjust)*time.Second,
+		)
+	}
+
+	return payload
 }
@@ -5,6 +5,8 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"legocerthub-backend/pkg/domain/private_keys/key_crypto"
+	"net"
+	"net/netip"
 )
 
 // MakeCsrDer generates the CSR bytes for ACME to POST To a Finalize URL
@@ -35,9 +37,16 @@ func (cert *Certificate) MakeCsrDer() (csr []byte, err error) {
 		locality = append(locality, cert.City)
 	}
 
+	// common name is omitted for ip address subjects (CAs issuing ip certs generally do not
+	// allow an ip in the CN, the ip is still included as a SAN)
+	commonName := cert.Subject
+	if _, err := netip.ParseAddr(cert.Subject); err == nil {
+		commonName = ""
+	}
+
 	// create Subject
 	subj := pkix.Name{
-		CommonName:         cert.Subject,
+		CommonName:         commonName,
 		Organization:       org,
 		OrganizationalUnit: ou,
 		Country:            country,
@@ -54,12 +63,25 @@ func (cert *Certificate) MakeCsrDer() (csr []byte, err error) {
 		extraExts = append(extraExts, cert.CSRExtraExtensions[i].Extension)
 	}
 
+	// split names into dns names and ip addresses
+	dnsNames := []string{}
+	ipAddresses := []net.IP{}
+	for _, name := range append([]string{cert.Subject}, cert.SubjectAltNames...) {
+		ip, err := netip.ParseAddr(name)
+		if err == nil {
+			ipAddresses = append(ipAddresses, ip.AsSlice())
+		} else {
+			dnsNames = append(dnsNames, name)
+		}
+	}
+
 	// CSR template to create CSR from
 	template := x509.CertificateRequest{
 		SignatureAlgorithm: cert.CertificateKey.Algorithm.CsrSigningAlg(),
 		Subject:            subj,
-		DNSNames:           append([]string{cert.Subject}, cert.SubjectAltNames...),
-		// unused: EmailAddresses, IPAddresses, URIs, Attributes (deprecated)
+		DNSNames:           dnsNames,
+		IPAddresses:        ipAddresses,
+		// unused: EmailAddresses, URIs, Attributes (deprecated)
 		ExtraExtensions: extraExts,
 	}
 
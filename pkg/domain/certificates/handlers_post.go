@@ -32,6 +32,12 @@ type NewPayload struct {
 	CSRExtraExtensions        []CertExtensionJSON `json:"csr_extra_extensions"`
 	PostProcessingCommand     *string             `json:"post_processing_command"`
 	PostProcessingEnvironment []string            `json:"post_processing_environment"`
+	PreferredIssuer           *string             `json:"preferred_issuer"`
+	Profile                   *string             `json:"profile"`
+	StarLifetime              *int                `json:"star_lifetime"`
+	StarDuration              *int                `json:"star_duration"`
+	StarStartOffset           *int                `json:"star_start_offset"`
+	StarLifetimeAdjust        *int                `json:"star_lifetime_adjust"`
 	// for post processing client, user submits enable or not, if enable key is generated and stored
 	// bool is not stored anywhere (disabled == blank key value)
 	PostProcessingClientEnable *bool  `json:"post_processing_client_enable"`
@@ -159,6 +165,35 @@ func (service *Service) PostNewCert(w http.ResponseWriter, r *http.Request) *out
 	if payload.PostProcessingClientEnable == nil {
 		payload.PostProcessingClientEnable = new(bool)
 	}
+	// preferred issuer (blank is okay, means use the ACME server's default chain)
+	if payload.PreferredIssuer == nil {
+		payload.PreferredIssuer = new(string)
+	}
+	// profile (blank is okay, means use the ACME server's default profile)
+	if payload.Profile == nil {
+		payload.Profile = new(string)
+	} else if !service.accounts.ProfileValid(*payload.AcmeAccountID, *payload.Profile) {
+		service.logger.Debug(ErrProfileBad)
+		return output.ErrValidationFailed
+	}
+	// star (optional, 0 disables) must be supported by the account's acme server
+	if payload.StarLifetime == nil {
+		payload.StarLifetime = new(int)
+	}
+	if payload.StarDuration == nil {
+		payload.StarDuration = new(int)
+	}
+	if payload.StarStartOffset == nil {
+		payload.StarStartOffset = new(int)
+	}
+	if payload.StarLifetimeAdjust == nil {
+		payload.StarLifetimeAdjust = new(int)
+	}
+	if !service.accounts.AutoRenewalValid(*payload.AcmeAccountID, *payload.StarLifetime, *payload.StarDuration,
+		*payload.StarStartOffset, *payload.StarLifetimeAdjust) {
+		service.logger.Debug(ErrAutoRenewalBad)
+		return output.ErrValidationFailed
+	}
 	// end validation
 
 	// if new key was generated, save it to storage
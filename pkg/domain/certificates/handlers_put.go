@@ -26,6 +26,12 @@ type DetailsUpdatePayload struct {
 	CSRExtraExtensions        []CertExtensionJSON `json:"csr_extra_extensions"`
 	PostProcessingCommand     *string             `json:"post_processing_command"`
 	PostProcessingEnvironment []string            `json:"post_processing_environment"`
+	PreferredIssuer           *string             `json:"preferred_issuer"`
+	Profile                   *string             `json:"profile"`
+	StarLifetime              *int                `json:"star_lifetime"`
+	StarDuration              *int                `json:"star_duration"`
+	StarStartOffset           *int                `json:"star_start_offset"`
+	StarLifetimeAdjust        *int                `json:"star_lifetime_adjust"`
 	ApiKey                    *string             `json:"api_key"`
 	ApiKeyNew                 *string             `json:"api_key_new"`
 	ApiKeyViaUrl              *bool               `json:"api_key_via_url"`
@@ -108,6 +114,41 @@ func (service *Service) PutDetailsCert(w http.ResponseWriter, r *http.Request) *
 
 	// post processing command & env are optional but nothing to validate
 
+	// preferred issuer is optional but nothing to validate
+
+	// profile (optional) must be offered by the account's acme server
+	if payload.Profile != nil && !service.accounts.ProfileValid(cert.CertificateAccount.ID, *payload.Profile) {
+		service.logger.Debug(ErrProfileBad)
+		return output.ErrValidationFailed
+	}
+
+	// star (optional, 0 disables) must be supported by the account's acme server
+	if payload.StarLifetime != nil || payload.StarDuration != nil || payload.StarStartOffset != nil ||
+		payload.StarLifetimeAdjust != nil {
+		starLifetime := cert.StarLifetime
+		if payload.StarLifetime != nil {
+			starLifetime = *payload.StarLifetime
+		}
+		starDuration := cert.StarDuration
+		if payload.StarDuration != nil {
+			starDuration = *payload.StarDuration
+		}
+		starStartOffset := cert.StarStartOffset
+		if payload.StarStartOffset != nil {
+			starStartOffset = *payload.StarStartOffset
+		}
+		starLifetimeAdjust := cert.StarLifetimeAdjust
+		if payload.StarLifetimeAdjust != nil {
+			starLifetimeAdjust = *payload.StarLifetimeAdjust
+		}
+
+		if !service.accounts.AutoRenewalValid(cert.CertificateAccount.ID, starLifetime, starDuration,
+			starStartOffset, starLifetimeAdjust) {
+			service.logger.Debug(ErrAutoRenewalBad)
+			return output.ErrValidationFailed
+		}
+	}
+
 	// end validation
 
 	// add additional details to the payload before saving
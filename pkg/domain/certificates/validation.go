@@ -25,7 +25,13 @@ var (
 	ErrApiKeyNewBad = errors.New("api key (new) is not valid (must be at least 10 chars in length)")
 
 	// domain
-	ErrDomainBad = errors.New("domain or subject name not valid")
+	ErrDomainBad = errors.New("domain (or ip address) or subject name not valid")
+
+	// profile
+	ErrProfileBad = errors.New("profile is not offered by the acme server")
+
+	// star
+	ErrAutoRenewalBad = errors.New("star lifetime, duration, start offset and lifetime adjust are not valid or not supported by the acme server")
 )
 
 // GetCertificate returns the Certificate for the specified id.
@@ -106,10 +112,11 @@ func (service *Service) privateKeyIdValid(keyId int, certId *int) bool {
 }
 
 // subjectValid validates domain name and if it is a wildcard
-// domain name it also verifies the method is dns-01
+// domain name it also verifies the method is dns-01. IP addresses
+// are also valid (see: rfc8738).
 func subjectValid(domain string) bool {
-	// check domain is valid
-	return validation.DomainValid(domain, true)
+	// check domain (or ip) is valid
+	return validation.DomainValid(domain, true) || validation.IPAddressValid(domain)
 }
 
 // subjectAltsValid validates each domain contained in the slice
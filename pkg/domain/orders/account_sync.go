@@ -0,0 +1,312 @@
+package orders
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/domain/acme_accounts"
+	"legocerthub-backend/pkg/domain/certificates"
+	"legocerthub-backend/pkg/domain/private_keys"
+	"legocerthub-backend/pkg/pagination_sort"
+	"legocerthub-backend/pkg/storage"
+	"strings"
+)
+
+// results of syncing a single order
+const (
+	orderSyncImported = "imported"
+	orderSyncUpdated  = "updated"
+	orderSyncSkipped  = "skipped"
+	orderSyncFailed   = "failed"
+)
+
+var errSyncNoMatchingCert = errors.New("no certificate on the account has the same identifiers as the order")
+
+// syncedOrder is the outcome of syncing one order from the acme server's
+// orders list
+type syncedOrder struct {
+	Location              string `json:"location"`
+	Result                string `json:"result"`
+	Status                string `json:"status,omitempty"`
+	OrderId               *int   `json:"order_id"`
+	CertificateId         *int   `json:"certificate_id"`
+	CertificateDownloaded bool   `json:"certificate_downloaded"`
+	Error                 string `json:"error,omitempty"`
+}
+
+// accountOrdersSync summarizes the outcome of syncing an account's orders list
+type accountOrdersSync struct {
+	AccountId int           `json:"acme_account_id"`
+	Total     int           `json:"total"`
+	Imported  int           `json:"imported"`
+	Updated   int           `json:"updated"`
+	Skipped   int           `json:"skipped"`
+	Failed    int           `json:"failed"`
+	Orders    []syncedOrder `json:"orders"`
+}
+
+// add records the result of one order in the sync summary
+func (sync *accountOrdersSync) add(result syncedOrder) {
+	sync.Total++
+	switch result.Result {
+	case orderSyncImported:
+		sync.Imported++
+	case orderSyncUpdated:
+		sync.Updated++
+	case orderSyncSkipped:
+		sync.Skipped++
+	default:
+		sync.Failed++
+	}
+
+	sync.Orders = append(sync.Orders, result)
+}
+
+// syncAccountOrders fetches the specified account's orders list from its acme server and
+// fetches each of the listed orders. Orders that are not in storage are imported if one of
+// the account's certificates has matching identifiers, orders already in storage are updated.
+// Certificates of valid orders are downloaded if storage does not already have them.
+// An error is only returned if the sync could not be done at all (e.g. the orders list
+// could not be fetched), errors with individual orders are recorded in the summary.
+func (service *Service) syncAccountOrders(account acme_accounts.Account) (accountOrdersSync, error) {
+	sync := accountOrdersSync{
+		AccountId: account.ID,
+		Orders:    []syncedOrder{},
+	}
+
+	key, err := account.AcmeAccountKey()
+	if err != nil {
+		return accountOrdersSync{}, err
+	}
+
+	acmeService, err := service.acmeServerService.AcmeService(account.AcmeServer.ID)
+	if err != nil {
+		return accountOrdersSync{}, err
+	}
+
+	// get the list of orders
+	orderUrls, err := acmeService.GetAccountOrders(key)
+	if err != nil {
+		return accountOrdersSync{}, err
+	}
+
+	// certificates that belong to the account (to match new orders to)
+	allCerts, _, err := service.storage.GetAllCerts(pagination_sort.QueryAll)
+	if err != nil {
+		return accountOrdersSync{}, err
+	}
+	accountCerts := []certificates.Certificate{}
+	for i := range allCerts {
+		if allCerts[i].CertificateAccount.ID == account.ID {
+			accountCerts = append(accountCerts, allCerts[i])
+		}
+	}
+
+	for i, orderUrl := range orderUrls {
+		result := service.syncOneOrder(acmeService, key, orderUrl, accountCerts)
+		sync.add(result)
+
+		// stop if rate limited, the rest would fail too
+		if blockedUntil := acmeService.BlockedUntil(key); !blockedUntil.IsZero() {
+			service.logger.Warnf("acme account %d: orders sync stopped after %d of %d orders, rate limited until %s", account.ID, i+1, len(orderUrls), blockedUntil)
+			break
+		}
+	}
+
+	service.logger.Infof("acme account %d: orders sync complete (total: %d, imported: %d, updated: %d, skipped: %d, failed: %d)",
+		account.ID, sync.Total, sync.Imported, sync.Updated, sync.Skipped, sync.Failed)
+
+	return sync, nil
+}
+
+// syncOneOrder fetches the order at orderUrl and imports or updates it in storage, along
+// with its certificate if the order is valid
+func (service *Service) syncOneOrder(acmeService *acme.Service, key acme.AccountKey, orderUrl string, accountCerts []certificates.Certificate) syncedOrder {
+	result := syncedOrder{
+		Location: orderUrl,
+		Result:   orderSyncFailed,
+	}
+
+	acmeOrder, err := acmeService.GetOrder(orderUrl, key)
+	if err != nil {
+		service.logger.Errorf("orders sync: failed to fetch order %s (%s)", orderUrl, err)
+		result.Error = err.Error()
+		return result
+	}
+	// POST-as-GET responses don't include the order location
+	acmeOrder.Location = orderUrl
+	result.Status = acmeOrder.Status
+
+	// existing or new order?
+	orderId, err := service.storage.GetOrderIdByLocation(orderUrl)
+	if errors.Is(err, storage.ErrNoRecord) {
+		cert, found := matchOrderCertificate(accountCerts, acmeOrder.Identifiers)
+		if !found {
+			service.logger.Infof("orders sync: skipping order %s (%s)", orderUrl, errSyncNoMatchingCert)
+			result.Result = orderSyncSkipped
+			result.Error = errSyncNoMatchingCert.Error()
+			return result
+		}
+
+		orderId, err = service.storage.PostNewOrder(makeNewOrderAcmePayload(cert, acmeOrder))
+		if err != nil && !errors.Is(err, ErrOrderExists) {
+			service.logger.Errorf("orders sync: failed to save order %s (%s)", orderUrl, err)
+			result.Error = err.Error()
+			return result
+		}
+
+		service.logger.Infof("orders sync: imported order %s for certificate %d", orderUrl, cert.ID)
+		result.Result = orderSyncImported
+	} else if err != nil {
+		service.logger.Errorf("orders sync: failed to look up order %s (%s)", orderUrl, err)
+		result.Error = err.Error()
+		return result
+	} else {
+		result.Result = orderSyncUpdated
+	}
+	result.OrderId = &orderId
+
+	// update acme fields (also stores the certificate url)
+	err = service.storage.PutOrderAcme(makeUpdateOrderAcmePayload(orderId, acmeOrder))
+	if err != nil {
+		service.logger.Errorf("orders sync: failed to update order %d (%s)", orderId, err)
+		result.Result = orderSyncFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	order, err := service.storage.GetOneOrder(orderId)
+	if err != nil {
+		service.logger.Errorf("orders sync: failed to get order %d (%s)", orderId, err)
+		result.Result = orderSyncFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.CertificateId = &order.Certificate.ID
+
+	// download the certificate if valid and not already stored
+	certificateUrl := currentCertificateUrl(acmeOrder)
+	if acmeOrder.Status == "valid" && certificateUrl != nil && order.Pem == nil {
+		err = service.syncOrderCertificate(acmeService, key, order, *certificateUrl)
+		if err != nil {
+			service.logger.Errorf("orders sync: failed to download certificate for order %d (%s)", orderId, err)
+			result.Result = orderSyncFailed
+			result.Error = err.Error()
+			return result
+		}
+		result.CertificateDownloaded = true
+	}
+
+	// update certificate timestamp
+	err = service.storage.UpdateCertUpdatedTime(order.Certificate.ID)
+	if err != nil {
+		service.logger.Error(err)
+		// no return
+	}
+
+	return result
+}
+
+// syncOrderCertificate downloads and saves the certificate of a valid order. If the
+// certificate's current private key is the key the certificate was issued for, it is
+// recorded as the order's finalized key.
+func (service *Service) syncOrderCertificate(acmeService *acme.Service, key acme.AccountKey, order Order, certificateUrl string) error {
+	certPemChains, err := acmeService.DownloadCertificateChains(certificateUrl, key)
+	if err != nil {
+		return err
+	}
+
+	certPemChain, err := service.savePemChains(order, certPemChains)
+	if err != nil {
+		return err
+	}
+
+	// the key used to finalize isn't known for orders placed elsewhere (or with
+	// a key that has since been changed), only record it if it matches
+	if order.FinalizedKey == nil && pemChainHasKey(certPemChain, order.Certificate.CertificateKey) {
+		err = service.storage.UpdateFinalizedKey(order.ID, order.Certificate.CertificateKey.ID)
+		if err != nil {
+			service.logger.Error(err)
+			// no return
+		}
+	}
+
+	// fetch renewal info for the cert (failure is not fatal)
+	order.Pem = &certPemChain
+	_, err = service.updateOrderRenewalInfo(order)
+	if err != nil {
+		service.logger.Errorf("orders sync: update renewal info error: %s", err)
+	}
+
+	return nil
+}
+
+// matchOrderCertificate returns the first certificate whose identifiers are exactly the
+// same as the order's identifiers
+func matchOrderCertificate(certs []certificates.Certificate, orderIds acme.IdentifierSlice) (certificates.Certificate, bool) {
+	orderValues := identifierValueSet(orderIds)
+
+	for i := range certs {
+		certValues := identifierValueSet(certs[i].NewOrderPayload().Identifiers)
+		if len(certValues) != len(orderValues) {
+			continue
+		}
+
+		match := true
+		for value := range certValues {
+			if _, ok := orderValues[value]; !ok {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return certs[i], true
+		}
+	}
+
+	return certificates.Certificate{}, false
+}
+
+// identifierValueSet returns the set of (lowercased) values of the identifiers
+func identifierValueSet(ids acme.IdentifierSlice) map[string]struct{} {
+	values := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		values[strings.ToLower(id.Value)] = struct{}{}
+	}
+
+	return values
+}
+
+// pemChainHasKey returns true if the leaf certificate of the pem chain has the public
+// key of the specified private key
+func pemChainHasKey(pemChain string, key private_keys.Key) bool {
+	block, _ := pem.Decode([]byte(pemChain))
+	if block == nil {
+		return false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	cryptoKey, err := key.CryptoPrivateKey()
+	if err != nil {
+		return false
+	}
+
+	signer, ok := cryptoKey.(crypto.Signer)
+	if !ok {
+		return false
+	}
+
+	pubKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return false
+	}
+
+	return pubKey.Equal(cert.PublicKey)
+}
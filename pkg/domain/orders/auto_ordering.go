@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"legocerthub-backend/pkg/randomness"
+	"slices"
 	"sync"
 	"time"
 )
 
 // startAutoOrderService starts a go routine that completes existing orders that are
 // not yet in a 'valid' or 'invalid' state and also places new orders forexpiring certs
+// and for certs whose ACME Renewal Information (ARI) window indicates renewal.
 // The service runs daily at the time specified in consts.
 func (service *Service) startAutoOrderService(cfg *Config, ctx context.Context, wg *sync.WaitGroup) {
 	// dont run if not enabled
@@ -74,8 +76,12 @@ func (service *Service) startAutoOrderService(cfg *Config, ctx context.Context,
 				service.logger.Errorf("error retying incomplete orders: %s", err)
 			}
 
+			// update renewal info; any cert whose selected renewal time is before the next run
+			// should be renewed now
+			renewalInfoCertIds := service.updateRenewalInfo(time.Now().Add(24 * time.Hour))
+
 			// order expiring certificates
-			service.orderExpiringCerts(remainingDaysThreshold)
+			service.orderExpiringCerts(remainingDaysThreshold, renewalInfoCertIds)
 		}
 	}()
 }
@@ -105,16 +111,34 @@ func (service *Service) retryIncompleteOrders() (err error) {
 }
 
 // orderExpiringCerts automatically orders any certficates that are valid but have a valid_to
-// timestamp within the specified threshold
-func (service *Service) orderExpiringCerts(remainingDaysThreshold time.Duration) {
+// timestamp within the specified threshold, as well as any additionally specified cert ids
+// (e.g. certs that should be renewed based on ACME Renewal Information)
+func (service *Service) orderExpiringCerts(remainingDaysThreshold time.Duration, additionalCertIds []int) {
 	service.logger.Info("adding expiring certificates to order queue")
 
 	// get slice of all expiring certificate ids
 	expiringCertIds, err := service.storage.GetExpiringCertIds(remainingDaysThreshold)
 	if err != nil {
-		service.logger.Errorf("error ordering expiring certs: %s", err)
-		return
+		// log error but still order any additional certs
+		service.logger.Errorf("error fetching expiring certs: %s", err)
+	}
+
+	// add additional ids (avoid duplicates)
+	for _, addlCertId := range additionalCertIds {
+		if !slices.Contains(expiringCertIds, addlCertId) {
+			expiringCertIds = append(expiringCertIds, addlCertId)
+		}
+	}
+
+	// don't reorder certs with a STAR order that will still be active at the next run,
+	// the acme server renews those
+	starCertIds, err := service.starCertIds(time.Now().Add(24 * time.Hour))
+	if err != nil {
+		service.logger.Errorf("error fetching star certs: %s", err)
 	}
+	expiringCertIds = slices.DeleteFunc(expiringCertIds, func(certId int) bool {
+		return slices.Contains(starCertIds, certId)
+	})
 
 	// address each expiring cert
 	for _, certId := range expiringCertIds {
@@ -2,6 +2,7 @@ package orders
 
 import (
 	"fmt"
+	"legocerthub-backend/pkg/acme"
 	"time"
 )
 
@@ -13,6 +14,13 @@ type orderFulfillJob struct {
 	addedToQueue time.Time
 	highPriority bool
 	orderID      int
+
+	// interruptedCount is how many times the job was interrupted by a crash (see
+	// maxJobInterruptions)
+	interruptedCount int
+
+	// deferredUntil is set if the job needs to run again later (e.g. rate limited)
+	deferredUntil time.Time
 }
 
 // makeFulfillingJob makes an orderFulfillJob
@@ -24,7 +32,7 @@ func (service *Service) makeFulfillingJob(orderID int, highPriority bool) (*orde
 	}
 
 	// cant fulfill if already in a final state
-	if order.Status == "valid" || order.Status == "invalid" {
+	if order.Status == "valid" || order.Status == "invalid" || order.Status == "canceled" {
 		return nil, fmt.Errorf("order fulfilling: failed to make fulfill job for order id %d (already in final state %s)", orderID, order.Status)
 	}
 
@@ -53,3 +61,56 @@ func (j *orderFulfillJob) Equal(j2 *orderFulfillJob) bool {
 func (j *orderFulfillJob) IsHighPriority() bool {
 	return j.highPriority
 }
+
+// SetHighPriority implements Job interface func to promote the job
+func (j *orderFulfillJob) SetHighPriority() {
+	j.highPriority = true
+}
+
+// QueuedAt implements Job interface func that returns when the job was queued
+func (j *orderFulfillJob) QueuedAt() time.Time {
+	return j.addedToQueue
+}
+
+// deferIfRateLimited queues the job to run again after the rate limit expires if err
+// is an ACME rateLimited error. It returns true if the job was deferred.
+func (j *orderFulfillJob) deferIfRateLimited(err error) bool {
+	retryAfter, limited := acme.RateLimitedUntil(err)
+	if !limited {
+		return false
+	}
+
+	j.deferUntil(retryAfter)
+	return true
+}
+
+// DeferredUntil implements DeferrableJob interface func that returns when the job should
+// run again (zero if it should not)
+func (j *orderFulfillJob) DeferredUntil() time.Time {
+	return j.deferredUntil
+}
+
+// deferUntil sets the job to be queued again, once it is done, to run once until has
+// passed. The saved job is kept so a restart doesn't lose it.
+func (j *orderFulfillJob) deferUntil(until time.Time) {
+	j.service.logger.Infof("order fulfilling: order id %d deferred until %s (acme rate limit)", j.orderID, until)
+
+	j.deferredUntil = until
+	j.service.persistQueuedJob(j.queuedJob())
+}
+
+// queuedJob returns the job as it is saved in storage
+func (j *orderFulfillJob) queuedJob() QueuedJob {
+	job := QueuedJob{
+		Queue:            jobQueueFulfill,
+		OrderID:          j.orderID,
+		HighPriority:     j.highPriority,
+		InterruptedCount: j.interruptedCount,
+		QueuedAt:         int(j.addedToQueue.Unix()),
+	}
+	if !j.deferredUntil.IsZero() {
+		job.NotBefore = int(j.deferredUntil.Unix())
+	}
+
+	return job
+}
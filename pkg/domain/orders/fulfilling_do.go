@@ -13,6 +13,11 @@ func (j *orderFulfillJob) Do(workerID int) {
 	// log end of Do (regardless of outcome)
 	defer j.service.logger.Infof("order fulfilling worker %d: order %d done", workerID, j.orderID)
 
+	// track running in storage, in case of restart
+	j.deferredUntil = time.Time{}
+	j.service.jobStarted(jobQueueFulfill, j.orderID)
+	defer func() { j.service.jobFinished(j.queuedJob(), !j.deferredUntil.IsZero()) }()
+
 	// get the relevant order from db
 	order, err := j.service.storage.GetOneOrder(j.orderID)
 	if err != nil {
@@ -55,6 +60,12 @@ func (j *orderFulfillJob) Do(workerID int) {
 		return // done, failed
 	}
 
+	// if the account is rate limited, try again once the limit expires
+	if blockedUntil := acmeService.BlockedUntil(key); !blockedUntil.IsZero() {
+		j.deferUntil(blockedUntil)
+		return // done, deferred
+	}
+
 	// exponential backoff for retrying while 'processing'
 	bo := randomness.BackoffACME(j.service.shutdownContext)
 
@@ -80,6 +91,10 @@ fulfillLoop:
 				return // done, permanent status
 			}
 
+			if j.deferIfRateLimited(err) {
+				return // done, deferred
+			}
+
 			j.service.logger.Errorf("order fulfilling worker %d: get order error: %w", workerID, err)
 			return // done, failed
 		}
@@ -96,8 +111,12 @@ fulfillLoop:
 
 		case "pending": // needs to be authed
 			var authStatus string
-			authStatus, err = j.service.authorizations.FulfillAuths(acmeOrder.Authorizations, key, acmeService)
+			authStatus, err = j.service.authorizations.FulfillAuths(order.Certificate.CertificateAccount.ID, acmeOrder.Authorizations, key, acmeService)
 			if err != nil {
+				if j.deferIfRateLimited(err) {
+					return // done, deferred
+				}
+
 				j.service.logger.Errorf("order fulfilling worker %d: fulfill auths error: %w", workerID, err)
 				return // done, failed
 			}
@@ -122,6 +141,10 @@ fulfillLoop:
 			// finalize the order
 			_, err = acmeService.FinalizeOrder(acmeOrder.Finalize, csr, key)
 			if err != nil {
+				if j.deferIfRateLimited(err) {
+					return // done, deferred
+				}
+
 				j.service.logger.Errorf("order fulfilling worker %d: finalize order error: %w", workerID, err)
 				return // done, failed
 			}
@@ -134,25 +157,39 @@ fulfillLoop:
 		case "valid": // can be downloaded
 			// download cert pem
 
+			// STAR orders provide a star-certificate url instead
+			certificateUrl := currentCertificateUrl(acmeOrder)
+
 			// nil check (make sure there is a cert URL)
-			if acmeOrder.Certificate == nil {
+			if certificateUrl == nil {
 				// if cert url is missing (nil), loop again (which will refresh order info)
 				continue
 			}
 
-			certPemChain, err := acmeService.DownloadCertificate(*acmeOrder.Certificate, key)
+			certPemChains, err := acmeService.DownloadCertificateChains(*certificateUrl, key)
 			if err != nil {
+				if j.deferIfRateLimited(err) {
+					return // done, deferred
+				}
+
 				j.service.logger.Errorf("order fulfilling worker %d: download cert error: %w", workerID, err)
 				return // done, failed
 			}
 
-			// process pem and save to storage
-			err = j.savePemChain(order.ID, certPemChain)
+			// select preferred chain, process pem and save to storage
+			certPemChain, err := j.service.savePemChains(order, certPemChains)
 			if err != nil {
 				j.service.logger.Errorf("order fulfilling worker %d: save pem error: %w", workerID, err)
 				return // done, failed
 			}
 
+			// fetch renewal info for the new cert (failure is not fatal)
+			order.Pem = &certPemChain
+			_, err = j.service.updateOrderRenewalInfo(order)
+			if err != nil {
+				j.service.logger.Errorf("order fulfilling worker %d: update renewal info error: %s", workerID, err)
+			}
+
 			// done
 			break fulfillLoop
 
@@ -176,7 +213,19 @@ fulfillLoop:
 			}
 
 		case "invalid": // break, irrecoverable
-			j.service.logger.Infof("order fulfilling worker %d: order status invalid; acme error: %w", workerID, acmeOrder.Error)
+			j.service.logger.Infof("order fulfilling worker %d: order status invalid; acme error: %s", workerID, acmeOrder.Error)
+			// log any identifier specific problems individually
+			if acmeOrder.Error != nil {
+				for _, sp := range acmeOrder.Error.Subproblems {
+					if sp.Identifier != nil {
+						j.service.logger.Infof("order fulfilling worker %d: order identifier %s error: %s (%s)", workerID, sp.Identifier.Value, sp.Type, sp.Detail)
+					}
+				}
+			}
+			break fulfillLoop
+
+		case "canceled": // break, STAR order canceled (see: rfc8739 s 3.1.2)
+			j.service.logger.Infof("order fulfilling worker %d: star order canceled", workerID)
 			break fulfillLoop
 
 		// Note: there is no 'expired' Status case. If the order expires it simply moves to 'invalid'.
@@ -215,6 +264,16 @@ fulfillLoop:
 		}
 	}
 
+	// if a challenge failed validation and another provider can be tried, place a new
+	// order (the failed provider is tried last when solving it)
+	if acmeOrder.Status == "invalid" && j.service.authorizations.FailoverAvailable(acmeOrder.Identifiers) {
+		j.service.logger.Infof("order fulfilling worker %d: order id %d invalid, placing a new order to try the next challenge provider", workerID, order.ID)
+		_, outErr := j.service.placeNewOrderAndFulfill(order.Certificate.ID, j.IsHighPriority())
+		if outErr != nil {
+			j.service.logger.Errorf("order fulfilling worker %d: failed to place new order for provider failover (%s)", workerID, outErr)
+		}
+	}
+
 	// log error if loop exhausted somehow
 	if time.Since(startTime) >= timeoutLength {
 		j.service.logger.Errorf("order fulfilling worker %d: order id %d exhausted retry loop time and terminated with status %s (certificate name: %s, subject: %s)", workerID, order.ID, acmeOrder.Status, order.Certificate.Name, order.Certificate.Subject)
@@ -3,6 +3,7 @@ package orders
 import (
 	"crypto/x509"
 	"encoding/pem"
+	"strings"
 )
 
 // this relates to the order's issued certificate, not to be conflated with the 'certificates'
@@ -10,34 +11,110 @@ import (
 
 // CertPayload is the data to store for an issued certificate
 type CertPayload struct {
-	Pem       string
-	ValidFrom int
-	ValidTo   int
+	Pem           string
+	AlternatePems []string
+	ValidFrom     int
+	ValidTo       int
 }
 
-// savePemChain calls a func to determine the valid from and to dates for the issued pem chain
-// and then saves the pem chain and valid dates to storage
-func (j *orderFulfillJob) savePemChain(orderId int, pemChain string) (err error) {
+// savePemChains selects which of the issued pem chains to use based on the certificate's
+// preferred issuer, calls a func to determine the valid from and to dates and then saves
+// the selected pem chain, the alternate pem chains, and valid dates to storage. The
+// selected pem chain is returned.
+func (service *Service) savePemChains(order Order, pemChains []string) (pemChain string, err error) {
+	// select chain
+	pemChain, alternatePems, found := selectPemChain(pemChains, order.Certificate.PreferredIssuer)
+	if !found {
+		service.logger.Warnf("order %d (certificate name: %s): no chain matches preferred issuer '%s', using default chain",
+			order.ID, order.Certificate.Name, order.Certificate.PreferredIssuer)
+	}
+
 	// calculate dates
 	validFrom, validTo, err := validDates(pemChain)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// payload to save
 	payload := CertPayload{
-		Pem:       pemChain,
-		ValidFrom: validFrom,
-		ValidTo:   validTo,
+		Pem:           pemChain,
+		AlternatePems: alternatePems,
+		ValidFrom:     validFrom,
+		ValidTo:       validTo,
 	}
 
 	// save to storage
-	err = j.service.storage.UpdateOrderCert(orderId, payload)
+	err = service.storage.UpdateOrderCert(order.ID, payload)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return pemChain, nil
+}
+
+// selectPemChain returns the first chain that contains a root or intermediate certificate
+// whose common name matches preferredIssuer, along with all of the other chains. If
+// preferredIssuer is blank or no chain matches, the first (default) chain is selected. found
+// is false only if a preferredIssuer was specified but not matched.
+func selectPemChain(pemChains []string, preferredIssuer string) (selected string, alternates []string, found bool) {
+	selectedIndex := 0
+	found = preferredIssuer == ""
+
+	if !found {
+		for i := range pemChains {
+			if chainHasIssuer(pemChains[i], preferredIssuer) {
+				selectedIndex = i
+				found = true
+				break
+			}
+		}
+	}
+
+	for i := range pemChains {
+		if i != selectedIndex {
+			alternates = append(alternates, pemChains[i])
+		}
+	}
+
+	return pemChains[selectedIndex], alternates, found
+}
+
+// chainHasIssuer returns true if any intermediate certificate in the pem chain, or the root
+// that signed the last certificate in the chain, has a common name that matches issuerName
+// (case insensitive)
+func chainHasIssuer(pemChain string, issuerName string) bool {
+	issuerName = strings.TrimSpace(issuerName)
+
+	var certs []*x509.Certificate
+	rest := []byte(pemChain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return false
+		}
+		certs = append(certs, cert)
+	}
+
+	// no intermediates
+	if len(certs) < 2 {
+		return false
+	}
+
+	// intermediates (skip leaf)
+	for _, cert := range certs[1:] {
+		if strings.EqualFold(cert.Subject.CommonName, issuerName) {
+			return true
+		}
 	}
 
-	return nil
+	// root (often not included in the chain)
+	return strings.EqualFold(certs[len(certs)-1].Issuer.CommonName, issuerName)
 }
 
 // validDates anlayzes the first cert in a pem chain and returns the valid from
@@ -1,13 +1,18 @@
 package orders
 
 import (
+	"legocerthub-backend/pkg/datatypes/job_manager"
 	"legocerthub-backend/pkg/output"
 	"net/http"
 )
 
 // orderJobResponse contains the json response struct for one order job
 type orderJobResponse struct {
-	AddedToQueue int                  `json:"added_to_queue"` // unix time job was requested
+	AddedToQueue int                  `json:"added_to_queue"`       // unix time job was requested
+	NotBefore    int                  `json:"not_before,omitempty"` // unix time a deferred job may start
+	StartedAt    int                  `json:"started_at,omitempty"`
+	FinishedAt   int                  `json:"finished_at,omitempty"`
+	DurationMs   int64                `json:"duration_ms"` // time running (0 if waiting)
 	HighPriority bool                 `json:"high_priority"`
 	Order        orderSummaryResponse `json:"order"`
 }
@@ -16,65 +21,85 @@ type orderJobResponse struct {
 // of the a work service
 type orderWorkStatusResponse struct {
 	output.JsonResponse
-	JobsWorking map[int]*orderJobResponse `json:"jobs_working"` // [workerid]
-	JobsWaiting []orderJobResponse        `json:"jobs_waiting"`
+	Paused       bool                      `json:"paused"`
+	JobsWorking  map[int]*orderJobResponse `json:"jobs_working"` // [workerid]
+	JobsWaiting  []orderJobResponse        `json:"jobs_waiting"`
+	JobsFinished []orderJobResponse        `json:"jobs_finished"` // most recent, newest first
 }
 
-// GetFulfillWorkStatus returns all fulfilling jobs with workers and waiting in queue
-func (service *Service) GetFulfillWorkStatus(w http.ResponseWriter, r *http.Request) *output.Error {
+// makeOrderJobResponse makes the response for a job using the job's order
+func makeOrderJobResponse[V orderJob[V]](service *Service, info job_manager.JobInfo[V], order Order) orderJobResponse {
+	resp := orderJobResponse{
+		AddedToQueue: int(info.QueuedAt.Unix()),
+		DurationMs:   info.Duration().Milliseconds(),
+		HighPriority: info.HighPriority,
+		Order:        order.summaryResponse(service),
+	}
+
+	if !info.NotBefore.IsZero() {
+		resp.NotBefore = int(info.NotBefore.Unix())
+	}
+	if !info.StartedAt.IsZero() {
+		resp.StartedAt = int(info.StartedAt.Unix())
+	}
+	if !info.FinishedAt.IsZero() {
+		resp.FinishedAt = int(info.FinishedAt.Unix())
+	}
+
+	return resp
+}
+
+// writeWorkStatus writes the status of all of the jobs in the manager
+func writeWorkStatus[V orderJob[V]](service *Service, w http.ResponseWriter, mgr *job_manager.Manager[V]) *output.Error {
 	// get jobs from manager
-	mgrJobs := service.orderFulfilling.AllCurrentJobs()
+	mgrJobs := mgr.AllCurrentJobs()
 
 	// get Order IDs for all jobs (to query db)
 	orderIDs := []int{}
 	for _, mgrWorkingJob := range mgrJobs.WorkingJobs {
 		// only add working if work isn't idle (i.e. it has a job)
 		if mgrWorkingJob != nil {
-			orderIDs = append(orderIDs, mgrWorkingJob.orderID)
+			orderIDs = append(orderIDs, mgrWorkingJob.Job.jobOrderID())
 		}
 	}
 	for _, mgrWaitingJob := range mgrJobs.WaitingJobs {
-		orderIDs = append(orderIDs, mgrWaitingJob.orderID)
+		orderIDs = append(orderIDs, mgrWaitingJob.Job.jobOrderID())
+	}
+	for _, mgrFinishedJob := range mgrJobs.FinishedJobs {
+		orderIDs = append(orderIDs, mgrFinishedJob.Job.jobOrderID())
 	}
 
 	// lookup all orders in db
 	orders, err := service.storage.GetOrders(orderIDs)
 	if err != nil {
-		service.logger.Errorf("orders: failed to convert fulfilling jobs to order objects (%w)", err)
+		service.logger.Errorf("orders: failed to convert jobs to order objects (%s)", err)
 		return output.ErrInternal
 	}
 
-	// build working part of response
-	workingResp := make(map[int]*orderJobResponse)
-	for workerID, mgrWorkingJob := range mgrJobs.WorkingJobs {
-		// find order that matches this workerID, and then make response
-		if mgrWorkingJob == nil {
-			workingResp[workerID] = nil
-		} else {
+	// makeResponses makes the responses for jobs whose order was found
+	makeResponses := func(infos []job_manager.JobInfo[V]) []orderJobResponse {
+		resps := []orderJobResponse{}
+		for _, info := range infos {
 			for _, order := range orders {
-				if mgrWorkingJob.orderID == order.ID {
-					workingResp[workerID] = &orderJobResponse{
-						AddedToQueue: int(mgrWorkingJob.addedToQueue.Unix()),
-						HighPriority: mgrWorkingJob.IsHighPriority(),
-						Order:        order.summaryResponse(service),
-					}
+				if info.Job.jobOrderID() == order.ID {
+					resps = append(resps, makeOrderJobResponse(service, info, order))
 					break
 				}
 			}
 		}
+		return resps
 	}
 
-	// build waiting part of response
-	waitingResp := []orderJobResponse{}
-	for _, mgrWaitingJob := range mgrJobs.WaitingJobs {
-		for _, order := range orders {
-			if mgrWaitingJob.orderID == order.ID {
-				waitingResp = append(waitingResp, orderJobResponse{
-					AddedToQueue: int(mgrWaitingJob.addedToQueue.Unix()),
-					HighPriority: mgrWaitingJob.IsHighPriority(),
-					Order:        order.summaryResponse(service),
-				})
-				break
+	// build working part of response
+	workingResp := make(map[int]*orderJobResponse)
+	for workerID, mgrWorkingJob := range mgrJobs.WorkingJobs {
+		// find order that matches this workerID, and then make response
+		if mgrWorkingJob == nil {
+			workingResp[workerID] = nil
+		} else {
+			resps := makeResponses([]job_manager.JobInfo[V]{*mgrWorkingJob})
+			if len(resps) > 0 {
+				workingResp[workerID] = &resps[0]
 			}
 		}
 	}
@@ -85,8 +110,10 @@ func (service *Service) GetFulfillWorkStatus(w http.ResponseWriter, r *http.Requ
 			StatusCode: http.StatusOK,
 			Message:    "ok",
 		},
-		JobsWorking: workingResp,
-		JobsWaiting: waitingResp,
+		Paused:       mgrJobs.Paused,
+		JobsWorking:  workingResp,
+		JobsWaiting:  makeResponses(mgrJobs.WaitingJobs),
+		JobsFinished: makeResponses(mgrJobs.FinishedJobs),
 	}
 
 	// serve final response
@@ -97,3 +124,9 @@ func (service *Service) GetFulfillWorkStatus(w http.ResponseWriter, r *http.Requ
 	}
 	return nil
 }
+
+// GetFulfillWorkStatus returns all fulfilling jobs with workers, waiting in queue, and
+// recently finished
+func (service *Service) GetFulfillWorkStatus(w http.ResponseWriter, r *http.Request) *output.Error {
+	return writeWorkStatus(service, w, service.orderFulfilling)
+}
@@ -0,0 +1,279 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"legocerthub-backend/pkg/datatypes/job_manager"
+	"legocerthub-backend/pkg/output"
+	"legocerthub-backend/pkg/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// orderJob is a job for a specific order (i.e. orderFulfillJob and postProcessJob)
+type orderJob[V any] interface {
+	job_manager.Job[V]
+	jobOrderID() int
+}
+
+// jobOrderID returns the ID of the order the job is for
+func (j *orderFulfillJob) jobOrderID() int {
+	return j.orderID
+}
+
+// jobOrderID returns the ID of the order the job is for
+func (j *postProcessJob) jobOrderID() int {
+	return j.orderID
+}
+
+// jobQueue is a job manager and what is needed to manage its jobs using the api
+type jobQueue[V orderJob[V]] struct {
+	name    string // as persisted in storage
+	manager *job_manager.Manager[V]
+	// probe returns a job that is Equal to the job for the order
+	probe func(orderID int) V
+}
+
+func (service *Service) fulfillingQueue() jobQueue[*orderFulfillJob] {
+	return jobQueue[*orderFulfillJob]{
+		name:    jobQueueFulfill,
+		manager: service.orderFulfilling,
+		probe: func(orderID int) *orderFulfillJob {
+			return &orderFulfillJob{orderID: orderID}
+		},
+	}
+}
+
+func (service *Service) postProcessingQueue() jobQueue[*postProcessJob] {
+	return jobQueue[*postProcessJob]{
+		name:    jobQueuePostProcess,
+		manager: service.postProcessing,
+		probe: func(orderID int) *postProcessJob {
+			// certificate id can't match any job
+			return &postProcessJob{orderID: orderID, certificateID: -1}
+		},
+	}
+}
+
+// jobOrderIdParam returns the orderid param of the request
+func (service *Service) jobOrderIdParam(r *http.Request) (int, *output.Error) {
+	orderIdParam := httprouter.ParamsFromContext(r.Context()).ByName("orderid")
+	orderId, err := strconv.Atoi(orderIdParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return -1, output.ErrValidationFailed
+	}
+
+	if !validation.IsIdExistingValidRange(orderId) {
+		service.logger.Debug(errOrderIdBad)
+		return -1, output.ErrValidationFailed
+	}
+
+	return orderId, nil
+}
+
+// jobManagerError converts a job manager error into an output error
+func (service *Service) jobManagerError(err error) *output.Error {
+	service.logger.Debug(err)
+
+	if errors.Is(err, job_manager.ErrJobNotFound) {
+		return output.ErrNotFound
+	} else if errors.Is(err, job_manager.ErrJobRunning) {
+		return output.ErrJobRunning
+	}
+
+	return output.ErrInternal
+}
+
+// writeMessage writes a simple ok response with the message
+func (service *Service) writeMessage(w http.ResponseWriter, message string) *output.Error {
+	response := &output.JsonResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = message
+
+	err := service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
+
+// job states
+const (
+	jobStateWaiting  = "waiting"
+	jobStateDeferred = "deferred"
+	jobStateWorking  = "working"
+	jobStateFinished = "finished"
+)
+
+// oneJobResponse is the response to a GET request for one job
+type oneJobResponse struct {
+	output.JsonResponse
+	State string           `json:"state"`
+	Job   orderJobResponse `json:"job"`
+}
+
+// getJob writes the state and timing of the queue's job for the orderid param. Jobs
+// that recently finished are also found.
+func getJob[V orderJob[V]](service *Service, q jobQueue[V], w http.ResponseWriter, r *http.Request) *output.Error {
+	orderId, outErr := service.jobOrderIdParam(r)
+	if outErr != nil {
+		return outErr
+	}
+
+	info := q.manager.FindJob(q.probe(orderId))
+	if info == nil {
+		service.logger.Debugf("%s job queue: no job for order id %d", q.name, orderId)
+		return output.ErrNotFound
+	}
+
+	order, err := service.storage.GetOneOrder(orderId)
+	if err != nil {
+		service.logger.Error(err)
+		return output.ErrStorageGeneric
+	}
+
+	// state
+	state := jobStateWorking
+	if info.StartedAt.IsZero() {
+		state = jobStateWaiting
+		if info.NotBefore.After(time.Now()) {
+			state = jobStateDeferred
+		}
+	} else if !info.FinishedAt.IsZero() {
+		state = jobStateFinished
+	}
+
+	// write response
+	response := &oneJobResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "ok"
+	response.State = state
+	response.Job = makeOrderJobResponse(service, *info, order)
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
+
+// cancelJob removes the queue's waiting job for the orderid param
+func cancelJob[V orderJob[V]](service *Service, q jobQueue[V], w http.ResponseWriter, r *http.Request) *output.Error {
+	orderId, outErr := service.jobOrderIdParam(r)
+	if outErr != nil {
+		return outErr
+	}
+
+	err := q.manager.CancelJob(q.probe(orderId))
+	if err != nil {
+		return service.jobManagerError(err)
+	}
+
+	// don't restore after restart
+	err = service.storage.DeleteQueuedJob(q.name, orderId)
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to remove saved job for order id %d (%s)", q.name, orderId, err)
+	}
+
+	service.logger.Infof("%s job queue: job for order id %d canceled", q.name, orderId)
+
+	return service.writeMessage(w, fmt.Sprintf("%s job for order id %d canceled", q.name, orderId))
+}
+
+// promoteJob makes the queue's waiting job for the orderid param high priority
+func promoteJob[V orderJob[V]](service *Service, q jobQueue[V], w http.ResponseWriter, r *http.Request) *output.Error {
+	orderId, outErr := service.jobOrderIdParam(r)
+	if outErr != nil {
+		return outErr
+	}
+
+	err := q.manager.PromoteJob(q.probe(orderId))
+	if err != nil {
+		return service.jobManagerError(err)
+	}
+
+	// keep priority after restart
+	err = service.storage.PutQueuedJobHighPriority(q.name, orderId)
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to save priority of job for order id %d (%s)", q.name, orderId, err)
+	}
+
+	service.logger.Infof("%s job queue: job for order id %d promoted to high priority", q.name, orderId)
+
+	return service.writeMessage(w, fmt.Sprintf("%s job for order id %d is high priority", q.name, orderId))
+}
+
+// pauseQueue stops the queue's workers from starting new jobs
+func pauseQueue[V orderJob[V]](service *Service, q jobQueue[V], w http.ResponseWriter) *output.Error {
+	q.manager.Pause()
+	service.logger.Infof("%s job queue: paused", q.name)
+
+	return service.writeMessage(w, fmt.Sprintf("%s job queue paused", q.name))
+}
+
+// resumeQueue allows the queue's workers to start jobs again
+func resumeQueue[V orderJob[V]](service *Service, q jobQueue[V], w http.ResponseWriter) *output.Error {
+	q.manager.Resume()
+	service.logger.Infof("%s job queue: resumed", q.name)
+
+	return service.writeMessage(w, fmt.Sprintf("%s job queue resumed", q.name))
+}
+
+// GetFulfillJob returns the state and timing of the fulfilling job for an order
+func (service *Service) GetFulfillJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return getJob(service, service.fulfillingQueue(), w, r)
+}
+
+// CancelFulfillJob removes an order's waiting fulfilling job from the queue
+func (service *Service) CancelFulfillJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return cancelJob(service, service.fulfillingQueue(), w, r)
+}
+
+// PromoteFulfillJob makes an order's waiting fulfilling job high priority
+func (service *Service) PromoteFulfillJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return promoteJob(service, service.fulfillingQueue(), w, r)
+}
+
+// PauseFulfilling stops fulfilling workers from starting new jobs (running jobs finish)
+func (service *Service) PauseFulfilling(w http.ResponseWriter, r *http.Request) *output.Error {
+	return pauseQueue(service, service.fulfillingQueue(), w)
+}
+
+// ResumeFulfilling allows fulfilling workers to start jobs again
+func (service *Service) ResumeFulfilling(w http.ResponseWriter, r *http.Request) *output.Error {
+	return resumeQueue(service, service.fulfillingQueue(), w)
+}
+
+// GetPostProcessJob returns the state and timing of the post processing job for an order
+func (service *Service) GetPostProcessJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return getJob(service, service.postProcessingQueue(), w, r)
+}
+
+// CancelPostProcessJob removes an order's waiting post processing job from the queue
+func (service *Service) CancelPostProcessJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return cancelJob(service, service.postProcessingQueue(), w, r)
+}
+
+// PromotePostProcessJob makes an order's waiting post processing job high priority
+func (service *Service) PromotePostProcessJob(w http.ResponseWriter, r *http.Request) *output.Error {
+	return promoteJob(service, service.postProcessingQueue(), w, r)
+}
+
+// PausePostProcessing stops post processing workers from starting new jobs (running
+// jobs finish)
+func (service *Service) PausePostProcessing(w http.ResponseWriter, r *http.Request) *output.Error {
+	return pauseQueue(service, service.postProcessingQueue(), w)
+}
+
+// ResumePostProcessing allows post processing workers to start jobs again
+func (service *Service) ResumePostProcessing(w http.ResponseWriter, r *http.Request) *output.Error {
+	return resumeQueue(service, service.postProcessingQueue(), w)
+}
@@ -0,0 +1,85 @@
+package orders
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/output"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var errSyncAccountNotUsable = errors.New("acme account is not usable (must be registered and valid)")
+
+// syncAccountOrdersWriteTimeout is how long the server may take to respond to a sync;
+// fetching every listed order and certificate takes much longer than the server's
+// usual write timeout
+const syncAccountOrdersWriteTimeout = 15 * time.Minute
+
+// accountOrdersSyncResponse is the response to an account orders sync
+type accountOrdersSyncResponse struct {
+	output.JsonResponse
+	OrdersSync accountOrdersSync `json:"orders_sync"`
+}
+
+// SyncAccountOrders is a handler that fetches the account's orders list from the
+// ACME server (see: rfc8555 s 7.1.2.1) and imports any listed orders (and their
+// certificates) that are missing from storage, and updates the ones that exist.
+// This is useful to recover orders after restoring an older backup.
+// endpoint: /api/v1/acmeaccounts/:id/sync-orders
+func (service *Service) SyncAccountOrders(w http.ResponseWriter, r *http.Request) *output.Error {
+	// get id from param
+	idParam := httprouter.ParamsFromContext(r.Context()).ByName("id")
+	accountId, err := strconv.Atoi(idParam)
+	if err != nil {
+		service.logger.Debug(err)
+		return output.ErrValidationFailed
+	}
+
+	// validation
+	account, outErr := service.accounts.GetAccount(accountId)
+	if outErr != nil {
+		return outErr
+	}
+	if !service.accounts.AccountUsable(accountId) {
+		service.logger.Debug(errSyncAccountNotUsable)
+		return output.ErrValidationFailed
+	}
+	// end validation
+
+	// sync runs longer than the server's usual write timeout
+	err = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(syncAccountOrdersWriteTimeout))
+	if err != nil {
+		service.logger.Errorf("failed to extend write deadline for account orders sync (%s)", err)
+	}
+
+	// do sync
+	ordersSync, err := service.syncAccountOrders(account)
+	if err != nil {
+		if errors.Is(err, acme.ErrAccountOrdersUnsupported) {
+			service.logger.Debug(err)
+			return output.ErrOrdersListNone
+		}
+		service.logger.Error(err)
+		if _, rateLimited := acme.RateLimitedUntil(err); rateLimited {
+			return output.ErrOrderRateLimited
+		}
+		return output.ErrInternal
+	}
+
+	// write response
+	response := &accountOrdersSyncResponse{}
+	response.StatusCode = http.StatusOK
+	response.Message = "synced account orders"
+	response.OrdersSync = ordersSync
+
+	err = service.output.WriteJSON(w, response)
+	if err != nil {
+		service.logger.Errorf("failed to write json (%s)", err)
+		return output.ErrWriteJsonError
+	}
+
+	return nil
+}
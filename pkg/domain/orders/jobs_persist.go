@@ -0,0 +1,167 @@
+package orders
+
+import (
+	"fmt"
+	"time"
+)
+
+// job queue names (used when persisting queued jobs)
+const (
+	jobQueueFulfill     = "fulfill"
+	jobQueuePostProcess = "post_process"
+)
+
+// maxJobInterruptions is how many times a job may be interrupted mid-run (e.g. by
+// a restart or crash) before it is no longer restored; this prevents a job that
+// crashes the app from being retried forever
+const maxJobInterruptions = 3
+
+// QueuedJob is an order fulfilling or post processing job, as saved in storage so
+// queued jobs survive a restart
+type QueuedJob struct {
+	Queue            string
+	OrderID          int
+	HighPriority     bool
+	Running          bool
+	InterruptedCount int
+	QueuedAt         int
+	// NotBefore is when a deferred job may run again (unix time, 0 if not deferred)
+	NotBefore int
+}
+
+// persistNewQueuedJob saves a job that is about to be added to a job manager. If the
+// job is already saved (i.e. it is already in the manager), the saved job is left as
+// is. A failure to save is logged but does not prevent the job from running.
+func (service *Service) persistNewQueuedJob(job QueuedJob) {
+	err := service.storage.PostQueuedJob(job)
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to save job for order id %d, it will not be restored after a restart (%s)", job.Queue, job.OrderID, err)
+	}
+}
+
+// persistQueuedJob saves a job as waiting, replacing the saved job if there is one. A
+// failure to save is logged but does not prevent the job from running.
+func (service *Service) persistQueuedJob(job QueuedJob) {
+	err := service.storage.PutQueuedJob(job)
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to save job for order id %d, it will not be restored after a restart (%s)", job.Queue, job.OrderID, err)
+	}
+}
+
+// jobStarted marks the persisted job as running so, if it does not finish, it can
+// be identified as interrupted at the next start
+func (service *Service) jobStarted(queue string, orderID int) {
+	err := service.storage.PutQueuedJobRunning(queue, orderID, int(time.Now().Unix()))
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to mark job for order id %d as running (%s)", queue, orderID, err)
+	}
+}
+
+// jobFinished removes the persisted job once it is done running. If shutdown was
+// called, the job may not have completed so it is saved as waiting (a clean shutdown
+// is not an interruption) and retried at the next start. A job that queued itself
+// again (requeued) is also kept.
+func (service *Service) jobFinished(job QueuedJob, requeued bool) {
+	if requeued {
+		return
+	}
+
+	if service.shutdownContext.Err() != nil {
+		service.logger.Infof("%s job queue: job for order id %d stopped by shutdown, it will be retried at the next start", job.Queue, job.OrderID)
+		service.persistQueuedJob(job)
+		return
+	}
+
+	err := service.storage.DeleteQueuedJob(job.Queue, job.OrderID)
+	if err != nil {
+		service.logger.Errorf("%s job queue: failed to remove finished job for order id %d (%s)", job.Queue, job.OrderID, err)
+	}
+}
+
+// restoreQueuedJobs adds the jobs that were saved before the last shutdown back to the
+// job managers. Jobs that were interrupted mid-run are restored first, then high and
+// low priority jobs in the order they were originally queued. A job that can no longer
+// run (e.g. the order is now in a final state) is discarded.
+func (service *Service) restoreQueuedJobs() {
+	jobs, err := service.storage.GetAllQueuedJobs()
+	if err != nil {
+		service.logger.Errorf("job queue: failed to get saved jobs, queued jobs will not be restored (%s)", err)
+		return
+	}
+
+	for _, job := range jobs {
+		err = service.restoreQueuedJob(job)
+		if err != nil {
+			service.logger.Warnf("%s job queue: discarding saved job for order id %d (%s)", job.Queue, job.OrderID, err)
+
+			err = service.storage.DeleteQueuedJob(job.Queue, job.OrderID)
+			if err != nil {
+				service.logger.Errorf("%s job queue: failed to remove saved job for order id %d (%s)", job.Queue, job.OrderID, err)
+			}
+		}
+	}
+
+	if len(jobs) > 0 {
+		service.logger.Infof("job queue: processed %d saved job(s)", len(jobs))
+	}
+}
+
+// restoreQueuedJob adds a single saved job back to its job manager
+func (service *Service) restoreQueuedJob(job QueuedJob) error {
+	// interrupted mid-run (a clean shutdown saves the job as waiting, so this was a crash)
+	if job.Running {
+		job.InterruptedCount++
+		if job.InterruptedCount > maxJobInterruptions {
+			return fmt.Errorf("interrupted %d times", job.InterruptedCount)
+		}
+		service.logger.Warnf("%s job queue: job for order id %d was interrupted while running, retrying (interruption %d of %d)", job.Queue, job.OrderID, job.InterruptedCount, maxJobInterruptions)
+	}
+
+	queuedAt := time.Unix(int64(job.QueuedAt), 0)
+
+	switch job.Queue {
+	case jobQueueFulfill:
+		newJob, err := service.makeFulfillingJob(job.OrderID, job.HighPriority)
+		if err != nil {
+			return err
+		}
+		newJob.addedToQueue = queuedAt
+		newJob.interruptedCount = job.InterruptedCount
+
+		// save before adding (see persistNewQueuedJob)
+		service.persistQueuedJob(job)
+
+		// deferred jobs still wait (e.g. for a rate limit to expire)
+		notBefore := time.Time{}
+		if job.NotBefore > 0 {
+			notBefore = time.Unix(int64(job.NotBefore), 0)
+		}
+
+		err = service.orderFulfilling.AddJobAfter(newJob, notBefore)
+		if err != nil {
+			return err
+		}
+
+	case jobQueuePostProcess:
+		newJob, err := service.makePostProcessJob(job.OrderID, job.HighPriority)
+		if err != nil {
+			return err
+		}
+		newJob.addedToQueue = queuedAt
+		newJob.interruptedCount = job.InterruptedCount
+
+		// save before adding (see persistNewQueuedJob)
+		job.NotBefore = 0
+		service.persistQueuedJob(job)
+
+		err = service.postProcessing.AddJob(newJob)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown job queue %s", job.Queue)
+	}
+
+	return nil
+}
@@ -21,15 +21,27 @@ type Order struct {
 	Error          *acme.Error
 	Expires        *int
 	DnsIdentifiers []string
+	IpIdentifiers  []string
 	Authorizations []string
 	Finalize       string
 	FinalizedKey   *private_keys.Key
 	CertificateUrl *string
 	Pem            *string
+	AlternatePems  []string
 	ValidFrom      *int
 	ValidTo        *int
 	CreatedAt      int
 	UpdatedAt      int
+
+	// ACME Renewal Information (ARI) suggested window
+	RenewalInfoWindowStart *int
+	RenewalInfoWindowEnd   *int
+
+	// STAR (auto-renewal) orders only
+	StarCertificateUrl *string
+	StarStart          *int
+	StarEnd            *int
+	StarLifetime       *int
 }
 
 // orderSummaryResponse is a JSON response containing only
@@ -42,11 +54,24 @@ type orderSummaryResponse struct {
 	KnownRevoked      bool                            `json:"known_revoked"`
 	Error             *acme.Error                     `json:"error"`
 	DnsIdentifiers    []string                        `json:"dns_identifiers"`
+	IpIdentifiers     []string                        `json:"ip_identifiers"`
 	FinalizedKey      *orderKeySummaryResponse        `json:"finalized_key"`
 	ValidFrom         *int                            `json:"valid_from"`
 	ValidTo           *int                            `json:"valid_to"`
 	CreatedAt         int                             `json:"created_at"`
 	UpdatedAt         int                             `json:"updated_at"`
+
+	RenewalInfoWindowStart *int `json:"renewal_info_window_start"`
+	RenewalInfoWindowEnd   *int `json:"renewal_info_window_end"`
+
+	AutoRenewal *orderAutoRenewalResponse `json:"auto_renewal,omitempty"`
+}
+
+// orderAutoRenewalResponse contains the auto-renewal details of STAR orders
+type orderAutoRenewalResponse struct {
+	StartDate *int `json:"start_date"`
+	EndDate   *int `json:"end_date"`
+	Lifetime  *int `json:"lifetime"`
 }
 
 type orderCertificateSummaryResponse struct {
@@ -92,6 +117,16 @@ func (order Order) summaryResponse(service *Service) orderSummaryResponse {
 	fulfillJob, _ := service.makeFulfillingJob(order.ID, false)
 	fulfillingWorker := service.orderFulfilling.JobExists(fulfillJob)
 
+	// only STAR orders have auto-renewal details
+	var autoRenewal *orderAutoRenewalResponse
+	if order.StarEnd != nil {
+		autoRenewal = &orderAutoRenewalResponse{
+			StartDate: order.StarStart,
+			EndDate:   order.StarEnd,
+			Lifetime:  order.StarLifetime,
+		}
+	}
+
 	return orderSummaryResponse{
 		FulfillmentWorker: fulfillingWorker,
 		ID:                order.ID,
@@ -117,11 +152,17 @@ func (order Order) summaryResponse(service *Service) orderSummaryResponse {
 		KnownRevoked:   order.KnownRevoked,
 		Error:          order.Error,
 		DnsIdentifiers: order.DnsIdentifiers,
+		IpIdentifiers:  order.IpIdentifiers,
 		FinalizedKey:   finalKey,
 		ValidFrom:      order.ValidFrom,
 		ValidTo:        order.ValidTo,
 		CreatedAt:      order.CreatedAt,
 		UpdatedAt:      order.UpdatedAt,
+
+		RenewalInfoWindowStart: order.RenewalInfoWindowStart,
+		RenewalInfoWindowEnd:   order.RenewalInfoWindowEnd,
+
+		AutoRenewal: autoRenewal,
 	}
 }
 
@@ -2,6 +2,7 @@ package orders
 
 import (
 	"errors"
+	"legocerthub-backend/pkg/acme"
 	"legocerthub-backend/pkg/output"
 )
 
@@ -28,9 +29,40 @@ func (service *Service) placeNewOrderAndFulfill(certId int, highPriority bool) (
 		return Order{}, output.ErrInternal
 	}
 
-	acmeResponse, err := acmeService.NewOrder(cert.NewOrderPayload(), key)
+	// don't place new orders while rate limited (avoids piling up failed orders)
+	if blockedUntil := acmeService.BlockedUntil(key); !blockedUntil.IsZero() {
+		service.logger.Warnf("certificate %d: not placing new order, acme rate limited until %s", cert.ID, blockedUntil)
+		return Order{}, output.ErrOrderRateLimited
+	}
+
+	newOrderPayload := cert.NewOrderPayload()
+
+	// the profile was validated when set, but confirm the server still offers it
+	if newOrderPayload.Profile != "" && !acmeService.SupportsProfile(newOrderPayload.Profile) {
+		service.logger.Errorf("certificate %d profile '%s' is not offered by its acme server", cert.ID, newOrderPayload.Profile)
+		return Order{}, output.ErrValidationFailed
+	}
+
+	// if ARI is supported, indicate which cert this order replaces (if any)
+	if acmeService.SupportsRenewalInfo() {
+		newOrderPayload.Replaces = service.replacesCertId(cert.ID)
+	}
+
+	acmeResponse, err := acmeService.NewOrder(newOrderPayload, key)
+	// if the server rejected the order and replaces was included, try once more without it
+	// (e.g. the replaced cert was already replaced, or was issued to a different account)
+	// unless rate limited, in which case another attempt would also fail
+	_, rateLimited := acme.RateLimitedUntil(err)
+	if err != nil && newOrderPayload.Replaces != "" && !rateLimited {
+		service.logger.Debugf("new order with replaces (%s) failed (%s), retrying without replaces", newOrderPayload.Replaces, err)
+		newOrderPayload.Replaces = ""
+		acmeResponse, err = acmeService.NewOrder(newOrderPayload, key)
+	}
 	if err != nil {
 		service.logger.Error(err)
+		if _, rateLimited = acme.RateLimitedUntil(err); rateLimited {
+			return Order{}, output.ErrOrderRateLimited
+		}
 		return Order{}, output.ErrInternal
 	}
 	service.logger.Debugf("new order location: %s", acmeResponse.Location)
@@ -18,12 +18,16 @@ type NewOrderAcmePayload struct {
 	KnownRevoked   bool
 	Expires        int
 	DnsIds         []string
+	IpIds          []string
 	Error          *string
 	Authorizations []string
 	Finalize       string
 	Location       string
 	CreatedAt      int
 	UpdatedAt      int
+	StarStart      *int
+	StarEnd        *int
+	StarLifetime   *int
 }
 
 // newOrderAcmePayload makes a OrderAcmePayload using the specified certificate
@@ -41,6 +45,7 @@ func makeNewOrderAcmePayload(cert certificates.Certificate, acmeResponse acme.Or
 		KnownRevoked:   false,
 		Expires:        acmeResponse.Expires.ToUnixTime(),
 		DnsIds:         acmeResponse.Identifiers.DnsIdentifiers(),
+		IpIds:          acmeResponse.Identifiers.IpIdentifiers(),
 		Error:          acmeErr,
 		Authorizations: acmeResponse.Authorizations,
 		Finalize:       acmeResponse.Finalize,
@@ -48,6 +53,7 @@ func makeNewOrderAcmePayload(cert certificates.Certificate, acmeResponse acme.Or
 		CreatedAt:      int(time.Now().Unix()),
 		UpdatedAt:      int(time.Now().Unix()),
 	}
+	payload.StarStart, payload.StarEnd, payload.StarLifetime = autoRenewalFields(acmeResponse)
 
 	return payload
 }
@@ -57,12 +63,18 @@ type UpdateAcmeOrderPayload struct {
 	Status         string
 	Expires        *int
 	DnsIds         []string
+	IpIds          []string
 	Error          *string
 	Authorizations []string
 	Finalize       string
 	CertificateUrl *string
 	UpdatedAt      int
 	OrderId        int
+
+	StarCertificateUrl *string
+	StarStart          *int
+	StarEnd            *int
+	StarLifetime       *int
 }
 
 // makeUpdateOrderAcmePayload makes the UpdateAcmeOrderPayload using a new payload and the orderId
@@ -72,13 +84,40 @@ func makeUpdateOrderAcmePayload(orderId int, acmeResponse acme.Order) UpdateAcme
 		acmeErr = nil
 	}
 
-	return UpdateAcmeOrderPayload{
-		Status:         acmeResponse.Status,
-		DnsIds:         acmeResponse.Identifiers.DnsIdentifiers(),
-		Error:          acmeErr,
-		Authorizations: acmeResponse.Authorizations,
-		UpdatedAt:      int(time.Now().Unix()),
-		OrderId:        orderId,
-		CertificateUrl: acmeResponse.Certificate,
+	payload := UpdateAcmeOrderPayload{
+		Status:             acmeResponse.Status,
+		DnsIds:             acmeResponse.Identifiers.DnsIdentifiers(),
+		IpIds:              acmeResponse.Identifiers.IpIdentifiers(),
+		Error:              acmeErr,
+		Authorizations:     acmeResponse.Authorizations,
+		UpdatedAt:          int(time.Now().Unix()),
+		OrderId:            orderId,
+		CertificateUrl:     acmeResponse.Certificate,
+		StarCertificateUrl: acmeResponse.StarCertificate,
+	}
+	payload.StarStart, payload.StarEnd, payload.StarLifetime = autoRenewalFields(acmeResponse)
+
+	return payload
+}
+
+// autoRenewalFields returns the start, end, and lifetime of a STAR order. If the order
+// is not a STAR order, all are nil.
+func autoRenewalFields(acmeResponse acme.Order) (start *int, end *int, lifetime *int) {
+	if acmeResponse.AutoRenewal == nil {
+		return nil, nil, nil
+	}
+
+	end = new(int)
+	*end = acmeResponse.AutoRenewal.EndUnixTime()
+
+	lifetime = new(int)
+	*lifetime = acmeResponse.AutoRenewal.Lifetime
+
+	// start-date is optional
+	if startUnix := acmeResponse.AutoRenewal.StartUnixTime(); startUnix != 0 {
+		start = new(int)
+		*start = startUnix
 	}
+
+	return start, end, lifetime
 }
@@ -14,6 +14,10 @@ type postProcessJob struct {
 	highPriority  bool
 	orderID       int
 	certificateID int
+
+	// interruptedCount is how many times the job was interrupted by a crash (see
+	// maxJobInterruptions)
+	interruptedCount int
 }
 
 // makeFulfillingJob makes an orderFulfillJob
@@ -60,3 +64,24 @@ func (j *postProcessJob) Equal(j2 *postProcessJob) bool {
 func (j *postProcessJob) IsHighPriority() bool {
 	return j.highPriority
 }
+
+// SetHighPriority implements Job interface func to promote the job
+func (j *postProcessJob) SetHighPriority() {
+	j.highPriority = true
+}
+
+// QueuedAt implements Job interface func that returns when the job was queued
+func (j *postProcessJob) QueuedAt() time.Time {
+	return j.addedToQueue
+}
+
+// queuedJob returns the job as it is saved in storage
+func (j *postProcessJob) queuedJob() QueuedJob {
+	return QueuedJob{
+		Queue:            jobQueuePostProcess,
+		OrderID:          j.orderID,
+		HighPriority:     j.highPriority,
+		InterruptedCount: j.interruptedCount,
+		QueuedAt:         int(j.addedToQueue.Unix()),
+	}
+}
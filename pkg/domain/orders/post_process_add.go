@@ -13,6 +13,10 @@ func (service *Service) postProcess(orderID int, isHighPriority bool) (err error
 		return err
 	}
 
+	// save so the job survives a restart (before adding, so a worker can't start it
+	// before it is saved)
+	service.persistNewQueuedJob(newJob.queuedJob())
+
 	// add to the Job Manager
 	err = service.postProcessing.AddJob(newJob)
 	if err != nil {
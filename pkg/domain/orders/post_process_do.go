@@ -2,6 +2,10 @@ package orders
 
 // Do actually runs the post processing task(s)
 func (j *postProcessJob) Do(workerID int) {
+	// track running in storage, in case of restart
+	j.service.jobStarted(jobQueuePostProcess, j.orderID)
+	defer func() { j.service.jobFinished(j.queuedJob(), false) }()
+
 	// get order
 	order, err := j.service.storage.GetOneOrder(j.orderID)
 	if err != nil {
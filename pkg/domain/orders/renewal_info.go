@@ -0,0 +1,115 @@
+package orders
+
+import (
+	"errors"
+	"legocerthub-backend/pkg/acme"
+	"legocerthub-backend/pkg/randomness"
+	"time"
+)
+
+var errOrderMissingPem = errors.New("order does not have a pem")
+
+// updateOrderRenewalInfo fetches the ACME Renewal Information (ARI) for the specified order
+// and saves the suggested window to storage. If the order's ACME Server does not support
+// ARI, nil is returned without doing anything.
+func (service *Service) updateOrderRenewalInfo(order Order) (*acme.RenewalInfo, error) {
+	if order.Pem == nil {
+		return nil, errOrderMissingPem
+	}
+
+	acmeService, err := service.acmeServerService.AcmeService(order.Certificate.CertificateAccount.AcmeServer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ARI is optional
+	if !acmeService.SupportsRenewalInfo() {
+		return nil, nil
+	}
+
+	renewalInfo, err := acmeService.GetRenewalInfo(*order.Pem)
+	if err != nil {
+		return nil, err
+	}
+
+	// save window
+	err = service.storage.PutOrderRenewalInfo(order.ID, renewalInfo.WindowStart(), renewalInfo.WindowEnd())
+	if err != nil {
+		return nil, err
+	}
+
+	if renewalInfo.ExplanationUrl != "" {
+		service.logger.Infof("order %d (certificate name: %s) renewal information includes explanation: %s", order.ID, order.Certificate.Name, renewalInfo.ExplanationUrl)
+	}
+
+	return &renewalInfo, nil
+}
+
+// updateRenewalInfo refreshes the ACME Renewal Information (ARI) of every certificate's
+// current valid order. It returns the IDs of any certificates that should be renewed
+// because a random time selected within the suggested window falls before renewBefore
+// (see: rfc9773 s 4.2).
+func (service *Service) updateRenewalInfo(renewBefore time.Time) (certIds []int) {
+	service.logger.Info("updating acme renewal information")
+
+	orderIds, err := service.storage.GetCurrentValidOrderIds()
+	if err != nil {
+		service.logger.Errorf("failed to get current valid orders for renewal information update (%s)", err)
+		return nil
+	}
+
+	// nothing to do
+	if len(orderIds) == 0 {
+		return nil
+	}
+
+	orders, err := service.storage.GetOrders(orderIds)
+	if err != nil {
+		service.logger.Errorf("failed to get current valid orders for renewal information update (%s)", err)
+		return nil
+	}
+
+	for _, order := range orders {
+		renewalInfo, err := service.updateOrderRenewalInfo(order)
+		if err != nil {
+			service.logger.Errorf("failed to update renewal information for order %d (%s)", order.ID, err)
+			continue
+		}
+
+		// acme server doesn't support ari
+		if renewalInfo == nil {
+			continue
+		}
+
+		// select a random time within the window
+		windowStart := renewalInfo.WindowStart()
+		windowEnd := renewalInfo.WindowEnd()
+		renewAt := time.Unix(int64(windowStart+randomness.GenerateInsecureInt(windowEnd-windowStart+1)), 0)
+
+		if renewAt.Before(renewBefore) {
+			service.logger.Debugf("certificate %d is due for renewal per renewal information (selected time %s)", order.Certificate.ID, renewAt)
+			certIds = append(certIds, order.Certificate.ID)
+		}
+	}
+
+	service.logger.Info("acme renewal information update complete")
+	return certIds
+}
+
+// replacesCertId returns the ARI CertID of the specified certificate's newest valid order
+// for use in a new order's `replaces` field. If there is no such order, an empty string
+// is returned.
+func (service *Service) replacesCertId(certId int) string {
+	order, err := service.storage.GetCertNewestValidOrderById(certId)
+	if err != nil || order.Pem == nil {
+		return ""
+	}
+
+	ariCertId, err := acme.AriCertId(*order.Pem)
+	if err != nil {
+		service.logger.Debugf("failed to make ari cert id for order %d (%s)", order.ID, err)
+		return ""
+	}
+
+	return ariCertId
+}
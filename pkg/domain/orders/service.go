@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"legocerthub-backend/pkg/datatypes/job_manager"
+	"legocerthub-backend/pkg/domain/acme_accounts"
 	"legocerthub-backend/pkg/domain/acme_servers"
 	"legocerthub-backend/pkg/domain/authorizations"
 	"legocerthub-backend/pkg/domain/certificates"
@@ -27,6 +28,7 @@ type App interface {
 	GetOutputter() *output.Service
 	GetOrderStorage() Storage
 	GetAcmeServerService() *acme_servers.Service
+	GetAcctsService() *acme_accounts.Service
 	GetCertificatesService() *certificates.Service
 
 	// for fulfiller
@@ -45,6 +47,7 @@ type Storage interface {
 	GetOneOrder(orderId int) (order Order, err error)
 	GetOrders(orderIDs []int) (orders []Order, err error)
 	GetOrdersByCert(certId int, q pagination_sort.Query) (orders []Order, totalRows int, err error)
+	GetOrderIdByLocation(location string) (orderId int, err error)
 	GetCertNewestValidOrderById(id int) (order Order, err error)
 
 	PostNewOrder(payload NewOrderAcmePayload) (newId int, err error)
@@ -54,13 +57,25 @@ type Storage interface {
 	UpdateFinalizedKey(orderId int, keyId int) (err error)
 	UpdateOrderCert(orderId int, CertPayload CertPayload) (err error)
 	RevokeOrder(orderId int) (err error)
+	PutOrderRenewalInfo(orderId int, windowStart int, windowEnd int) (err error)
+
+	// job queues
+	GetAllQueuedJobs() (jobs []QueuedJob, err error)
+	PostQueuedJob(job QueuedJob) (err error)
+	PutQueuedJob(job QueuedJob) (err error)
+	PutQueuedJobRunning(queue string, orderId int, startedAt int) (err error)
+	PutQueuedJobHighPriority(queue string, orderId int) (err error)
+	DeleteQueuedJob(queue string, orderId int) (err error)
 
 	GetAllValidCurrentOrders(q pagination_sort.Query) (orders []Order, totalRows int, err error)
 	GetAllIncompleteOrderIds() (orderIds []int, err error)
 	GetExpiringCertIds(maxTimeRemaining time.Duration) (certIds []int, err error)
+	GetCurrentValidOrderIds() (orderIds []int, err error)
+	GetActiveStarOrderIds() (orderIds []int, err error)
 	GetNewestIncompleteCertOrderId(certId int) (orderId int, err error)
 
 	// certs
+	GetAllCerts(q pagination_sort.Query) (certs []certificates.Certificate, totalRowCount int, err error)
 	UpdateCertUpdatedTime(certId int) (err error)
 }
 
@@ -79,6 +94,7 @@ type Service struct {
 	output            *output.Service
 	storage           Storage
 	acmeServerService *acme_servers.Service
+	accounts          *acme_accounts.Service
 	authorizations    *authorizations.Service
 	certificates      *certificates.Service
 
@@ -122,6 +138,12 @@ func NewService(app App, cfg *Config) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// accounts
+	service.accounts = app.GetAcctsService()
+	if service.accounts == nil {
+		return nil, errServiceComponent
+	}
+
 	// auths
 	service.authorizations = app.GetAuthsService()
 	if service.authorizations == nil {
@@ -180,8 +202,14 @@ func NewService(app App, cfg *Config) (*Service, error) {
 		return nil, errServiceComponent
 	}
 
+	// restore jobs that were queued before the last shutdown
+	service.restoreQueuedJobs()
+
 	// start service to automatically place and complete orders
 	service.startAutoOrderService(cfg, app.GetShutdownContext(), app.GetShutdownWaitGroup())
 
+	// start service to rotate in new certificates issued for STAR orders
+	service.startStarRotationService(app.GetShutdownContext(), app.GetShutdownWaitGroup())
+
 	return service, nil
 }
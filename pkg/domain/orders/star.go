@@ -0,0 +1,209 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"legocerthub-backend/pkg/acme"
+	"sync"
+	"time"
+)
+
+// Short-Term, Automatically Renewed (STAR) certificates (see: rfc8739)
+// A STAR order is fulfilled once, after which the acme server issues a new short-lived
+// certificate on its own schedule until the order's end-date. The current certificate
+// is always available at the order's star-certificate url.
+
+// starPollInterval is how often active STAR orders are checked for a new certificate
+const starPollInterval = 5 * time.Minute
+
+// currentCertificateUrl returns the url to download the order's certificate from. For
+// STAR orders this is the star-certificate url.
+func currentCertificateUrl(acmeOrder acme.Order) *string {
+	if acmeOrder.Certificate != nil {
+		return acmeOrder.Certificate
+	}
+
+	return acmeOrder.StarCertificate
+}
+
+// startStarRotationService starts a go routine that periodically downloads the current
+// certificate of each active STAR order and rotates it into storage (and thus the download
+// endpoints) when the acme server has issued a new one
+func (service *Service) startStarRotationService(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			delayTimer := time.NewTimer(starPollInterval)
+
+			select {
+			case <-ctx.Done():
+				// ensure timer releases resources
+				if !delayTimer.Stop() {
+					<-delayTimer.C
+				}
+
+				service.logger.Info("star certificate rotation service shutdown complete")
+				return
+
+			case <-delayTimer.C:
+				// proceed to check
+			}
+
+			service.rotateStarCertificates()
+		}
+	}()
+}
+
+// rotateStarCertificates checks all active STAR orders and rotates in the current
+// certificate for any that are due
+func (service *Service) rotateStarCertificates() {
+	orderIds, err := service.storage.GetActiveStarOrderIds()
+	if err != nil {
+		service.logger.Errorf("failed to get active star orders (%s)", err)
+		return
+	}
+	if len(orderIds) == 0 {
+		return
+	}
+
+	starOrders, err := service.storage.GetOrders(orderIds)
+	if err != nil {
+		service.logger.Errorf("failed to get active star orders (%s)", err)
+		return
+	}
+
+	for _, order := range starOrders {
+		if !order.starRotationDue() {
+			continue
+		}
+
+		err = service.rotateStarCertificate(order)
+		if err != nil {
+			service.logger.Errorf("star order %d (certificate name: %s): failed to rotate certificate (%s)", order.ID, order.Certificate.Name, err)
+		}
+	}
+}
+
+// starRotationDue returns true if the order's stored certificate is past the midpoint
+// of its validity (by which time the acme server should have issued the next one), or
+// if there is no stored certificate
+func (order *Order) starRotationDue() bool {
+	if order.Pem == nil || order.ValidFrom == nil || order.ValidTo == nil {
+		return true
+	}
+
+	midpoint := *order.ValidFrom + (*order.ValidTo-*order.ValidFrom)/2
+	return time.Now().Unix() >= int64(midpoint)
+}
+
+// rotateStarCertificate downloads the current certificate of a STAR order and, if it is
+// not the certificate already in storage, saves it and does any post processing
+func (service *Service) rotateStarCertificate(order Order) error {
+	key, err := order.Certificate.CertificateAccount.AcmeAccountKey()
+	if err != nil {
+		return err
+	}
+
+	acmeService, err := service.acmeServerService.AcmeService(order.Certificate.CertificateAccount.AcmeServer.ID)
+	if err != nil {
+		return err
+	}
+
+	// don't poll while rate limited
+	if !acmeService.BlockedUntil(key).IsZero() {
+		return nil
+	}
+
+	certPemChains, err := acmeService.DownloadCertificateChains(*order.StarCertificateUrl, key)
+	if err != nil {
+		// the order may have been canceled or the server may have stopped renewing,
+		// refresh the order so it is no longer polled if so
+		acmeOrder, getErr := acmeService.GetOrder(order.Location, key)
+		if getErr == nil {
+			putErr := service.storage.PutOrderAcme(makeUpdateOrderAcmePayload(order.ID, acmeOrder))
+			if putErr != nil {
+				service.logger.Error(putErr)
+			}
+		}
+
+		return err
+	}
+
+	// nothing to do if a new cert hasn't been issued yet
+	if order.Pem != nil && sameLeafCertificate(certPemChains[0], *order.Pem) {
+		return nil
+	}
+
+	_, err = service.savePemChains(order, certPemChains)
+	if err != nil {
+		return err
+	}
+
+	service.logger.Infof("star order %d (certificate name: %s): rotated in new certificate", order.ID, order.Certificate.Name)
+
+	// update certificate timestamp
+	err = service.storage.UpdateCertUpdatedTime(order.Certificate.ID)
+	if err != nil {
+		service.logger.Error(err)
+		// no return
+	}
+
+	// send to post-processing queue
+	if order.hasPostProcessingToDo() {
+		err = service.postProcess(order.ID, false)
+		if err != nil {
+			service.logger.Errorf("star order %d: failed to post process (%s)", order.ID, err)
+		}
+	}
+
+	// also update LeGo Server Cert (if this order was for the LeGo Server)
+	if service.serverCertificateName != nil && *service.serverCertificateName == order.Certificate.Name {
+		err = service.loadHttpsCertificateFunc()
+		if err != nil {
+			service.logger.Errorf("star order %d: failed to load lego's new https certificate (%s)", order.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// sameLeafCertificate returns true if the first certificate of both pem chains is the same
+func sameLeafCertificate(pemChainA string, pemChainB string) bool {
+	blockA, _ := pem.Decode([]byte(pemChainA))
+	blockB, _ := pem.Decode([]byte(pemChainB))
+	if blockA == nil || blockB == nil {
+		return false
+	}
+
+	return bytes.Equal(blockA.Bytes, blockB.Bytes)
+}
+
+// starCertIds returns the ids of certificates that have an active STAR order that does
+// not end before the specified time. These certificates are kept current by the acme
+// server and should not be reordered.
+func (service *Service) starCertIds(activeUntil time.Time) (certIds []int, err error) {
+	orderIds, err := service.storage.GetActiveStarOrderIds()
+	if err != nil {
+		return nil, err
+	}
+	if len(orderIds) == 0 {
+		return nil, nil
+	}
+
+	starOrders, err := service.storage.GetOrders(orderIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range starOrders {
+		if order.StarEnd != nil && int64(*order.StarEnd) > activeUntil.Unix() {
+			certIds = append(certIds, order.Certificate.ID)
+		}
+	}
+
+	return certIds, nil
+}
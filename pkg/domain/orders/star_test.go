@@ -0,0 +1,115 @@
+package orders
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"legocerthub-backend/pkg/acme"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// makeTestCertPem returns a self signed pem certificate valid from notBefore to notAfter
+func makeTestCertPem(t *testing.T, notBefore time.Time, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "star.example.com"},
+		DNSNames:     []string{"star.example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// starRotationDueTest is a stored STAR certificate's validity (relative to now) and if
+// rotation should be due
+type starRotationDueTest struct {
+	name      string
+	hasPem    bool
+	validFrom time.Duration
+	validTo   time.Duration
+	due       bool
+}
+
+var starRotationDueTests = []starRotationDueTest{
+	{name: "no certificate", hasPem: false, due: true},
+	{name: "just issued", hasPem: true, validFrom: -time.Minute, validTo: 48 * time.Hour, due: false},
+	{name: "before midpoint", hasPem: true, validFrom: -23 * time.Hour, validTo: 25 * time.Hour, due: false},
+	{name: "at midpoint", hasPem: true, validFrom: -24 * time.Hour, validTo: 24 * time.Hour, due: true},
+	{name: "after midpoint", hasPem: true, validFrom: -25 * time.Hour, validTo: 23 * time.Hour, due: true},
+	{name: "expired", hasPem: true, validFrom: -72 * time.Hour, validTo: -24 * time.Hour, due: true},
+}
+
+func TestOrders_StarRotationDue(t *testing.T) {
+	for _, test := range starRotationDueTests {
+		order := Order{}
+		if test.hasPem {
+			now := time.Now()
+			certPem := makeTestCertPem(t, now.Add(test.validFrom), now.Add(test.validTo))
+			validFrom := int(now.Add(test.validFrom).Unix())
+			validTo := int(now.Add(test.validTo).Unix())
+
+			order.Pem = &certPem
+			order.ValidFrom = &validFrom
+			order.ValidTo = &validTo
+		}
+
+		if due := order.starRotationDue(); due != test.due {
+			t.Errorf("star rotation due test case '%s' returned %t, expected %t", test.name, due, test.due)
+		}
+	}
+}
+
+func TestOrders_StarSameLeafCertificate(t *testing.T) {
+	now := time.Now()
+	leafA := makeTestCertPem(t, now, now.Add(48*time.Hour))
+	leafB := makeTestCertPem(t, now, now.Add(48*time.Hour))
+	intermediate := makeTestCertPem(t, now, now.Add(365*24*time.Hour))
+
+	// polled chain includes the intermediate, stored pem may not
+	if !sameLeafCertificate(leafA+intermediate, leafA) {
+		t.Error("same leaf certificate with different chain returned not same (would rotate needlessly)")
+	}
+	if sameLeafCertificate(leafB+intermediate, leafA+intermediate) {
+		t.Error("new leaf certificate returned same (would never rotate)")
+	}
+	if sameLeafCertificate("", leafA) || sameLeafCertificate(leafA, "not a pem") {
+		t.Error("invalid pem returned same")
+	}
+}
+
+func TestOrders_StarCurrentCertificateUrl(t *testing.T) {
+	certUrl := "https://acme.example.com/cert/1"
+	starUrl := "https://acme.example.com/star-cert/1"
+
+	// regular order
+	url := currentCertificateUrl(acme.Order{Certificate: &certUrl})
+	if url == nil || *url != certUrl {
+		t.Errorf("regular order certificate url is %v, expected %s", url, certUrl)
+	}
+
+	// STAR order
+	url = currentCertificateUrl(acme.Order{StarCertificate: &starUrl})
+	if url == nil || *url != starUrl {
+		t.Errorf("star order certificate url is %v, expected %s", url, starUrl)
+	}
+
+	// not yet issued
+	if url = currentCertificateUrl(acme.Order{}); url != nil {
+		t.Errorf("unissued order certificate url is %s, expected nil", *url)
+	}
+}
@@ -64,7 +64,7 @@ func (service *Service) isOrderRetryable(certId int, orderId int) *output.Error
 	}
 
 	// check if order is in a final state (can't retry)
-	if order.Status == "valid" || order.Status == "invalid" {
+	if order.Status == "valid" || order.Status == "invalid" || order.Status == "canceled" {
 		service.logger.Debug(errOrderRetryFinal)
 		return output.ErrValidationFailed
 	}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -33,7 +34,12 @@ func New(userAgent string) (client *Client) {
 // do creates a request with the specified parameters, modifies it in accord with ACME
 // spec and then executes the request
 func (c *Client) do(method string, url string, body io.Reader, addlHeader http.Header) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
+	return c.doWithContext(context.Background(), method, url, body, addlHeader)
+}
+
+// doWithContext is the same as do, but the request is bound to the specified context
+func (c *Client) doWithContext(ctx context.Context, method string, url string, body io.Reader, addlHeader http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -78,15 +84,33 @@ func (c *Client) Get(url string) (*http.Response, error) {
 	return c.GetWithHeader(url, nil)
 }
 
+// GetWithContext does a get request to the specified url that is bound to the
+// specified context
+func (c *Client) GetWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return c.doWithContext(ctx, http.MethodGet, url, nil, nil)
+}
+
 // Head does a head request to the specified url
 // a head request is the same as Get but without the body
 func (c *Client) Head(url string) (*http.Response, error) {
 	return c.do(http.MethodHead, url, nil, nil)
 }
 
+// HeadWithContext does a head request to the specified url that is bound to
+// the specified context
+func (c *Client) HeadWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return c.doWithContext(ctx, http.MethodHead, url, nil, nil)
+}
+
 // PostWithHeader does a post request using the specified url, content type, body,
 // and additionally specified headers.
 func (c *Client) PostWithHeader(url string, contentType string, body io.Reader, header http.Header) (resp *http.Response, err error) {
+	return c.PostWithHeaderWithContext(context.Background(), url, contentType, body, header)
+}
+
+// PostWithHeaderWithContext is the same as PostWithHeader, but the request is bound
+// to the specified context
+func (c *Client) PostWithHeaderWithContext(ctx context.Context, url string, contentType string, body io.Reader, header http.Header) (resp *http.Response, err error) {
 	// if no headers, make empty for Content-Type
 	if header == nil {
 		header = make(http.Header)
@@ -95,7 +119,7 @@ func (c *Client) PostWithHeader(url string, contentType string, body io.Reader,
 	// explicitly set (override) content type header
 	header.Set("Content-Type", contentType)
 
-	return c.do(http.MethodPost, url, body, header)
+	return c.doWithContext(ctx, http.MethodPost, url, body, header)
 }
 
 // Post does a post request using the specified url, content type, and body
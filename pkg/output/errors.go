@@ -22,7 +22,12 @@ var (
 	ErrBadDirectoryURL  = &Error{StatusCode: 400, Message: "error: specified acme directory url is not https or did not return a valid directory json response"}
 
 	// order
-	ErrOrderInvalid = &Error{StatusCode: 400, Message: "error: order status is invalid (which cannot be recovered from)"}
+	ErrOrderInvalid     = &Error{StatusCode: 400, Message: "error: order status is invalid (which cannot be recovered from)"}
+	ErrOrderRateLimited = &Error{StatusCode: 429, Message: "error: acme server rate limit in effect, try again later"}
+	ErrOrdersListNone   = &Error{StatusCode: 400, Message: "error: acme server does not provide an orders list for the account"}
+
+	// jobs
+	ErrJobRunning = &Error{StatusCode: 409, Message: "error: job is already running (only waiting jobs can be changed)"}
 )
 
 // Error is the standardized error structure, it is the same as a regular message but also
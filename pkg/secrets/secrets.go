@@ -0,0 +1,183 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config values that hold a secret may be a plain value or one of these references.
+const (
+	// encrypted value (with the master key), the rest is the base64 raw url encoded
+	// nonce + ciphertext
+	encryptedPrefix = "enc:"
+	// name of an environment variable that contains the value
+	envPrefix = "env:"
+	// path of a file that contains the value
+	filePrefix = "file:"
+)
+
+// RedactedValue replaces encrypted and plain secrets in api responses
+const RedactedValue = "[redacted]"
+
+var errRedactedValue = errors.New("secret value is redacted; send the value or a reference instead")
+
+// Field is a config value that may contain a secret. It is either Value or, for
+// values that are not addressable, the MapKey entry of Map.
+type Field struct {
+	Value *string
+	// KeyValue indicates Value is an environment style KEY=VALUE pair and only VALUE
+	// is secret
+	KeyValue bool
+
+	Map    map[string]string
+	MapKey string
+}
+
+// MapFields returns a Field for every value in m
+func MapFields(m map[string]string) []Field {
+	fields := make([]Field, 0, len(m))
+	for k := range m {
+		fields = append(fields, Field{Map: m, MapKey: k})
+	}
+
+	return fields
+}
+
+// KeyValueFields returns a Field for every KEY=VALUE pair in s
+func KeyValueFields(s []string) []Field {
+	fields := make([]Field, 0, len(s))
+	for i := range s {
+		fields = append(fields, Field{Value: &s[i], KeyValue: true})
+	}
+
+	return fields
+}
+
+// Config is implemented by configs that contain secrets
+type Config interface {
+	SecretFields() []Field
+}
+
+// secretPart returns the secret portion of the field and a func to replace it. ok is
+// false if there is no secret in the field.
+func (f Field) secretPart() (secret string, replace func(string), ok bool) {
+	if f.Map != nil {
+		if f.Map[f.MapKey] == "" {
+			return "", nil, false
+		}
+		return f.Map[f.MapKey], func(s string) { f.Map[f.MapKey] = s }, true
+	}
+
+	if f.Value == nil || *f.Value == "" {
+		return "", nil, false
+	}
+
+	if !f.KeyValue {
+		return *f.Value, func(s string) { *f.Value = s }, true
+	}
+
+	key, secret, found := strings.Cut(*f.Value, "=")
+	if !found || secret == "" {
+		return "", nil, false
+	}
+
+	return secret, func(s string) { *f.Value = key + "=" + s }, true
+}
+
+// isReference returns true if the secret is already in a reference form
+func isReference(secret string) bool {
+	return strings.HasPrefix(secret, encryptedPrefix) || strings.HasPrefix(secret, envPrefix) ||
+		strings.HasPrefix(secret, filePrefix)
+}
+
+// Resolve replaces every secret reference in cfg with the value it refers to. Plain
+// values are left as is.
+func (service *Service) Resolve(cfg Config) error {
+	for _, f := range cfg.SecretFields() {
+		secret, replace, ok := f.secretPart()
+		if !ok {
+			continue
+		}
+
+		switch {
+		case secret == RedactedValue:
+			return errRedactedValue
+
+		case strings.HasPrefix(secret, encryptedPrefix):
+			value, err := service.decrypt(strings.TrimPrefix(secret, encryptedPrefix))
+			if err != nil {
+				return err
+			}
+			replace(value)
+
+		case strings.HasPrefix(secret, envPrefix):
+			name := strings.TrimPrefix(secret, envPrefix)
+			value, exists := os.LookupEnv(name)
+			if !exists {
+				return fmt.Errorf("secret environment variable %s is not set", name)
+			}
+			replace(value)
+
+		case strings.HasPrefix(secret, filePrefix):
+			name := strings.TrimPrefix(secret, filePrefix)
+			value, err := os.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("failed to read secret file (%s)", err)
+			}
+			replace(strings.TrimSpace(string(value)))
+		}
+	}
+
+	return nil
+}
+
+// Protect encrypts every plain secret in cfg so only references remain. If any value
+// was encrypted, changed is true.
+func (service *Service) Protect(cfg Config) (changed bool, err error) {
+	for _, f := range cfg.SecretFields() {
+		secret, replace, ok := f.secretPart()
+		if !ok || isReference(secret) {
+			continue
+		}
+
+		if secret == RedactedValue {
+			return false, errRedactedValue
+		}
+
+		encrypted, err := service.encrypt(secret)
+		if err != nil {
+			return false, err
+		}
+		replace(encryptedPrefix + encrypted)
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// Redact replaces every encrypted or plain secret in cfg with RedactedValue. env and
+// file references are not secret and are left as is.
+func Redact(cfg Config) {
+	for _, f := range cfg.SecretFields() {
+		secret, replace, ok := f.secretPart()
+		if !ok || strings.HasPrefix(secret, envPrefix) || strings.HasPrefix(secret, filePrefix) {
+			continue
+		}
+
+		replace(RedactedValue)
+	}
+}
+
+// ContainsPlain returns true if cfg has any secret that is not in a reference form
+func ContainsPlain(cfg Config) bool {
+	for _, f := range cfg.SecretFields() {
+		secret, _, ok := f.secretPart()
+		if ok && !isReference(secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,203 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testConfig is a provider style config with every kind of secret field
+type testConfig struct {
+	Token       string
+	Environment []string
+	Headers     map[string]string
+}
+
+func (cfg *testConfig) SecretFields() []Field {
+	fields := []Field{{Value: &cfg.Token}}
+	fields = append(fields, KeyValueFields(cfg.Environment)...)
+	fields = append(fields, MapFields(cfg.Headers)...)
+
+	return fields
+}
+
+// newTestService returns a service with a master key in a temp dir
+func newTestService(t *testing.T) *Service {
+	return NewService(filepath.Join(t.TempDir(), "secrets.key"))
+}
+
+func TestSecrets_ProtectResolve(t *testing.T) {
+	service := newTestService(t)
+
+	cfg := &testConfig{
+		Token:       "plain-token",
+		Environment: []string{"API_KEY=plain-api-key", "EMPTY=", "NOVALUE"},
+		Headers:     map[string]string{"Authorization": "Bearer plain"},
+	}
+
+	changed, err := service.Protect(cfg)
+	if err != nil {
+		t.Fatalf("protect failed (%s)", err)
+	}
+	if !changed {
+		t.Error("protect with plain values returned not changed")
+	}
+
+	// everything secret is encrypted, only VALUE of KEY=VALUE
+	if !strings.HasPrefix(cfg.Token, encryptedPrefix) {
+		t.Errorf("protected token is '%s', expected encrypted", cfg.Token)
+	}
+	if !strings.HasPrefix(cfg.Environment[0], "API_KEY="+encryptedPrefix) {
+		t.Errorf("protected environment value is '%s', expected API_KEY=enc:...", cfg.Environment[0])
+	}
+	if cfg.Environment[1] != "EMPTY=" || cfg.Environment[2] != "NOVALUE" {
+		t.Errorf("environment values without a secret were changed (%v)", cfg.Environment[1:])
+	}
+	if !strings.HasPrefix(cfg.Headers["Authorization"], encryptedPrefix) {
+		t.Errorf("protected map value is '%s', expected encrypted", cfg.Headers["Authorization"])
+	}
+	if ContainsPlain(cfg) {
+		t.Error("protected config still contains plain secrets")
+	}
+
+	// already protected, nothing to do
+	encryptedToken := cfg.Token
+	changed, err = service.Protect(cfg)
+	if err != nil || changed {
+		t.Errorf("protect of protected config returned changed %t (err: %v)", changed, err)
+	}
+	if cfg.Token != encryptedToken {
+		t.Error("protect of protected config re-encrypted the token")
+	}
+
+	err = service.Resolve(cfg)
+	if err != nil {
+		t.Fatalf("resolve failed (%s)", err)
+	}
+	if cfg.Token != "plain-token" {
+		t.Errorf("resolved token is '%s', expected 'plain-token'", cfg.Token)
+	}
+	if cfg.Environment[0] != "API_KEY=plain-api-key" {
+		t.Errorf("resolved environment value is '%s', expected 'API_KEY=plain-api-key'", cfg.Environment[0])
+	}
+	if cfg.Headers["Authorization"] != "Bearer plain" {
+		t.Errorf("resolved map value is '%s', expected 'Bearer plain'", cfg.Headers["Authorization"])
+	}
+}
+
+func TestSecrets_References(t *testing.T) {
+	service := newTestService(t)
+
+	t.Setenv("LEGO_TEST_SECRET", "from-env")
+	secretFile := filepath.Join(t.TempDir(), "secret.txt")
+	err := os.WriteFile(secretFile, []byte("  from-file\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &testConfig{
+		Token:       "env:LEGO_TEST_SECRET",
+		Environment: []string{"FROM_FILE=file:" + secretFile},
+		Headers:     map[string]string{"X-Env": "env:LEGO_TEST_SECRET"},
+	}
+
+	// references are not secret, protect and redact leave them as is
+	changed, err := service.Protect(cfg)
+	if err != nil || changed {
+		t.Errorf("protect of references returned changed %t (err: %v)", changed, err)
+	}
+	Redact(cfg)
+	if cfg.Token != "env:LEGO_TEST_SECRET" || cfg.Environment[0] != "FROM_FILE=file:"+secretFile {
+		t.Errorf("redact changed references (%s, %s)", cfg.Token, cfg.Environment[0])
+	}
+
+	err = service.Resolve(cfg)
+	if err != nil {
+		t.Fatalf("resolve of references failed (%s)", err)
+	}
+	if cfg.Token != "from-env" || cfg.Headers["X-Env"] != "from-env" {
+		t.Errorf("env reference resolved to '%s' and '%s', expected 'from-env'", cfg.Token, cfg.Headers["X-Env"])
+	}
+	if cfg.Environment[0] != "FROM_FILE=from-file" {
+		t.Errorf("file reference resolved to '%s', expected 'FROM_FILE=from-file'", cfg.Environment[0])
+	}
+
+	// missing env var and file
+	err = service.Resolve(&testConfig{Token: "env:LEGO_TEST_SECRET_NOT_SET"})
+	if err == nil {
+		t.Error("resolve of unset environment variable did not return an error")
+	}
+	err = service.Resolve(&testConfig{Token: "file:" + filepath.Join(t.TempDir(), "missing.txt")})
+	if err == nil {
+		t.Error("resolve of missing file did not return an error")
+	}
+}
+
+func TestSecrets_RedactedValue(t *testing.T) {
+	service := newTestService(t)
+
+	cfg := &testConfig{
+		Token:       "plain-token",
+		Environment: []string{"API_KEY=plain-api-key"},
+		Headers:     map[string]string{"Authorization": "Bearer plain"},
+	}
+	_, err := service.Protect(cfg)
+	if err != nil {
+		t.Fatalf("protect failed (%s)", err)
+	}
+
+	Redact(cfg)
+	if cfg.Token != RedactedValue || cfg.Environment[0] != "API_KEY="+RedactedValue ||
+		cfg.Headers["Authorization"] != RedactedValue {
+		t.Errorf("redacted config is %+v, expected all secrets redacted", cfg)
+	}
+
+	// a redacted value sent back must not be saved or used
+	_, err = service.Protect(cfg)
+	if !errors.Is(err, errRedactedValue) {
+		t.Errorf("protect of redacted value returned error '%v', expected redacted error", err)
+	}
+	err = service.Resolve(cfg)
+	if !errors.Is(err, errRedactedValue) {
+		t.Errorf("resolve of redacted value returned error '%v', expected redacted error", err)
+	}
+}
+
+func TestSecrets_WrongKey(t *testing.T) {
+	service := newTestService(t)
+
+	cfg := &testConfig{Token: "plain-token"}
+	_, err := service.Protect(cfg)
+	if err != nil {
+		t.Fatalf("protect failed (%s)", err)
+	}
+
+	// a different master key can't decrypt
+	otherService := newTestService(t)
+	_, err = otherService.Protect(&testConfig{Token: "creates-other-key"})
+	if err != nil {
+		t.Fatalf("protect with other key failed (%s)", err)
+	}
+	err = otherService.Resolve(&testConfig{Token: cfg.Token})
+	if !errors.Is(err, errDecrypt) {
+		t.Errorf("resolve with wrong key returned error '%v', expected decrypt error", err)
+	}
+
+	// tampered ciphertext
+	err = service.Resolve(&testConfig{Token: cfg.Token + "AA"})
+	if !errors.Is(err, errDecrypt) {
+		t.Errorf("resolve of tampered value returned error '%v', expected decrypt error", err)
+	}
+
+	// missing key file is not created on resolve and the error names the file
+	missingService := newTestService(t)
+	err = missingService.Resolve(&testConfig{Token: cfg.Token})
+	if err == nil || !strings.Contains(err.Error(), missingService.keyPath) {
+		t.Errorf("resolve with missing key returned error '%v', expected missing key error", err)
+	}
+	if _, statErr := os.Stat(missingService.keyPath); statErr == nil {
+		t.Error("resolve with missing key created a new key file")
+	}
+}
@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"legocerthub-backend/pkg/randomness"
+	"os"
+	"strings"
+	"sync"
+)
+
+var errDecrypt = errors.New("failed to decrypt secret (is the master key file the one it was encrypted with?)")
+
+// Service encrypts and decrypts secrets using the master key file
+type Service struct {
+	keyPath string
+	gcm     cipher.AEAD
+	mu      sync.Mutex
+}
+
+// NewService creates a new service that uses the master key at keyPath. The key
+// is not read until it is needed and is created if it does not exist when
+// something is encrypted.
+func NewService(keyPath string) *Service {
+	return &Service{
+		keyPath: keyPath,
+	}
+}
+
+// loadKey reads the master key and creates the AEAD. If the key file does not
+// exist and create is true, a new key is generated and saved.
+func (service *Service) loadKey(create bool) (cipher.AEAD, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	// already loaded
+	if service.gcm != nil {
+		return service.gcm, nil
+	}
+
+	keyB64, err := os.ReadFile(service.keyPath)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		// make new key
+		newKey, genErr := randomness.GenerateAES256KeyAsBase64RawUrl()
+		if genErr != nil {
+			return nil, genErr
+		}
+
+		// O_EXCL so an existing key is never overwritten
+		f, openErr := os.OpenFile(service.keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if openErr != nil {
+			return nil, fmt.Errorf("failed to create secrets master key file (%s)", openErr)
+		}
+		_, err = f.WriteString(newKey)
+		closeErr := f.Close()
+		if err != nil || closeErr != nil {
+			return nil, fmt.Errorf("failed to write secrets master key file (%s)", errors.Join(err, closeErr))
+		}
+
+		keyB64 = []byte(newKey)
+	} else if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("secrets master key file %s is missing, encrypted secrets can't be decrypted "+
+			"(the key is not included in backups and must be restored separately)", service.keyPath)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read secrets master key file %s (%s)", service.keyPath, err)
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(string(keyB64)))
+	if err != nil {
+		return nil, fmt.Errorf("secrets master key file is malformed (%s)", err)
+	}
+
+	aes, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets master key is invalid (%s)", err)
+	}
+
+	gcm, err := cipher.NewGCM(aes)
+	if err != nil {
+		return nil, err
+	}
+
+	service.gcm = gcm
+	return gcm, nil
+}
+
+// encrypt encrypts value with the master key and returns the base64 raw url encoded
+// nonce + ciphertext
+func (service *Service) encrypt(value string) (string, error) {
+	gcm, err := service.loadKey(true)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	// note: dst==nonce on purpose (so nonce is prepended)
+	encrypted := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return base64.RawURLEncoding.EncodeToString(encrypted), nil
+}
+
+// decrypt reverses encrypt
+func (service *Service) decrypt(encoded string) (string, error) {
+	gcm, err := service.loadKey(false)
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(encrypted) < gcm.NonceSize() {
+		return "", errDecrypt
+	}
+
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	value, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errDecrypt
+	}
+
+	return string(value), nil
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/domain/authorizations"
+)
+
+// authorizationDb is a single acme authorization, as database table fields
+// corresponds to authorizations.Authorization
+type authorizationDb struct {
+	id              int
+	accountId       int
+	location        string
+	identifierType  string
+	identifierValue string
+	status          string
+	expires         sql.NullInt32
+	challengeType   sql.NullString
+	providerId      sql.NullInt32
+	providerType    sql.NullString
+	createdAt       int
+	updatedAt       int
+}
+
+func (auth authorizationDb) toAuthorization() authorizations.Authorization {
+	// solved by (only if recorded)
+	var solvedBy *authorizations.SolvedBy
+	if auth.challengeType.Valid && auth.providerId.Valid {
+		solvedBy = &authorizations.SolvedBy{
+			ChallengeType: auth.challengeType.String,
+			ProviderID:    int(auth.providerId.Int32),
+			ProviderType:  auth.providerType.String,
+		}
+	}
+
+	return authorizations.Authorization{
+		ID:              auth.id,
+		AccountID:       auth.accountId,
+		Location:        auth.location,
+		IdentifierType:  auth.identifierType,
+		IdentifierValue: auth.identifierValue,
+		Status:          auth.status,
+		Expires:         nullInt32ToInt(auth.expires),
+		SolvedBy:        solvedBy,
+		CreatedAt:       auth.createdAt,
+		UpdatedAt:       auth.updatedAt,
+	}
+}
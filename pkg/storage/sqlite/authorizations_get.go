@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/authorizations"
+)
+
+// GetAccountAuthorizations returns all of the stored authorizations for the specified
+// account, newest first
+func (store *Storage) GetAccountAuthorizations(accountId int) ([]authorizations.Authorization, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		id, acme_account_id, acme_location, identifier_type, identifier_value, status, expires,
+		challenge_type, challenge_provider_id, challenge_provider_type, created_at, updated_at
+	FROM
+		acme_authorizations
+	WHERE
+		acme_account_id = $1
+	ORDER BY
+		created_at DESC
+	`
+
+	rows, err := store.db.QueryContext(ctx, query, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	auths := []authorizations.Authorization{}
+	for rows.Next() {
+		var oneAuth authorizationDb
+		err = rows.Scan(
+			&oneAuth.id,
+			&oneAuth.accountId,
+			&oneAuth.location,
+			&oneAuth.identifierType,
+			&oneAuth.identifierValue,
+			&oneAuth.status,
+			&oneAuth.expires,
+			&oneAuth.challengeType,
+			&oneAuth.providerId,
+			&oneAuth.providerType,
+			&oneAuth.createdAt,
+			&oneAuth.updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		auths = append(auths, oneAuth.toAuthorization())
+	}
+
+	return auths, nil
+}
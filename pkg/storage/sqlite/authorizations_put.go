@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/authorizations"
+)
+
+// PutAuthorization saves the authorization to the db. If an authorization with the same
+// location already exists, its status and expiration are updated (and how it was solved,
+// if specified).
+func (store *Storage) PutAuthorization(payload authorizations.AuthorizationPayload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// solved by (optional)
+	var challengeType, providerType *string
+	var providerId *int
+	if payload.SolvedBy != nil {
+		challengeType = &payload.SolvedBy.ChallengeType
+		providerId = &payload.SolvedBy.ProviderID
+		providerType = &payload.SolvedBy.ProviderType
+	}
+
+	query := `
+	INSERT INTO acme_authorizations (acme_account_id, acme_location, identifier_type, identifier_value,
+		status, expires, challenge_type, challenge_provider_id, challenge_provider_type, created_at,
+		updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (acme_location) DO UPDATE SET
+		status = excluded.status,
+		expires = excluded.expires,
+		challenge_type = COALESCE(excluded.challenge_type, challenge_type),
+		challenge_provider_id = COALESCE(excluded.challenge_provider_id, challenge_provider_id),
+		challenge_provider_type = COALESCE(excluded.challenge_provider_type, challenge_provider_type),
+		updated_at = excluded.updated_at
+	`
+
+	_, err := store.db.ExecContext(ctx, query,
+		payload.AccountID,
+		payload.Location,
+		payload.IdentifierType,
+		payload.IdentifierValue,
+		payload.Status,
+		payload.Expires,
+		challengeType,
+		providerId,
+		providerType,
+		payload.CreatedAt,
+		payload.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -28,6 +28,12 @@ type certificateDb struct {
 	postProcessingCommand      string
 	postProcessingEnvironment  jsonStringSlice // stored as json array
 	postProcessingClientKeyB64 string          // base64 raw url encoded AES 256 key
+	preferredIssuer            string
+	profile                    string
+	starLifetime               int
+	starDuration               int
+	starStartOffset            int
+	starLifetimeAdjust         int
 }
 
 func (cert certificateDb) toCertificate() (certificates.Certificate, error) {
@@ -58,5 +64,11 @@ func (cert certificateDb) toCertificate() (certificates.Certificate, error) {
 		PostProcessingCommand:      cert.postProcessingCommand,
 		PostProcessingEnvironment:  cert.postProcessingEnvironment.toSlice(),
 		PostProcessingClientKeyB64: cert.postProcessingClientKeyB64,
+		PreferredIssuer:            cert.preferredIssuer,
+		Profile:                    cert.profile,
+		StarLifetime:               cert.starLifetime,
+		StarDuration:               cert.starDuration,
+		StarStartOffset:            cert.starStartOffset,
+		StarLifetimeAdjust:         cert.starLifetimeAdjust,
 	}, nil
 }
@@ -47,7 +47,8 @@ func (store *Storage) GetAllCerts(q pagination_sort.Query) (certs []certificates
 		c.id, c.name, c.description, c.subject, c.subject_alts, 
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		pk.id, pk.name, pk.description, pk.algorithm, pk.pem, pk.api_key, pk.api_key_new,
 		pk.api_key_disabled, pk.api_key_via_url, pk.created_at, pk.updated_at,
@@ -111,6 +112,12 @@ func (store *Storage) GetAllCerts(q pagination_sort.Query) (certs []certificates
 			&oneCert.postProcessingCommand,
 			&oneCert.postProcessingEnvironment,
 			&oneCert.postProcessingClientKeyB64,
+			&oneCert.preferredIssuer,
+			&oneCert.profile,
+			&oneCert.starLifetime,
+			&oneCert.starDuration,
+			&oneCert.starStartOffset,
+			&oneCert.starLifetimeAdjust,
 
 			&oneCert.certificateKeyDb.id,
 			&oneCert.certificateKeyDb.name,
@@ -192,7 +199,8 @@ func (store *Storage) getOneCert(id int, name string) (cert certificates.Certifi
 		c.id, c.name, c.description, c.subject, c.subject_alts,
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		pk.id, pk.name, pk.description, pk.algorithm, pk.pem, pk.api_key, pk.api_key_new,
 		pk.api_key_disabled, pk.api_key_via_url, pk.created_at, pk.updated_at,
@@ -240,6 +248,12 @@ func (store *Storage) getOneCert(id int, name string) (cert certificates.Certifi
 		&oneCert.postProcessingCommand,
 		&oneCert.postProcessingEnvironment,
 		&oneCert.postProcessingClientKeyB64,
+		&oneCert.preferredIssuer,
+		&oneCert.profile,
+		&oneCert.starLifetime,
+		&oneCert.starDuration,
+		&oneCert.starStartOffset,
+		&oneCert.starLifetimeAdjust,
 
 		&oneCert.certificateKeyDb.id,
 		&oneCert.certificateKeyDb.name,
@@ -18,8 +18,10 @@ func (store *Storage) PostNewCert(payload certificates.NewPayload) (certificates
 	query := `
 	INSERT INTO certificates (name, description, private_key_id, acme_account_id, subject, subject_alts, 
 		csr_org, csr_ou, csr_country, csr_state, csr_city, csr_extra_extensions, created_at, updated_at, api_key, api_key_via_url,
-		post_processing_command, post_processing_environment, post_processing_client_key)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		post_processing_command, post_processing_environment, post_processing_client_key, preferred_issuer,
+		profile, star_lifetime, star_duration, star_start_offset, star_lifetime_adjust)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23,
+		$24, $25)
 	RETURNING id
 	`
 
@@ -44,6 +46,12 @@ func (store *Storage) PostNewCert(payload certificates.NewPayload) (certificates
 		payload.PostProcessingCommand,
 		makeJsonStringSlice(payload.PostProcessingEnvironment),
 		payload.PostProcessingClientKeyB64,
+		payload.PreferredIssuer,
+		payload.Profile,
+		payload.StarLifetime,
+		payload.StarDuration,
+		payload.StarStartOffset,
+		payload.StarLifetimeAdjust,
 	).Scan(&id)
 
 	if err != nil {
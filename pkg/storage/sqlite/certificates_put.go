@@ -32,9 +32,15 @@ func (store *Storage) PutDetailsCert(payload certificates.DetailsUpdatePayload)
 			api_key_via_url = case when $13 is null then api_key_via_url else $13 end,
 			post_processing_command = case when $14 is null then post_processing_command else $14 end,
 			post_processing_environment = case when $15 is null then post_processing_environment else $15 end,
-			updated_at = $16
+			preferred_issuer = case when $16 is null then preferred_issuer else $16 end,
+			profile = case when $17 is null then profile else $17 end,
+			star_lifetime = case when $18 is null then star_lifetime else $18 end,
+			star_duration = case when $19 is null then star_duration else $19 end,
+			star_start_offset = case when $20 is null then star_start_offset else $20 end,
+			star_lifetime_adjust = case when $21 is null then star_lifetime_adjust else $21 end,
+			updated_at = $22
 		WHERE
-			id = $17
+			id = $23
 		`
 
 	_, err := store.db.ExecContext(ctx, query,
@@ -53,6 +59,12 @@ func (store *Storage) PutDetailsCert(payload certificates.DetailsUpdatePayload)
 		payload.ApiKeyViaUrl,
 		payload.PostProcessingCommand,
 		makeJsonStringSlice(payload.PostProcessingEnvironment),
+		payload.PreferredIssuer,
+		payload.Profile,
+		payload.StarLifetime,
+		payload.StarDuration,
+		payload.StarStartOffset,
+		payload.StarLifetimeAdjust,
 		payload.UpdatedAt,
 		payload.ID,
 	)
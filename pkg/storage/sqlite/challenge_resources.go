@@ -0,0 +1,29 @@
+package sqlite
+
+import "legocerthub-backend/pkg/challenges"
+
+// challengeResourceDb is a single journaled challenge resource, as database table fields
+// corresponds to challenges.ProvisionedResource
+type challengeResourceDb struct {
+	id           int
+	providerId   int
+	providerKey  string
+	providerType string
+	domain       string
+	token        string
+	keyAuth      string
+	createdAt    int
+}
+
+func (res challengeResourceDb) toProvisionedResource() challenges.ProvisionedResource {
+	return challenges.ProvisionedResource{
+		ID:           res.id,
+		ProviderID:   res.providerId,
+		ProviderKey:  res.providerKey,
+		ProviderType: res.providerType,
+		Domain:       res.domain,
+		Token:        res.token,
+		KeyAuth:      res.keyAuth,
+		CreatedAt:    res.createdAt,
+	}
+}
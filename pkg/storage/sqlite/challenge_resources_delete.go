@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// DeleteChallengeResource removes a challenge resource from the journal
+func (store *Storage) DeleteChallengeResource(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		challenge_resources
+	WHERE
+		id = $1
+	`
+
+	_, err := store.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
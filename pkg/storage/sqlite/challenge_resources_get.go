@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// GetAllChallengeResources returns all of the journaled challenge resources, oldest first
+func (store *Storage) GetAllChallengeResources() ([]challenges.ProvisionedResource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		id, provider_id, provider_key, provider_type, domain, token, key_auth, created_at
+	FROM
+		challenge_resources
+	ORDER BY
+		id ASC
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := []challenges.ProvisionedResource{}
+	for rows.Next() {
+		var oneRes challengeResourceDb
+		err = rows.Scan(
+			&oneRes.id,
+			&oneRes.providerId,
+			&oneRes.providerKey,
+			&oneRes.providerType,
+			&oneRes.domain,
+			&oneRes.token,
+			&oneRes.keyAuth,
+			&oneRes.createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, oneRes.toProvisionedResource())
+	}
+
+	return resources, nil
+}
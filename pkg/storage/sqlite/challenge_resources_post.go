@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// PostChallengeResource journals a challenge resource and returns its id
+func (store *Storage) PostChallengeResource(res challenges.ProvisionedResource) (id int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO challenge_resources (provider_id, provider_key, provider_type, domain, token, key_auth,
+		created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id
+	`
+
+	// insert and scan the new id
+	id = -1
+	err = store.db.QueryRowContext(ctx, query,
+		res.ProviderID,
+		res.ProviderKey,
+		res.ProviderType,
+		res.Domain,
+		res.Token,
+		res.KeyAuth,
+		res.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
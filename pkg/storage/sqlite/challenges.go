@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"database/sql"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// challengeEventDb is a single challenge solving step, as database table fields
+// corresponds to challenges.Event
+type challengeEventDb struct {
+	id              int
+	attemptId       string
+	providerId      int
+	providerKey     string
+	providerType    string
+	challengeType   string
+	identifierType  string
+	identifierValue string
+	step            string
+	success         bool
+	errorMessage    string
+	startedAt       int
+	durationMs      int
+}
+
+func (event challengeEventDb) toEvent() challenges.Event {
+	return challenges.Event{
+		ID:              event.id,
+		AttemptID:       event.attemptId,
+		ProviderID:      event.providerId,
+		ProviderKey:     event.providerKey,
+		ProviderType:    event.providerType,
+		ChallengeType:   event.challengeType,
+		IdentifierType:  event.identifierType,
+		IdentifierValue: event.identifierValue,
+		Step:            event.step,
+		Success:         event.success,
+		Error:           event.errorMessage,
+		StartedAt:       event.startedAt,
+		DurationMs:      event.durationMs,
+	}
+}
+
+// providerStatsDb is one provider's challenge history summary, as database fields
+// corresponds to challenges.ProviderStats
+type providerStatsDb struct {
+	providerKey         string
+	providerType        string
+	attempts            int
+	successfulAttempts  int
+	provisionFailures   int
+	propagationFailures int
+	validationFailures  int
+	deprovisionFailures int
+	avgPropagationMs    sql.NullFloat64
+	lastAttemptAt       int
+}
+
+func (stats providerStatsDb) toProviderStats() challenges.ProviderStats {
+	// success rate
+	successRate := 0.0
+	if stats.attempts > 0 {
+		successRate = float64(stats.successfulAttempts) / float64(stats.attempts)
+	}
+
+	// average propagation (only if there were any successful propagation checks)
+	var avgPropagationMs *float64
+	if stats.avgPropagationMs.Valid {
+		avgPropagationMs = &stats.avgPropagationMs.Float64
+	}
+
+	return challenges.ProviderStats{
+		ProviderKey:         stats.providerKey,
+		ProviderType:        stats.providerType,
+		Attempts:            stats.attempts,
+		SuccessfulAttempts:  stats.successfulAttempts,
+		SuccessRate:         successRate,
+		ProvisionFailures:   stats.provisionFailures,
+		PropagationFailures: stats.propagationFailures,
+		ValidationFailures:  stats.validationFailures,
+		DeprovisionFailures: stats.deprovisionFailures,
+		AvgPropagationMs:    avgPropagationMs,
+		LastAttemptAt:       stats.lastAttemptAt,
+	}
+}
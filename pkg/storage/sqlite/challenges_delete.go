@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// DeleteChallengeEventsBefore removes the challenge history events that started before
+// before (unix time) and returns how many were removed
+func (store *Storage) DeleteChallengeEventsBefore(before int) (count int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		challenge_events
+	WHERE
+		started_at < $1
+	`
+
+	result, err := store.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+	"legocerthub-backend/pkg/pagination_sort"
+)
+
+// GetChallengeEvents returns the challenge history events that match the filter, newest
+// first
+func (store *Storage) GetChallengeEvents(filter challenges.EventFilter, q pagination_sort.Query) (events []challenges.Event, totalRowCount int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// provider id -1 does not filter (ids start at 0)
+	providerId := -1
+	if filter.ProviderID != nil {
+		providerId = *filter.ProviderID
+	}
+
+	query := `
+	SELECT
+		id, attempt_id, provider_id, provider_key, provider_type, challenge_type,
+		identifier_type, identifier_value, step, success, error, started_at, duration_ms,
+
+		count(*) OVER() AS full_count
+	FROM
+		challenge_events
+	WHERE
+		($1 = -1 OR provider_id = $1)
+		AND
+		($2 = "" OR provider_key = $2)
+		AND
+		($3 = "" OR identifier_value = $3)
+		AND
+		($4 = "" OR step = $4)
+	ORDER BY
+		id DESC
+	LIMIT
+		$5
+	OFFSET
+		$6
+	`
+
+	rows, err := store.db.QueryContext(ctx, query,
+		providerId,
+		filter.ProviderKey,
+		filter.IdentifierValue,
+		filter.Step,
+		q.Limit(),
+		q.Offset(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	// for total row count
+	var totalRows int
+
+	events = []challenges.Event{}
+	for rows.Next() {
+		var oneEvent challengeEventDb
+		err = rows.Scan(
+			&oneEvent.id,
+			&oneEvent.attemptId,
+			&oneEvent.providerId,
+			&oneEvent.providerKey,
+			&oneEvent.providerType,
+			&oneEvent.challengeType,
+			&oneEvent.identifierType,
+			&oneEvent.identifierValue,
+			&oneEvent.step,
+			&oneEvent.success,
+			&oneEvent.errorMessage,
+			&oneEvent.startedAt,
+			&oneEvent.durationMs,
+
+			&totalRows,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		events = append(events, oneEvent.toEvent())
+	}
+
+	return events, totalRows, nil
+}
+
+// GetChallengeProviderStats summarizes the challenge history of each provider (by key), using
+// only the events that started at or after since (unix time)
+func (store *Storage) GetChallengeProviderStats(since int) ([]challenges.ProviderStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		provider_key,
+		provider_type,
+		count(DISTINCT attempt_id),
+		count(DISTINCT CASE WHEN step = "validation" AND success = 1 THEN attempt_id END),
+		sum(CASE WHEN step = "provision" AND success = 0 THEN 1 ELSE 0 END),
+		sum(CASE WHEN step = "propagation" AND success = 0 THEN 1 ELSE 0 END),
+		sum(CASE WHEN step = "validation" AND success = 0 THEN 1 ELSE 0 END),
+		sum(CASE WHEN step = "deprovision" AND success = 0 THEN 1 ELSE 0 END),
+		avg(CASE WHEN step = "propagation" AND success = 1 THEN duration_ms END),
+		max(started_at)
+	FROM
+		challenge_events
+	WHERE
+		started_at >= $1
+	GROUP BY
+		provider_key, provider_type
+	ORDER BY
+		provider_key, provider_type
+	`
+
+	rows, err := store.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allStats := []challenges.ProviderStats{}
+	for rows.Next() {
+		var oneStats providerStatsDb
+		err = rows.Scan(
+			&oneStats.providerKey,
+			&oneStats.providerType,
+			&oneStats.attempts,
+			&oneStats.successfulAttempts,
+			&oneStats.provisionFailures,
+			&oneStats.propagationFailures,
+			&oneStats.validationFailures,
+			&oneStats.deprovisionFailures,
+			&oneStats.avgPropagationMs,
+			&oneStats.lastAttemptAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		allStats = append(allStats, oneStats.toProviderStats())
+	}
+
+	return allStats, nil
+}
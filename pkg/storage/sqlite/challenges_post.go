@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/challenges"
+)
+
+// PostChallengeEvent saves a challenge solving step to the challenge history
+func (store *Storage) PostChallengeEvent(event challenges.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO challenge_events (attempt_id, provider_id, provider_key, provider_type, challenge_type,
+		identifier_type, identifier_value, step, success, error, started_at, duration_ms)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := store.db.ExecContext(ctx, query,
+		event.AttemptID,
+		event.ProviderID,
+		event.ProviderKey,
+		event.ProviderType,
+		event.ChallengeType,
+		event.IdentifierType,
+		event.IdentifierValue,
+		event.Step,
+		event.Success,
+		event.Error,
+		event.StartedAt,
+		event.DurationMs,
+	)
+
+	return err
+}
@@ -18,15 +18,25 @@ type orderDb struct {
 	err            sql.NullString // stored as json object
 	expires        sql.NullInt32
 	dnsIdentifiers jsonStringSlice // stored as json array
+	ipIdentifiers  jsonStringSlice // stored as json array
 	authorizations jsonStringSlice // stored as json array
 	finalize       string
 	finalizedKey   keyDb
 	certificateUrl sql.NullString
 	pem            sql.NullString
+	alternatePems  jsonStringSlice // stored as json array
 	validFrom      sql.NullInt32
 	validTo        sql.NullInt32
 	createdAt      int
 	updatedAt      int
+
+	renewalInfoWindowStart sql.NullInt32
+	renewalInfoWindowEnd   sql.NullInt32
+
+	starCertificateUrl sql.NullString
+	starStart          sql.NullInt32
+	starEnd            sql.NullInt32
+	starLifetime       sql.NullInt32
 }
 
 func (order orderDb) toOrder() (orders.Order, error) {
@@ -58,14 +68,24 @@ func (order orderDb) toOrder() (orders.Order, error) {
 		Error:          acmeErr,
 		Expires:        nullInt32ToInt(order.expires),
 		DnsIdentifiers: order.dnsIdentifiers.toSlice(),
+		IpIdentifiers:  order.ipIdentifiers.toSlice(),
 		Authorizations: order.authorizations.toSlice(),
 		Finalize:       order.finalize,
 		FinalizedKey:   key,
 		CertificateUrl: nullStringToString(order.certificateUrl),
 		Pem:            nullStringToString(order.pem),
+		AlternatePems:  order.alternatePems.toSlice(),
 		ValidFrom:      nullInt32ToInt(order.validFrom),
 		ValidTo:        nullInt32ToInt(order.validTo),
 		CreatedAt:      order.createdAt,
 		UpdatedAt:      order.updatedAt,
+
+		RenewalInfoWindowStart: nullInt32ToInt(order.renewalInfoWindowStart),
+		RenewalInfoWindowEnd:   nullInt32ToInt(order.renewalInfoWindowEnd),
+
+		StarCertificateUrl: nullStringToString(order.starCertificateUrl),
+		StarStart:          nullInt32ToInt(order.starStart),
+		StarEnd:            nullInt32ToInt(order.starEnd),
+		StarLifetime:       nullInt32ToInt(order.starLifetime),
 	}, nil
 }
@@ -42,15 +42,17 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 	query := fmt.Sprintf(`
 	SELECT
 		/* order */
-		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
+		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, ao.ip_identifiers,
 		ao.authorizations, ao.finalize, ao.certificate_url, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.updated_at, ao.renewal_info_window_start, ao.renewal_info_window_end, ao.star_certificate_url,
+		ao.star_start, ao.star_end, ao.star_lifetime,
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts,
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		/* cert's key */
 		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ck.api_key_new,
@@ -131,6 +133,7 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 			&oneOrder.err,
 			&oneOrder.expires,
 			&oneOrder.dnsIdentifiers,
+			&oneOrder.ipIdentifiers,
 			&oneOrder.authorizations,
 			&oneOrder.finalize,
 			&oneOrder.certificateUrl,
@@ -138,6 +141,12 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 			&oneOrder.validTo,
 			&oneOrder.createdAt,
 			&oneOrder.updatedAt,
+			&oneOrder.renewalInfoWindowStart,
+			&oneOrder.renewalInfoWindowEnd,
+			&oneOrder.starCertificateUrl,
+			&oneOrder.starStart,
+			&oneOrder.starEnd,
+			&oneOrder.starLifetime,
 
 			&oneOrder.certificate.id,
 			&oneOrder.certificate.name,
@@ -158,6 +167,12 @@ func (store *Storage) GetAllValidCurrentOrders(q pagination_sort.Query) (orders
 			&oneOrder.certificate.postProcessingCommand,
 			&oneOrder.certificate.postProcessingEnvironment,
 			&oneOrder.certificate.postProcessingClientKeyB64,
+			&oneOrder.certificate.preferredIssuer,
+			&oneOrder.certificate.profile,
+			&oneOrder.certificate.starLifetime,
+			&oneOrder.certificate.starDuration,
+			&oneOrder.certificate.starStartOffset,
+			&oneOrder.certificate.starLifetimeAdjust,
 
 			&oneOrder.certificate.certificateKeyDb.id,
 			&oneOrder.certificate.certificateKeyDb.name,
@@ -262,15 +277,17 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 	query := fmt.Sprintf(`
 	SELECT
 		/* order */
-		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
-		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, ao.ip_identifiers,
+		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.alternate_pems, ao.valid_from, ao.valid_to, ao.created_at,
+		ao.updated_at, ao.renewal_info_window_start, ao.renewal_info_window_end, ao.star_certificate_url,
+		ao.star_start, ao.star_end, ao.star_lifetime,
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts,
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		/* cert's key */
 		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ck.api_key_new, ck.api_key_disabled,
@@ -337,14 +354,22 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 			&oneOrder.err,
 			&oneOrder.expires,
 			&oneOrder.dnsIdentifiers,
+			&oneOrder.ipIdentifiers,
 			&oneOrder.authorizations,
 			&oneOrder.finalize,
 			&oneOrder.certificateUrl,
 			&oneOrder.pem,
+			&oneOrder.alternatePems,
 			&oneOrder.validFrom,
 			&oneOrder.validTo,
 			&oneOrder.createdAt,
 			&oneOrder.updatedAt,
+			&oneOrder.renewalInfoWindowStart,
+			&oneOrder.renewalInfoWindowEnd,
+			&oneOrder.starCertificateUrl,
+			&oneOrder.starStart,
+			&oneOrder.starEnd,
+			&oneOrder.starLifetime,
 
 			&oneOrder.certificate.id,
 			&oneOrder.certificate.name,
@@ -365,6 +390,12 @@ func (store *Storage) GetOrdersByCert(certId int, q pagination_sort.Query) (orde
 			&oneOrder.certificate.postProcessingCommand,
 			&oneOrder.certificate.postProcessingEnvironment,
 			&oneOrder.certificate.postProcessingClientKeyB64,
+			&oneOrder.certificate.preferredIssuer,
+			&oneOrder.certificate.profile,
+			&oneOrder.certificate.starLifetime,
+			&oneOrder.certificate.starDuration,
+			&oneOrder.certificate.starStartOffset,
+			&oneOrder.certificate.starLifetimeAdjust,
 
 			&oneOrder.certificate.certificateKeyDb.id,
 			&oneOrder.certificate.certificateKeyDb.name,
@@ -535,6 +566,102 @@ func (store *Storage) GetExpiringCertIds(maxTimeRemaining time.Duration) (certId
 	return certIds, nil
 }
 
+// GetCurrentValidOrderIds returns a slice of order ids, one for each certificate that currently
+// has a valid order. The id returned is the cert's most recent valid order.
+func (store *Storage) GetCurrentValidOrderIds() (orderIds []int, err error) {
+	// query
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			ao.id
+		FROM
+			acme_orders ao
+		WHERE 
+			ao.status = "valid"
+			AND
+			ao.known_revoked = 0
+			AND
+			ao.valid_to > $1
+			AND
+			ao.pem NOT NULL
+			AND
+			ao.certificate_id IS NOT NULL
+		GROUP BY
+			ao.certificate_id
+		HAVING
+			MAX(ao.valid_to)
+		`
+
+	// get records
+	rows, err := store.db.QueryContext(ctx, query,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId int
+
+		err = rows.Scan(&orderId)
+		if err != nil {
+			return nil, err
+		}
+
+		orderIds = append(orderIds, orderId)
+	}
+
+	return orderIds, nil
+}
+
+// GetActiveStarOrderIds returns a slice of order ids for all valid STAR (auto-renewal) orders
+// whose auto-renewal period has not ended
+func (store *Storage) GetActiveStarOrderIds() (orderIds []int, err error) {
+	// query
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			ao.id
+		FROM
+			acme_orders ao
+		WHERE
+			ao.status = "valid"
+			AND
+			ao.known_revoked = 0
+			AND
+			ao.star_certificate_url NOT NULL
+			AND
+			ao.star_end > $1
+		`
+
+	// get records
+	rows, err := store.db.QueryContext(ctx, query,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderId int
+
+		err = rows.Scan(&orderId)
+		if err != nil {
+			return nil, err
+		}
+
+		orderIds = append(orderIds, orderId)
+	}
+
+	return orderIds, nil
+}
+
 // GetNewestIncompleteCertOrderId returns the most recent incomplete order for a specified certId,
 // assuming there is one.
 func (store *Storage) GetNewestIncompleteCertOrderId(certId int) (orderId int, err error) {
@@ -579,6 +706,35 @@ func (store *Storage) GetNewestIncompleteCertOrderId(certId int) (orderId int, e
 	return orderId, nil
 }
 
+// GetOrderIdByLocation returns the id of the order with the specified ACME location
+func (store *Storage) GetOrderIdByLocation(location string) (orderId int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		id
+	FROM
+		acme_orders
+	WHERE
+		acme_location = $1
+	`
+
+	row := store.db.QueryRowContext(ctx, query, location)
+
+	err = row.Scan(
+		&orderId,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrNoRecord
+		}
+		return -2, err
+	}
+
+	return orderId, nil
+}
+
 // GetOrders fetches the Order for each ID in the orderIDs slice and returns the
 // slice of Order
 func (store *Storage) GetOrders(orderIDs []int) (orders []orders.Order, err error) {
@@ -597,15 +753,17 @@ func (store *Storage) GetOrders(orderIDs []int) (orders []orders.Order, err erro
 	query := fmt.Sprintf(`
 	SELECT
 		/* order */
-		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
-		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, ao.ip_identifiers,
+		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.alternate_pems, ao.valid_from, ao.valid_to, ao.created_at,
+		ao.updated_at, ao.renewal_info_window_start, ao.renewal_info_window_end, ao.star_certificate_url,
+		ao.star_start, ao.star_end, ao.star_lifetime,
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts,
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		/* cert's key */
 		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ak.api_key_new, ck.api_key_disabled,
@@ -658,14 +816,22 @@ func (store *Storage) GetOrders(orderIDs []int) (orders []orders.Order, err erro
 			&oneOrder.err,
 			&oneOrder.expires,
 			&oneOrder.dnsIdentifiers,
+			&oneOrder.ipIdentifiers,
 			&oneOrder.authorizations,
 			&oneOrder.finalize,
 			&oneOrder.certificateUrl,
 			&oneOrder.pem,
+			&oneOrder.alternatePems,
 			&oneOrder.validFrom,
 			&oneOrder.validTo,
 			&oneOrder.createdAt,
 			&oneOrder.updatedAt,
+			&oneOrder.renewalInfoWindowStart,
+			&oneOrder.renewalInfoWindowEnd,
+			&oneOrder.starCertificateUrl,
+			&oneOrder.starStart,
+			&oneOrder.starEnd,
+			&oneOrder.starLifetime,
 
 			&oneOrder.certificate.id,
 			&oneOrder.certificate.name,
@@ -686,6 +852,12 @@ func (store *Storage) GetOrders(orderIDs []int) (orders []orders.Order, err erro
 			&oneOrder.certificate.postProcessingCommand,
 			&oneOrder.certificate.postProcessingEnvironment,
 			&oneOrder.certificate.postProcessingClientKeyB64,
+			&oneOrder.certificate.preferredIssuer,
+			&oneOrder.certificate.profile,
+			&oneOrder.certificate.starLifetime,
+			&oneOrder.certificate.starDuration,
+			&oneOrder.certificate.starStartOffset,
+			&oneOrder.certificate.starLifetimeAdjust,
 
 			&oneOrder.certificate.certificateKeyDb.id,
 			&oneOrder.certificate.certificateKeyDb.name,
@@ -794,15 +966,17 @@ func (store *Storage) getCertNewestValidOrder(certId int, certName string) (orde
 	query := `
 	SELECT
 		/* order */
-		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, 
-		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.valid_from, ao.valid_to, ao.created_at,
-		ao.updated_at, 
+		ao.id, ao.acme_location, ao.status, ao.known_revoked, ao.error, ao.expires, ao.dns_identifiers, ao.ip_identifiers,
+		ao.authorizations, ao.finalize, ao.certificate_url, ao.pem, ao.alternate_pems, ao.valid_from, ao.valid_to, ao.created_at,
+		ao.updated_at, ao.renewal_info_window_start, ao.renewal_info_window_end, ao.star_certificate_url,
+		ao.star_start, ao.star_end, ao.star_lifetime,
 
 		/* order's cert */
 		c.id, c.name, c.description, c.subject, c.subject_alts,
 		c.csr_org, c.csr_ou, c.csr_country, c.csr_state, c.csr_city, c.csr_extra_extensions, c.created_at, c.updated_at,
 		c.api_key, c.api_key_new, c.api_key_via_url, c.post_processing_command, c.post_processing_environment,
-		c.post_processing_client_key,
+		c.post_processing_client_key, c.preferred_issuer, c.profile, c.star_lifetime, c.star_duration,
+		c.star_start_offset, c.star_lifetime_adjust,
 		
 		/* cert's key */
 		ck.id, ck.name, ck.description, ck.algorithm, ck.pem, ck.api_key, ak.api_key_new, ck.api_key_disabled,
@@ -870,14 +1044,22 @@ func (store *Storage) getCertNewestValidOrder(certId int, certName string) (orde
 		&oneOrder.err,
 		&oneOrder.expires,
 		&oneOrder.dnsIdentifiers,
+		&oneOrder.ipIdentifiers,
 		&oneOrder.authorizations,
 		&oneOrder.finalize,
 		&oneOrder.certificateUrl,
 		&oneOrder.pem,
+		&oneOrder.alternatePems,
 		&oneOrder.validFrom,
 		&oneOrder.validTo,
 		&oneOrder.createdAt,
 		&oneOrder.updatedAt,
+		&oneOrder.renewalInfoWindowStart,
+		&oneOrder.renewalInfoWindowEnd,
+		&oneOrder.starCertificateUrl,
+		&oneOrder.starStart,
+		&oneOrder.starEnd,
+		&oneOrder.starLifetime,
 
 		&oneOrder.certificate.id,
 		&oneOrder.certificate.name,
@@ -898,6 +1080,12 @@ func (store *Storage) getCertNewestValidOrder(certId int, certName string) (orde
 		&oneOrder.certificate.postProcessingCommand,
 		&oneOrder.certificate.postProcessingEnvironment,
 		&oneOrder.certificate.postProcessingClientKeyB64,
+		&oneOrder.certificate.preferredIssuer,
+		&oneOrder.certificate.profile,
+		&oneOrder.certificate.starLifetime,
+		&oneOrder.certificate.starDuration,
+		&oneOrder.certificate.starStartOffset,
+		&oneOrder.certificate.starLifetimeAdjust,
 
 		&oneOrder.certificate.certificateKeyDb.id,
 		&oneOrder.certificate.certificateKeyDb.name,
@@ -50,12 +50,16 @@ func (store *Storage) PostNewOrder(payload orders.NewOrderAcmePayload) (newId in
 				known_revoked,
 				expires,
 				dns_identifiers,
+				ip_identifiers,
 				error,
 				authorizations,
 				finalize,
 				acme_location,
 				created_at,
-				updated_at
+				updated_at,
+				star_start,
+				star_end,
+				star_lifetime
 			)
 	VALUES
 			(
@@ -70,7 +74,11 @@ func (store *Storage) PostNewOrder(payload orders.NewOrderAcmePayload) (newId in
 				$9,
 				$10,
 				$11,
-				$12
+				$12,
+				$13,
+				$14,
+				$15,
+				$16
 			)
 	RETURNING
 		id
@@ -83,12 +91,16 @@ func (store *Storage) PostNewOrder(payload orders.NewOrderAcmePayload) (newId in
 		payload.KnownRevoked,
 		payload.Expires,
 		makeJsonStringSlice(payload.DnsIds),
+		makeJsonStringSlice(payload.IpIds),
 		payload.Error,
 		makeJsonStringSlice(payload.Authorizations),
 		payload.Finalize,
 		payload.Location,
 		payload.CreatedAt,
 		payload.UpdatedAt,
+		payload.StarStart,
+		payload.StarEnd,
+		payload.StarLifetime,
 	).Scan(&newId)
 
 	err = tx.Commit()
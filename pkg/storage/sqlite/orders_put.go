@@ -23,9 +23,14 @@ func (store *Storage) PutOrderAcme(payload orders.UpdateAcmeOrderPayload) (err e
 			authorizations = $5,
 			finalize = $6,
 			certificate_url = case when $7 is null then certificate_url else $7 end,
-			updated_at = $8
+			updated_at = $8,
+			ip_identifiers = $9,
+			star_certificate_url = case when $10 is null then star_certificate_url else $10 end,
+			star_start = case when $11 is null then star_start else $11 end,
+			star_end = case when $12 is null then star_end else $12 end,
+			star_lifetime = case when $13 is null then star_lifetime else $13 end
 		WHERE
-			id = $9
+			id = $14
 		`
 
 	_, err = store.db.ExecContext(ctx, query,
@@ -37,6 +42,11 @@ func (store *Storage) PutOrderAcme(payload orders.UpdateAcmeOrderPayload) (err e
 		payload.Finalize,
 		payload.CertificateUrl,
 		payload.UpdatedAt,
+		makeJsonStringSlice(payload.IpIds),
+		payload.StarCertificateUrl,
+		payload.StarStart,
+		payload.StarEnd,
+		payload.StarLifetime,
 		payload.OrderId,
 	)
 
@@ -124,15 +134,17 @@ func (store *Storage) UpdateOrderCert(orderId int, payload orders.CertPayload) (
 			acme_orders
 		SET
 			pem = $1,
-			valid_from = $2,
-			valid_to = $3,
-			updated_at = $4
+			alternate_pems = $2,
+			valid_from = $3,
+			valid_to = $4,
+			updated_at = $5
 		WHERE
-			id = $5
+			id = $6
 		`
 
 	_, err = store.db.ExecContext(ctx, query,
 		payload.Pem,
+		makeJsonStringSlice(payload.AlternatePems),
 		payload.ValidFrom,
 		payload.ValidTo,
 		timeNow(),
@@ -148,6 +160,40 @@ func (store *Storage) UpdateOrderCert(orderId int, payload orders.CertPayload) (
 	return nil
 }
 
+// PutOrderRenewalInfo updates the specified order ID with the ACME Renewal Information
+// (ARI) suggested window
+func (store *Storage) PutOrderRenewalInfo(orderId int, windowStart int, windowEnd int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// no checks or validation (shouldn't be needed)
+
+	// update existing record
+	query := `
+		UPDATE
+			acme_orders
+		SET
+			renewal_info_window_start = $1,
+			renewal_info_window_end = $2
+		WHERE
+			id = $3
+		`
+
+	_, err = store.db.ExecContext(ctx, query,
+		windowStart,
+		windowEnd,
+		orderId,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	// TODO: Handle 0 rows updated.
+
+	return nil
+}
+
 // RevokeOrder updates the revoked flag in db to true (1)
 func (store *Storage) RevokeOrder(orderId int) (err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
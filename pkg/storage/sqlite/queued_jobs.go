@@ -0,0 +1,27 @@
+package sqlite
+
+import "legocerthub-backend/pkg/domain/orders"
+
+// queuedJobDb is a single saved job, as database table fields
+// corresponds to orders.QueuedJob
+type queuedJobDb struct {
+	queue            string
+	orderId          int
+	highPriority     bool
+	running          bool
+	interruptedCount int
+	queuedAt         int
+	notBefore        int
+}
+
+func (job queuedJobDb) toQueuedJob() orders.QueuedJob {
+	return orders.QueuedJob{
+		Queue:            job.queue,
+		OrderID:          job.orderId,
+		HighPriority:     job.highPriority,
+		Running:          job.running,
+		InterruptedCount: job.interruptedCount,
+		QueuedAt:         job.queuedAt,
+		NotBefore:        job.notBefore,
+	}
+}
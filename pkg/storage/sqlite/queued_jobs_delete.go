@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"context"
+)
+
+// DeleteQueuedJob removes a saved job
+func (store *Storage) DeleteQueuedJob(queue string, orderId int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	DELETE FROM
+		queued_jobs
+	WHERE
+		queue = $1
+		AND
+		order_id = $2
+	`
+
+	_, err = store.db.ExecContext(ctx, query, queue, orderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
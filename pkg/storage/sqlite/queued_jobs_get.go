@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// GetAllQueuedJobs returns all of the saved jobs. Jobs that were running are first, then
+// high priority jobs, then low priority jobs; each in the order they were queued.
+func (store *Storage) GetAllQueuedJobs() (jobs []orders.QueuedJob, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	SELECT
+		queue, order_id, high_priority, running, interrupted_count, queued_at, not_before
+	FROM
+		queued_jobs
+	ORDER BY
+		running DESC,
+		high_priority DESC,
+		queued_at ASC,
+		id ASC
+	`
+
+	rows, err := store.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs = []orders.QueuedJob{}
+	for rows.Next() {
+		var oneJob queuedJobDb
+		err = rows.Scan(
+			&oneJob.queue,
+			&oneJob.orderId,
+			&oneJob.highPriority,
+			&oneJob.running,
+			&oneJob.interruptedCount,
+			&oneJob.queuedAt,
+			&oneJob.notBefore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, oneJob.toQueuedJob())
+	}
+
+	return jobs, nil
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// PostQueuedJob saves a new job as waiting in its queue. If the job is already saved,
+// the saved job is not changed.
+func (store *Storage) PostQueuedJob(job orders.QueuedJob) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO queued_jobs (queue, order_id, high_priority, running, interrupted_count, queued_at, not_before)
+	VALUES ($1, $2, $3, 0, 0, $4, $5)
+	ON CONFLICT (queue, order_id) DO NOTHING
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		job.Queue,
+		job.OrderID,
+		job.HighPriority,
+		job.QueuedAt,
+		job.NotBefore,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"legocerthub-backend/pkg/domain/orders"
+)
+
+// PutQueuedJob saves a job as waiting in its queue. If the job is already saved, it is
+// updated (and no longer marked as running).
+func (store *Storage) PutQueuedJob(job orders.QueuedJob) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	INSERT INTO queued_jobs (queue, order_id, high_priority, running, interrupted_count, queued_at, not_before)
+	VALUES ($1, $2, $3, 0, $4, $5, $6)
+	ON CONFLICT (queue, order_id) DO UPDATE SET
+		high_priority = excluded.high_priority,
+		running = 0,
+		interrupted_count = excluded.interrupted_count,
+		queued_at = excluded.queued_at,
+		started_at = NULL,
+		not_before = excluded.not_before
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		job.Queue,
+		job.OrderID,
+		job.HighPriority,
+		job.InterruptedCount,
+		job.QueuedAt,
+		job.NotBefore,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutQueuedJobRunning marks a saved job as running
+func (store *Storage) PutQueuedJobRunning(queue string, orderId int, startedAt int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		queued_jobs
+	SET
+		running = 1,
+		started_at = $1
+	WHERE
+		queue = $2
+		AND
+		order_id = $3
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		startedAt,
+		queue,
+		orderId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PutQueuedJobHighPriority makes a saved job high priority
+func (store *Storage) PutQueuedJobHighPriority(queue string, orderId int) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	query := `
+	UPDATE
+		queued_jobs
+	SET
+		high_priority = 1
+	WHERE
+		queue = $1
+		AND
+		order_id = $2
+	`
+
+	_, err = store.db.ExecContext(ctx, query,
+		queue,
+		orderId,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
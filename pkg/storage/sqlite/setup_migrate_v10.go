@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v9 to v10:
+// - acme_authorizations:
+//     - Add table and fields
+
+// migrateV9toV10 updates the storage db from user_version 9 to user_version 10, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV9toV10() (int, error) {
+	oldSchemaVer := 9
+	newSchemaVer := 10
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add table
+	query = `CREATE TABLE IF NOT EXISTS acme_authorizations (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		acme_account_id integer NOT NULL,
+		acme_location text NOT NULL UNIQUE,
+		identifier_type text NOT NULL,
+		identifier_value text NOT NULL,
+		status text NOT NULL,
+		expires integer,
+		created_at integer NOT NULL,
+		updated_at integer NOT NULL,
+		FOREIGN KEY (acme_account_id)
+			REFERENCES acme_accounts (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
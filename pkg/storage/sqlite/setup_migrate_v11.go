@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v10 to v11:
+// - certificates:
+//     - Add 'star_lifetime' field/column
+//     - Add 'star_duration' field/column
+// - acme_orders:
+//     - Add 'star_certificate_url' field/column
+//     - Add 'star_start' field/column
+//     - Add 'star_end' field/column
+//     - Add 'star_lifetime' field/column
+
+// migrateV10toV11 updates the storage db from user_version 10 to user_version 11, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV10toV11() (int, error) {
+	oldSchemaVer := 10
+	newSchemaVer := 11
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add columns
+	query = `
+		ALTER TABLE certificates ADD star_lifetime integer NOT NULL DEFAULT 0;
+		ALTER TABLE certificates ADD star_duration integer NOT NULL DEFAULT 0;
+		ALTER TABLE acme_orders ADD star_certificate_url text;
+		ALTER TABLE acme_orders ADD star_start integer;
+		ALTER TABLE acme_orders ADD star_end integer;
+		ALTER TABLE acme_orders ADD star_lifetime integer;
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
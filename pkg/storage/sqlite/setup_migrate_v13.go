@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v12 to v13:
+// - challenge_events:
+//     - Add table and fields
+
+// migrateV12toV13 updates the storage db from user_version 12 to user_version 13, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV12toV13() (int, error) {
+	oldSchemaVer := 12
+	newSchemaVer := 13
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add table
+	query = `CREATE TABLE IF NOT EXISTS challenge_events (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		attempt_id text NOT NULL,
+		provider_id integer NOT NULL,
+		provider_type text NOT NULL,
+		challenge_type text NOT NULL,
+		identifier_type text NOT NULL,
+		identifier_value text NOT NULL,
+		step text NOT NULL,
+		success integer NOT NULL CHECK(success IN (0,1)),
+		error text NOT NULL DEFAULT "",
+		started_at integer NOT NULL,
+		duration_ms integer NOT NULL
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
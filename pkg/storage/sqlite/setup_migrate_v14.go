@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v13 to v14:
+// - challenge_resources:
+//     - Add table and fields
+
+// migrateV13toV14 updates the storage db from user_version 13 to user_version 14, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV13toV14() (int, error) {
+	oldSchemaVer := 13
+	newSchemaVer := 14
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add table
+	query = `CREATE TABLE IF NOT EXISTS challenge_resources (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		provider_id integer NOT NULL,
+		provider_type text NOT NULL,
+		domain text NOT NULL,
+		token text NOT NULL,
+		key_auth text NOT NULL,
+		created_at integer NOT NULL
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v14 to v15:
+// - queued_jobs:
+//     - Add table and fields
+
+// migrateV14toV15 updates the storage db from user_version 14 to user_version 15, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV14toV15() (int, error) {
+	oldSchemaVer := 14
+	newSchemaVer := 15
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add table
+	query = `CREATE TABLE IF NOT EXISTS queued_jobs (
+		id integer PRIMARY KEY AUTOINCREMENT NOT NULL UNIQUE,
+		queue text NOT NULL,
+		order_id integer NOT NULL,
+		high_priority integer NOT NULL DEFAULT 0 CHECK(high_priority IN (0,1)),
+		running integer NOT NULL DEFAULT 0 CHECK(running IN (0,1)),
+		interrupted_count integer NOT NULL DEFAULT 0,
+		queued_at integer NOT NULL,
+		started_at integer,
+		UNIQUE (queue, order_id),
+		FOREIGN KEY (order_id)
+			REFERENCES acme_orders (id)
+				ON DELETE CASCADE
+				ON UPDATE NO ACTION
+	)`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
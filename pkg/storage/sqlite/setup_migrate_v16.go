@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v15 to v16:
+// - challenge_events:
+//     - Add 'provider_key' field/column (events from before v16 use a key made from
+//       their provider_id)
+
+// migrateV15toV16 updates the storage db from user_version 15 to user_version 16, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV15toV16() (int, error) {
+	oldSchemaVer := 15
+	newSchemaVer := 16
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add columns
+	query = `
+		ALTER TABLE challenge_events ADD provider_key text NOT NULL DEFAULT "";
+		UPDATE challenge_events SET provider_key = 'id-' || provider_id;
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v17 to v18:
+// - certificates:
+//     - Add 'star_start_offset' field/column
+//     - Add 'star_lifetime_adjust' field/column
+
+// migrateV17toV18 updates the storage db from user_version 17 to user_version 18, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV17toV18() (int, error) {
+	oldSchemaVer := 17
+	newSchemaVer := 18
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add columns
+	query = `
+		ALTER TABLE certificates ADD star_start_offset integer NOT NULL DEFAULT 0;
+		ALTER TABLE certificates ADD star_lifetime_adjust integer NOT NULL DEFAULT 0;
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+// CHANGES v7 to v8:
+// - certificates:
+//     - Add 'preferred_issuer' field/column
+// - acme_orders:
+//     - Add 'alternate_pems' field/column
+
+// migrateV7toV8 updates the storage db from user_version 7 to user_version 8, if it cannot
+// do so, an error is returned and modification is aborted
+func (store *Storage) migrateV7toV8() (int, error) {
+	oldSchemaVer := 7
+	newSchemaVer := 8
+
+	store.logger.Infof("updating database user_version from %d to %d", oldSchemaVer, newSchemaVer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), store.timeout)
+	defer cancel()
+
+	// create sql transaction to roll back in the event an error occurs
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback()
+
+	// verify correct current ver
+	query := `PRAGMA user_version`
+	row := tx.QueryRowContext(ctx, query)
+	fileUserVersion := -1
+	err = row.Scan(
+		&fileUserVersion,
+	)
+	if err != nil {
+		return -1, err
+	}
+	if fileUserVersion != oldSchemaVer {
+		return -1, fmt.Errorf("cannot update db schema, current version %d (expected %d)", fileUserVersion, oldSchemaVer)
+	}
+
+	// add columns
+	query = `
+		ALTER TABLE certificates ADD preferred_issuer text NOT NULL DEFAULT "";
+		ALTER TABLE acme_orders ADD alternate_pems text NOT NULL DEFAULT "[]";
+	`
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// update user_version
+	query = fmt.Sprintf(`
+		PRAGMA user_version = %d
+	`, newSchemaVer)
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return -1, err
+	}
+
+	// no errors, commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return -1, err
+	}
+
+	store.logger.Infof("database user_version successfully upgraded from %d to %d", oldSchemaVer, newSchemaVer)
+	return newSchemaVer, nil
+}
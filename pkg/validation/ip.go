@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"net/netip"
+)
+
+// IPAddressValid returns true if the string is a validly formatted IPv4 or IPv6
+// address. The address must be in its canonical text form (e.g. IPv6 must be
+// lower case and compressed, see: rfc5952) since rfc8738 requires ACME ip
+// identifiers to be in that form. IPv6 zones are not permitted.
+func IPAddressValid(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	// no zones
+	if addr.Zone() != "" {
+		return false
+	}
+
+	// IPv4-mapped IPv6 addresses should be specified as IPv4
+	if addr.Is4In6() {
+		return false
+	}
+
+	return addr.String() == ip
+}
+
+// IPPrefixValid returns true if the string is a validly formatted IPv4 or IPv6
+// CIDR prefix (e.g. 10.0.0.0/8) in canonical form. The address must not have any
+// bits set beyond the prefix length.
+func IPPrefixValid(prefix string) bool {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return false
+	}
+
+	// IPv4-mapped IPv6 addresses should be specified as IPv4
+	if p.Addr().Is4In6() {
+		return false
+	}
+
+	return p.Masked().String() == prefix
+}
@@ -0,0 +1,89 @@
+package validation
+
+import "testing"
+
+// valid ip addresses
+var validIPAddresses = []string{
+	"192.168.1.1",
+	"10.0.0.254",
+	"8.8.8.8",
+	"0.0.0.0",
+	"2001:db8::1",
+	"fe80::1",
+	"::1",
+	"2001:db8:0:1:1:1:1:1",
+}
+
+// invalid ip addresses
+var invalidIPAddresses = []string{
+	"",
+	"hello",
+	"example.com",
+	"192.168.1",
+	"192.168.1.256",
+	"192.168.01.1",
+	" 192.168.1.1",
+	"192.168.1.1 ",
+	"192.168.1.1/32",
+	"2001:DB8::1",
+	"2001:db8:0:0:0:0:0:1",
+	"fe80::1%eth0",
+	"::ffff:192.168.1.1",
+	"[2001:db8::1]",
+}
+
+func TestValidation_IPAddressValid(t *testing.T) {
+	// test valid ips
+	for _, ip := range validIPAddresses {
+		if !IPAddressValid(ip) {
+			t.Errorf("valid ip address test case '%s' returned invalid", ip)
+		}
+	}
+
+	// test invalid ips
+	for _, ip := range invalidIPAddresses {
+		if IPAddressValid(ip) {
+			t.Errorf("invalid ip address test case '%s' returned valid", ip)
+		}
+	}
+}
+
+// valid ip prefixes
+var validIPPrefixes = []string{
+	"10.0.0.0/8",
+	"192.168.1.0/24",
+	"192.168.1.1/32",
+	"0.0.0.0/0",
+	"2001:db8::/32",
+	"fd00::/8",
+	"::/0",
+}
+
+// invalid ip prefixes
+var invalidIPPrefixes = []string{
+	"",
+	"10.0.0.0",
+	"10.0.0.1/8",
+	"10.0.0.0/33",
+	"192.168.1.0/",
+	"2001:DB8::/32",
+	"2001:db8::1/32",
+	"::ffff:10.0.0.0/104",
+	"example.com/24",
+}
+
+func TestValidation_IPPrefixValid(t *testing.T) {
+	// test valid prefixes
+	for _, prefix := range validIPPrefixes {
+		if !IPPrefixValid(prefix) {
+			t.Errorf("valid ip prefix test case '%s' returned invalid", prefix)
+		}
+	}
+
+	// test invalid prefixes
+	for _, prefix := range invalidIPPrefixes {
+		if IPPrefixValid(prefix) {
+			t.Errorf("invalid ip prefix test case '%s' returned valid", prefix)
+		}
+	}
+}